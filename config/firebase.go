@@ -22,20 +22,44 @@ var (
 	AssessmentQuestionCache *cache.Cache
 	ScreeningQuestionCache *cache.Cache
 	TherapyQuestionCache *cache.Cache
+	RevokedTokenCache *cache.Cache
+	ChildProfileCache *cache.Cache
 	cacheExpiration = 20 * time.Minute
+	// revocationCacheExpiration bounds how long other instances keep accepting a revoked token.
+	revocationCacheExpiration = time.Minute
 )
 
-// InitFirebase initializes Firebase and Firestore clients.
-func InitFirebase() error {
-	var initErr error
-	once.Do(func() {
+// InitConfig loads the JWT signing keys and initializes the question, token revocation and child
+// profile caches.
+// Tokens are signed with the keys in JWT_KEYS_DIR; without it, they fall back to the shared
+// JWT_SECRET, which is meant for local development only.
+func InitConfig() error {
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		if err := LoadJWTKeys(dir, os.Getenv("JWT_SIGNING_KEY_ID")); err != nil {
+			return err
+		}
+	} else {
 		secret := os.Getenv("JWT_SECRET")
 		if secret == "" || len(secret) < 32 {
-			initErr = logAndReturnError("JWT_SECRET is not set or too short, minimum 32 characters required")
-			return
+			return logAndReturnError("JWT_KEYS_DIR is not set and JWT_SECRET is not set or too short, minimum 32 characters required")
 		}
 		JWTSecret = []byte(secret)
+		log.Printf("JWT_KEYS_DIR is not set, signing tokens with the shared JWT_SECRET (HS256)")
+	}
 
+	// Initialize caches with a default expiration time
+	AssessmentQuestionCache = cache.New(cacheExpiration, 10*time.Minute)
+	ScreeningQuestionCache = cache.New(cacheExpiration, 10*time.Minute)
+	TherapyQuestionCache = cache.New(cacheExpiration, 10*time.Minute)
+	RevokedTokenCache = cache.New(revocationCacheExpiration, 10*time.Minute)
+	ChildProfileCache = cache.New(cacheExpiration, 10*time.Minute)
+	return nil
+}
+
+// InitFirebase initializes Firebase and Firestore clients.
+func InitFirebase() error {
+	var initErr error
+	once.Do(func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
@@ -58,11 +82,6 @@ func InitFirebase() error {
 			initErr = logAndReturnError("Error initializing Firestore client: %v", err)
 			return
 		}
-
-        // Initialize caches with a default expiration time
-		AssessmentQuestionCache = cache.New(cacheExpiration, 10*time.Minute)
-		ScreeningQuestionCache = cache.New(cacheExpiration, 10*time.Minute)
-		TherapyQuestionCache = cache.New(cacheExpiration, 10*time.Minute)
 	})
 	return initErr
 }
@@ -0,0 +1,194 @@
+package config
+
+import (
+    "crypto"
+    "crypto/ed25519"
+    "crypto/rsa"
+    "crypto/x509"
+    "encoding/base64"
+    "encoding/pem"
+    "fmt"
+    "log"
+    "math/big"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/golang-jwt/jwt/v5"
+)
+
+// JWTKey is a key used to sign or verify issued tokens. Keys loaded from a public key file have
+// no private key and only verify tokens signed before the private key was retired.
+type JWTKey struct {
+    ID      string
+    Method  jwt.SigningMethod
+    Private crypto.Signer
+    Public  crypto.PublicKey
+}
+
+var (
+    // JWTKeys holds every verification key by key ID (kid).
+    JWTKeys map[string]*JWTKey
+    // JWTSigningKey is the key new tokens are signed with. It is nil when tokens are signed with
+    // the shared JWT_SECRET instead.
+    JWTSigningKey *JWTKey
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for signing keys.
+const minRSAKeyBits = 2048
+
+// LoadJWTKeys loads every *.pem file in dir as a verification key named after the file, so
+// 2025-05.pem and 2025-05.pub.pem both have the key ID 2025-05. Private keys may be RSA (RS256)
+// or Ed25519 (EdDSA), in PKCS#8 or PKCS#1 form; public keys are PKIX. signingKeyID selects the
+// private key new tokens are signed with and may be empty when dir holds a single private key.
+func LoadJWTKeys(dir, signingKeyID string) error {
+    paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+    if err != nil {
+        return logAndReturnError("Invalid JWT_KEYS_DIR %s: %v", dir, err)
+    }
+    sort.Strings(paths)
+
+    keys := make(map[string]*JWTKey, len(paths))
+    var privateKeyIDs []string
+    for _, path := range paths {
+        key, err := loadJWTKey(path)
+        if err != nil {
+            return logAndReturnError("Failed to load JWT key %s: %v", path, err)
+        }
+        if _, ok := keys[key.ID]; ok {
+            return logAndReturnError("Duplicate JWT key ID %s in %s", key.ID, dir)
+        }
+        keys[key.ID] = key
+        if key.Private != nil {
+            privateKeyIDs = append(privateKeyIDs, key.ID)
+        }
+    }
+
+    if signingKeyID == "" {
+        if len(privateKeyIDs) != 1 {
+            return logAndReturnError("JWT_SIGNING_KEY_ID must be set when JWT_KEYS_DIR holds %d private keys", len(privateKeyIDs))
+        }
+        signingKeyID = privateKeyIDs[0]
+    }
+    signingKey, ok := keys[signingKeyID]
+    if !ok || signingKey.Private == nil {
+        return logAndReturnError("JWT signing key %s has no private key in %s", signingKeyID, dir)
+    }
+
+    JWTKeys = keys
+    JWTSigningKey = signingKey
+    log.Printf("Loaded %d JWT verification keys, signing with %s (%s)", len(keys), signingKey.ID, signingKey.Method.Alg())
+    return nil
+}
+
+// loadJWTKey parses a PEM-encoded private or public key file.
+func loadJWTKey(path string) (*JWTKey, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    block, _ := pem.Decode(data)
+    if block == nil {
+        return nil, fmt.Errorf("no PEM block found")
+    }
+
+    id := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".pem"), ".pub")
+    key := &JWTKey{ID: id}
+    var parsed interface{}
+    switch block.Type {
+    case "PRIVATE KEY":
+        parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+    case "RSA PRIVATE KEY":
+        parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+    case "PUBLIC KEY":
+        parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+    default:
+        return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    switch k := parsed.(type) {
+    case *rsa.PrivateKey:
+        key.Private, key.Public = k, &k.PublicKey
+    case ed25519.PrivateKey:
+        key.Private, key.Public = k, k.Public()
+    case *rsa.PublicKey, ed25519.PublicKey:
+        key.Public = k
+    default:
+        return nil, fmt.Errorf("unsupported key type %T, expected RSA or Ed25519", parsed)
+    }
+
+    switch pub := key.Public.(type) {
+    case *rsa.PublicKey:
+        if pub.N.BitLen() < minRSAKeyBits {
+            return nil, fmt.Errorf("RSA key is %d bits, at least %d required", pub.N.BitLen(), minRSAKeyBits)
+        }
+        key.Method = jwt.SigningMethodRS256
+    case ed25519.PublicKey:
+        key.Method = jwt.SigningMethodEdDSA
+    }
+    return key, nil
+}
+
+// SignJWT signs claims with the active signing key, adding its key ID as the kid header. Without
+// a signing key, tokens are signed with the shared JWT_SECRET using HS256.
+func SignJWT(claims jwt.MapClaims) (string, error) {
+    if JWTSigningKey == nil {
+        return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(JWTSecret)
+    }
+    token := jwt.NewWithClaims(JWTSigningKey.Method, claims)
+    token.Header["kid"] = JWTSigningKey.ID
+    return token.SignedString(JWTSigningKey.Private)
+}
+
+// JWTKeyFunc returns the key that verifies token, matching its kid header against the loaded
+// keys. HS256 tokens are only accepted while no asymmetric keys are configured.
+func JWTKeyFunc(token *jwt.Token) (interface{}, error) {
+    if JWTSigningKey == nil {
+        if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+            return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+        }
+        return JWTSecret, nil
+    }
+
+    kid, _ := token.Header["kid"].(string)
+    key, ok := JWTKeys[kid]
+    if !ok {
+        return nil, fmt.Errorf("unknown key ID %q", kid)
+    }
+    if token.Method.Alg() != key.Method.Alg() {
+        return nil, fmt.Errorf("signing method %s does not match key %s", token.Method.Alg(), kid)
+    }
+    return key.Public, nil
+}
+
+// JWKS returns the public half of every verification key as a JSON Web Key Set.
+func JWKS() models.JWKSet {
+    ids := make([]string, 0, len(JWTKeys))
+    for id := range JWTKeys {
+        ids = append(ids, id)
+    }
+    sort.Strings(ids)
+
+    set := models.JWKSet{Keys: make([]models.JWK, 0, len(ids))}
+    for _, id := range ids {
+        key := JWTKeys[id]
+        jwk := models.JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+        switch pub := key.Public.(type) {
+        case *rsa.PublicKey:
+            jwk.Kty = "RSA"
+            jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+            jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+        case ed25519.PublicKey:
+            jwk.Kty = "OKP"
+            jwk.Crv = "Ed25519"
+            jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+        }
+        set.Keys = append(set.Keys, jwk)
+    }
+    return set
+}
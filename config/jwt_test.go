@@ -0,0 +1,177 @@
+package config
+
+import (
+    "crypto/ed25519"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/x509"
+    "encoding/pem"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/golang-jwt/jwt/v5"
+)
+
+// useJWTKeys restores the loaded JWT keys when the test ends.
+func useJWTKeys(t *testing.T) {
+    t.Helper()
+    keys, signingKey, secret := JWTKeys, JWTSigningKey, JWTSecret
+    t.Cleanup(func() { JWTKeys, JWTSigningKey, JWTSecret = keys, signingKey, secret })
+}
+
+// writePEM writes a PEM block of the given type to name in dir.
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) {
+    t.Helper()
+    data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+    if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+        t.Fatalf("WriteFile() error = %v", err)
+    }
+}
+
+// writeRSAKey writes an RSA private key of bits to name in dir in PKCS#1 form.
+func writeRSAKey(t *testing.T, dir, name string, bits int) {
+    t.Helper()
+    key, err := rsa.GenerateKey(rand.Reader, bits)
+    if err != nil {
+        t.Fatalf("GenerateKey() error = %v", err)
+    }
+    writePEM(t, dir, name, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+}
+
+// writeEd25519Key writes an Ed25519 private key to name in dir in PKCS#8 form and returns it.
+func writeEd25519Key(t *testing.T, dir, name string) ed25519.PrivateKey {
+    t.Helper()
+    _, key, err := ed25519.GenerateKey(rand.Reader)
+    if err != nil {
+        t.Fatalf("GenerateKey() error = %v", err)
+    }
+    der, err := x509.MarshalPKCS8PrivateKey(key)
+    if err != nil {
+        t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+    }
+    writePEM(t, dir, name, "PRIVATE KEY", der)
+    return key
+}
+
+func TestLoadJWTKeys(t *testing.T) {
+    useJWTKeys(t)
+    dir := t.TempDir()
+    writeRSAKey(t, dir, "2025-04.pem", 2048)
+    current := writeEd25519Key(t, dir, "2025-05.pem")
+    retired, err := x509.MarshalPKIXPublicKey(writeEd25519Key(t, t.TempDir(), "old.pem").Public())
+    if err != nil {
+        t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+    }
+    writePEM(t, dir, "2025-03.pub.pem", "PUBLIC KEY", retired)
+
+    if err := LoadJWTKeys(dir, ""); err == nil {
+        t.Error("LoadJWTKeys() with two private keys and no signing key ID succeeded")
+    }
+    if err := LoadJWTKeys(dir, "2025-03"); err == nil {
+        t.Error("LoadJWTKeys() signing with a public-only key succeeded")
+    }
+    if err := LoadJWTKeys(dir, "2025-05"); err != nil {
+        t.Fatalf("LoadJWTKeys() error = %v", err)
+    }
+
+    if len(JWTKeys) != 3 {
+        t.Errorf("loaded %d keys, want 3", len(JWTKeys))
+    }
+    if JWTSigningKey.ID != "2025-05" || JWTSigningKey.Method != jwt.SigningMethodEdDSA {
+        t.Errorf("signing key = %s (%s), want 2025-05 (EdDSA)", JWTSigningKey.ID, JWTSigningKey.Method.Alg())
+    }
+    if !current.Public().(ed25519.PublicKey).Equal(JWTSigningKey.Public) {
+        t.Error("signing key does not hold the public half of 2025-05.pem")
+    }
+    if JWTKeys["2025-03"].Private != nil || JWTKeys["2025-04"].Method != jwt.SigningMethodRS256 {
+        t.Errorf("keys = %+v, want a public-only 2025-03 and an RS256 2025-04", JWTKeys)
+    }
+}
+
+func TestLoadJWTKeysRejectsWeakKeys(t *testing.T) {
+    useJWTKeys(t)
+    dir := t.TempDir()
+    writeRSAKey(t, dir, "weak.pem", 1024)
+
+    if err := LoadJWTKeys(dir, "weak"); err == nil {
+        t.Error("LoadJWTKeys() accepted a 1024-bit RSA key")
+    }
+}
+
+func TestJWTKeyFunc(t *testing.T) {
+    useJWTKeys(t)
+    dir := t.TempDir()
+    writeRSAKey(t, dir, "rsa.pem", 2048)
+    writeEd25519Key(t, dir, "ed.pem")
+    if err := LoadJWTKeys(dir, "rsa"); err != nil {
+        t.Fatalf("LoadJWTKeys() error = %v", err)
+    }
+
+    signed, err := SignJWT(jwt.MapClaims{"uid": "user"})
+    if err != nil {
+        t.Fatalf("SignJWT() error = %v", err)
+    }
+    token, err := jwt.Parse(signed, JWTKeyFunc)
+    if err != nil || token.Header["kid"] != "rsa" {
+        t.Fatalf("Parse() = kid %v, %v, want a valid token signed by rsa", token.Header["kid"], err)
+    }
+
+    // A token keeps verifying after the signing key changes, as long as its kid is loaded.
+    JWTSigningKey = JWTKeys["ed"]
+    if _, err := jwt.Parse(signed, JWTKeyFunc); err != nil {
+        t.Errorf("Parse() after rotation error = %v", err)
+    }
+
+    tests := []struct {
+        name   string
+        method jwt.SigningMethod
+        kid    interface{}
+        key    interface{}
+    }{
+        {"unknown kid", jwt.SigningMethodEdDSA, "missing", JWTKeys["ed"].Private},
+        {"missing kid", jwt.SigningMethodEdDSA, nil, JWTKeys["ed"].Private},
+        {"method does not match kid", jwt.SigningMethodEdDSA, "rsa", JWTKeys["ed"].Private},
+        {"shared secret", jwt.SigningMethodHS256, "rsa", []byte("a-shared-secret-of-32-characters!")},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            token := jwt.NewWithClaims(tt.method, jwt.MapClaims{"uid": "user"})
+            if tt.kid != nil {
+                token.Header["kid"] = tt.kid
+            }
+            signed, err := token.SignedString(tt.key)
+            if err != nil {
+                t.Fatalf("SignedString() error = %v", err)
+            }
+            if _, err := jwt.Parse(signed, JWTKeyFunc); err == nil {
+                t.Error("Parse() accepted the token")
+            }
+        })
+    }
+}
+
+func TestJWKS(t *testing.T) {
+    useJWTKeys(t)
+    dir := t.TempDir()
+    writeRSAKey(t, dir, "b-rsa.pem", 2048)
+    writeEd25519Key(t, dir, "a-ed.pem")
+    if err := LoadJWTKeys(dir, "b-rsa"); err != nil {
+        t.Fatalf("LoadJWTKeys() error = %v", err)
+    }
+
+    set := JWKS()
+    if len(set.Keys) != 2 {
+        t.Fatalf("JWKS() has %d keys, want 2", len(set.Keys))
+    }
+    ed, rsaJWK := set.Keys[0], set.Keys[1]
+    if ed.Kid != "a-ed" || ed.Kty != "OKP" || ed.Crv != "Ed25519" || ed.Alg != "EdDSA" || ed.X == "" {
+        t.Errorf("Ed25519 key = %+v", ed)
+    }
+    if rsaJWK.Kid != "b-rsa" || rsaJWK.Kty != "RSA" || rsaJWK.Alg != "RS256" || rsaJWK.E != "AQAB" || rsaJWK.Use != "sig" {
+        t.Errorf("RSA key = %+v", rsaJWK)
+    }
+    if len(rsaJWK.N) != 342 {
+        t.Errorf("RSA modulus is %d base64url characters, want 342 for 2048 bits", len(rsaJWK.N))
+    }
+}
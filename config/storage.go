@@ -0,0 +1,38 @@
+package config
+
+import (
+    "os"
+    "strings"
+)
+
+// Storage backends selectable with the STORAGE_BACKEND environment variable.
+const (
+    StorageFirestore = "firestore"
+    StorageMemory    = "memory"
+)
+
+// StorageBackend returns the configured storage backend, defaulting to Firestore.
+func StorageBackend() string {
+    backend := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_BACKEND")))
+    if backend == "" {
+        return StorageFirestore
+    }
+    return backend
+}
+
+// AdminUserIDs returns the user IDs listed in ADMIN_USER_IDS, used to seed admins for in-memory storage.
+func AdminUserIDs() []string {
+    var ids []string
+    for _, id := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+        if id = strings.TrimSpace(id); id != "" {
+            ids = append(ids, id)
+        }
+    }
+    return ids
+}
+
+// LocalAuthEnabled reports whether ALLOW_LOCAL_AUTH=true enables the dev-only local auth type,
+// which signs in as any user ID without credentials. It is off unless explicitly enabled.
+func LocalAuthEnabled() bool {
+    return strings.EqualFold(strings.TrimSpace(os.Getenv("ALLOW_LOCAL_AUTH")), "true")
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+
+    "github.com/dzuura/neurodyx-be/middleware"
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/services"
+)
+
+// GetAchievementsHandler lists every badge with the user's progress toward it.
+func GetAchievementsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    badges, err := services.ListAchievements(r.Context(), userID)
+    if err != nil {
+        log.Printf("Error retrieving achievements for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve achievements: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "achievements": badges,
+    })
+}
+
+// evaluateAchievements unlocks the badges earned by a submission and returns them. Failures are
+// logged rather than failing the submission, which has already been saved.
+func evaluateAchievements(r *http.Request, userID string, therapyResults []models.TherapyResult) []models.Achievement {
+    achievements, err := services.EvaluateAchievements(r.Context(), userID, therapyResults)
+    if err != nil {
+        log.Printf("Error evaluating achievements for userID %s: %v", userID, err)
+    }
+    if achievements == nil {
+        achievements = []models.Achievement{}
+    }
+    return achievements
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+    "encoding/json"
+    "errors"
+    "log"
+    "net/http"
+
+    "github.com/gorilla/mux"
+    "github.com/dzuura/neurodyx-be/middleware"
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/services"
+)
+
+// RebuildQuestionIndexHandler regenerates the question ID index used to resolve submissions and
+// lists the malformed question documents it skipped.
+func RebuildQuestionIndexHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    rebuild, err := services.RebuildQuestionIndex(r.Context())
+    if err != nil {
+        log.Printf("Error rebuilding question index: %v", err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to rebuild question index: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(rebuild)
+}
+
+// SetUserDailyGoalHandler replaces any user's daily therapy goal on behalf of a user granted
+// user-goals:manage. Guardians set a child's goal through the child profile instead.
+func SetUserDailyGoalHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    adminID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    updateDailyGoal(w, r, mux.Vars(r)["userID"], adminID)
+}
+
+// SetAssignedLearnerGoalHandler replaces the daily therapy goal of a learner on behalf of a
+// therapist granted assigned-goals:manage, provided an admin assigned the learner to them.
+func SetAssignedLearnerGoalHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    therapistID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    userID := mux.Vars(r)["userID"]
+    assigned, err := services.IsAssignedTherapist(r.Context(), therapistID, userID)
+    if err != nil {
+        log.Printf("Error checking therapists of userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to check assigned therapists: " + err.Error()})
+        return
+    }
+    if !assigned {
+        log.Printf("Therapist %s is not assigned to userID %s", therapistID, userID)
+        w.WriteHeader(http.StatusForbidden)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Learner is not assigned to this therapist"})
+        return
+    }
+
+    updateDailyGoal(w, r, userID, therapistID)
+}
+
+// GetUserRolesHandler retrieves the roles of a user.
+func GetUserRolesHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID := mux.Vars(r)["userID"]
+    roles, err := services.GetUserRoles(r.Context(), userID)
+    if err != nil {
+        log.Printf("Error retrieving roles for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve roles: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(models.UserRoles{UserID: userID, Roles: roles})
+}
+
+// SetUserRolesHandler replaces the roles of a user. The user's access tokens keep their old roles
+// until they are refreshed.
+func SetUserRolesHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    adminID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    var req models.UserRoles
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+        return
+    }
+
+    userID := mux.Vars(r)["userID"]
+    roles, err := services.SetUserRoles(r.Context(), userID, req.Roles, adminID)
+    if errors.Is(err, services.ErrInvalidRole) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
+    if err != nil {
+        log.Printf("Error setting roles for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to set roles: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(models.UserRoles{UserID: userID, Roles: roles})
+}
+
+// GetUserTherapistsHandler retrieves the therapists assigned to a learner.
+func GetUserTherapistsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID := mux.Vars(r)["userID"]
+    therapistIDs, err := services.GetUserTherapists(r.Context(), userID)
+    if err != nil {
+        log.Printf("Error retrieving therapists for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve therapists: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(models.LearnerTherapists{UserID: userID, TherapistIDs: therapistIDs})
+}
+
+// SetUserTherapistsHandler replaces the therapists assigned to a learner. Each therapist may then
+// manage the learner's daily goal.
+func SetUserTherapistsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    adminID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    var req models.LearnerTherapists
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+        return
+    }
+
+    userID := mux.Vars(r)["userID"]
+    therapistIDs, err := services.SetUserTherapists(r.Context(), userID, req.TherapistIDs, adminID)
+    if errors.Is(err, services.ErrNotTherapist) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
+    if err != nil {
+        log.Printf("Error setting therapists for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to set therapists: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(models.LearnerTherapists{UserID: userID, TherapistIDs: therapistIDs})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/middleware"
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+    "github.com/dzuura/neurodyx-be/services"
+    "github.com/gorilla/mux"
+)
+
+func TestSetAssignedLearnerGoalHandler(t *testing.T) {
+    services.SetRepository(repository.NewMemoryRepository())
+    ctx := context.Background()
+    if _, err := services.SetUserRoles(ctx, "therapist", []string{models.RoleTherapist}, "admin"); err != nil {
+        t.Fatalf("SetUserRoles() error = %v", err)
+    }
+    if _, err := services.SetUserTherapists(ctx, "learner", []string{"therapist"}, "admin"); err != nil {
+        t.Fatalf("SetUserTherapists() error = %v", err)
+    }
+
+    tests := []struct {
+        name       string
+        learnerID  string
+        body       string
+        wantStatus int
+    }{
+        {"assigned learner", "learner", `{"metric":"correctAnswers","target":5}`, http.StatusOK},
+        {"unassigned learner", "other-learner", `{"metric":"correctAnswers","target":5}`, http.StatusForbidden},
+        {"invalid goal", "learner", `{"metric":"correctAnswers","target":0}`, http.StatusBadRequest},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            req := httptest.NewRequest(http.MethodPut, "/api/therapist/learners/"+tt.learnerID+"/goal", strings.NewReader(tt.body))
+            req = mux.SetURLVars(req, map[string]string{"userID": tt.learnerID})
+            req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, "therapist"))
+            rec := httptest.NewRecorder()
+            SetAssignedLearnerGoalHandler(rec, req)
+            if rec.Code != tt.wantStatus {
+                t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+            }
+        })
+    }
+
+    goal, err := services.GetDailyGoal(ctx, "learner")
+    if err != nil {
+        t.Fatalf("GetDailyGoal() error = %v", err)
+    }
+    if goal.Target != 5 || goal.SetBy != "therapist" {
+        t.Errorf("goal = %+v, want target 5 set by therapist", goal)
+    }
+    if goal, _ := services.GetDailyGoal(ctx, "other-learner"); goal.SetBy == "therapist" {
+        t.Error("the unassigned learner's goal was changed")
+    }
+}
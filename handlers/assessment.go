@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/dzuura/neurodyx-be/config"
 	"github.com/dzuura/neurodyx-be/middleware"
 	"github.com/dzuura/neurodyx-be/models"
+	"github.com/dzuura/neurodyx-be/repository"
 	"github.com/dzuura/neurodyx-be/services"
 )
 
@@ -44,6 +46,11 @@ func AddAssessmentQuestionHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     questionID, err := services.SaveAssessmentQuestion(r.Context(), question, userID)
+    if errors.Is(err, services.ErrInvalidQuestion) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
     if err != nil {
         log.Printf("Error saving assessment question: %v", err)
         w.WriteHeader(http.StatusInternalServerError)
@@ -132,43 +139,19 @@ func UpdateAssessmentQuestionHandler(w http.ResponseWriter, r *http.Request) {
         }
     }
 
-    firestoreClient, err := config.App.Firestore(r.Context())
-    if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to connect to Firestore"})
-        return
-    }
-    defer firestoreClient.Close()
-
-    var originalType, originalCategory string
-    types := []string{"visual", "auditory", "kinesthetic", "tactile"}
-    found := false
-    for _, t := range types {
-        categoriesIter := firestoreClient.Collection("assessmentQuestions").Doc(t).Collections(r.Context())
-        categories, err := categoriesIter.GetAll()
-        if err != nil {
-            log.Printf("Failed to retrieve categories for type %s: %v", t, err)
-            continue
-        }
-        for _, category := range categories {
-            doc, err := category.Doc(questionID).Get(r.Context())
-            if err == nil && doc.Exists() {
-                originalType = t
-                originalCategory = category.ID
-                found = true
-                break
-            }
-        }
-        if found {
-            break
-        }
-    }
-
-    if !found {
+    existing, err := services.FindAssessmentQuestion(r.Context(), questionID)
+    if errors.Is(err, repository.ErrNotFound) {
         w.WriteHeader(http.StatusNotFound)
         json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Assessment question not found"})
         return
     }
+    if err != nil {
+        log.Printf("Error looking up assessment question %s: %v", questionID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve assessment question: " + err.Error()})
+        return
+    }
+    originalType, originalCategory := existing.Type, existing.Category
 
     if question.Type != originalType || question.Category != originalCategory {
         w.WriteHeader(http.StatusBadRequest)
@@ -177,6 +160,11 @@ func UpdateAssessmentQuestionHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     err = services.UpdateAssessmentQuestion(r.Context(), questionID, question, userID)
+    if errors.Is(err, services.ErrInvalidQuestion) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
     if err != nil {
         log.Printf("Error updating assessment question %s: %v", questionID, err)
         w.WriteHeader(http.StatusInternalServerError)
@@ -216,43 +204,19 @@ func DeleteAssessmentQuestionHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    firestoreClient, err := config.App.Firestore(r.Context())
-    if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to connect to Firestore"})
-        return
-    }
-    defer firestoreClient.Close()
-
-    var questionType, category string
-    types := []string{"visual", "auditory", "kinesthetic", "tactile"}
-    found := false
-    for _, t := range types {
-        categoriesIter := firestoreClient.Collection("assessmentQuestions").Doc(t).Collections(r.Context())
-        categories, err := categoriesIter.GetAll()
-        if err != nil {
-            log.Printf("Failed to retrieve categories for type %s: %v", t, err)
-            continue
-        }
-        for _, cat := range categories {
-            doc, err := cat.Doc(questionID).Get(r.Context())
-            if err == nil && doc.Exists() {
-                questionType = t
-                category = cat.ID
-                found = true
-                break
-            }
-        }
-        if found {
-            break
-        }
-    }
-
-    if !found {
+    existing, err := services.FindAssessmentQuestion(r.Context(), questionID)
+    if errors.Is(err, repository.ErrNotFound) {
         w.WriteHeader(http.StatusNotFound)
         json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Assessment question not found"})
         return
     }
+    if err != nil {
+        log.Printf("Error looking up assessment question %s: %v", questionID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve assessment question: " + err.Error()})
+        return
+    }
+    questionType, category := existing.Type, existing.Category
 
     err = services.DeleteAssessmentQuestion(r.Context(), questionID, questionType, category, userID)
     if err != nil {
@@ -296,29 +260,19 @@ func SubmitAnswerHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    totalCorrect := 0
-    typeMap := make(map[string]int)
-    for _, sub := range submission.Submissions {
-        result, err := services.SaveAssessmentResult(r.Context(), userID, sub, "")
-        if err != nil {
-            log.Printf("Error submitting answer for question %s: %v", sub.QuestionID, err)
-            continue
-        }
-        totalCorrect += result.CorrectAnswers
-        typeMap[submission.Type] = typeMap[submission.Type] + result.CorrectAnswers
-    }
-
-    result := models.AssessmentResult{
-        Type:           submission.Type,
-        CorrectAnswers: totalCorrect,
-        TotalQuestions: len(submission.Submissions),
-        Status:         "completed",
+    result, err := services.SubmitAssessmentAnswers(r.Context(), userID, submission.Type, submission.Submissions)
+    if err != nil {
+        log.Printf("Error submitting assessment answers: %v", err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to submit answers: " + err.Error()})
+        return
     }
+    achievements := evaluateAchievements(r, userID, nil)
 
-    log.Printf("Successfully processed %d submissions for userID: %s, type: %s, correct: %d", len(submission.Submissions), userID, submission.Type, totalCorrect)
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(map[string]interface{}{
-        "result": result,
+        "result":       result,
+        "achievements": achievements,
     })
 }
 
@@ -343,4 +297,27 @@ func GetAssessmentResultsHandler(w http.ResponseWriter, r *http.Request) {
 
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(results)
+}
+
+// GetLearningProfileHandler computes and returns the user's learning-style profile.
+func GetLearningProfileHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    profile, err := services.ComputeLearningProfile(r.Context(), userID)
+    if err != nil {
+        log.Printf("Error computing learning profile: %v", err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to compute learning profile: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(profile)
 }
\ No newline at end of file
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+    "encoding/json"
+    "errors"
+    "log"
+    "net/http"
+
+    "github.com/gorilla/mux"
+    "github.com/dzuura/neurodyx-be/middleware"
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+    "github.com/dzuura/neurodyx-be/services"
+)
+
+// ListAssessmentAttemptsHandler lists the user's assessment attempts, optionally filtered by type.
+func ListAssessmentAttemptsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    attempts, err := services.ListAssessmentAttempts(r.Context(), userID, r.URL.Query().Get("type"))
+    if err != nil {
+        writeAttemptError(w, err, "retrieve assessment attempts")
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "attempts": attempts,
+    })
+}
+
+// GetAssessmentAttemptHandler retrieves a single assessment attempt.
+func GetAssessmentAttemptHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    attempt, err := services.GetAssessmentAttempt(r.Context(), userID, mux.Vars(r)["attemptID"])
+    if err != nil {
+        writeAttemptError(w, err, "retrieve assessment attempt")
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(attempt)
+}
+
+// DiffAssessmentAttemptsHandler compares two assessment attempts of the same type.
+func DiffAssessmentAttemptsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    from := r.URL.Query().Get("from")
+    to := r.URL.Query().Get("to")
+    if from == "" || to == "" {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Missing required query parameters: from, to"})
+        return
+    }
+
+    diff, err := services.DiffAssessmentAttempts(r.Context(), userID, from, to)
+    if err != nil {
+        writeAttemptError(w, err, "diff assessment attempts")
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(diff)
+}
+
+// writeAttemptError maps assessment attempt errors to HTTP responses.
+func writeAttemptError(w http.ResponseWriter, err error, action string) {
+    status := http.StatusInternalServerError
+    switch {
+    case errors.Is(err, repository.ErrNotFound):
+        status = http.StatusNotFound
+        err = errors.New("assessment attempt not found")
+    case errors.Is(err, services.ErrInvalidQuestionType), errors.Is(err, services.ErrAttemptTypeMismatch):
+        status = http.StatusBadRequest
+    default:
+        log.Printf("Failed to %s: %v", action, err)
+    }
+
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to " + action + ": " + err.Error()})
+}
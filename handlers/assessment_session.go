@@ -0,0 +1,188 @@
+package handlers
+
+import (
+    "encoding/json"
+    "errors"
+    "log"
+    "net/http"
+
+    "github.com/gorilla/mux"
+    "github.com/dzuura/neurodyx-be/middleware"
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+    "github.com/dzuura/neurodyx-be/services"
+)
+
+// StartAssessmentSessionHandler starts an assessment session for a type, or resumes the user's
+// in-progress session for that type.
+func StartAssessmentSessionHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    var request struct {
+        Type string `json:"type"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+        return
+    }
+    if request.Type == "" {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Missing required field: type"})
+        return
+    }
+
+    session, resumed, err := services.StartAssessmentSession(r.Context(), userID, request.Type)
+    if err != nil {
+        writeSessionError(w, err, "start assessment session")
+        return
+    }
+
+    if resumed {
+        w.WriteHeader(http.StatusOK)
+    } else {
+        w.WriteHeader(http.StatusCreated)
+    }
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "session": session,
+        "resumed": resumed,
+    })
+}
+
+// GetAssessmentSessionHandler retrieves the state of an assessment session.
+func GetAssessmentSessionHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    session, err := services.GetAssessmentSession(r.Context(), userID, mux.Vars(r)["sessionID"])
+    if err != nil {
+        writeSessionError(w, err, "retrieve assessment session")
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(session)
+}
+
+// NextAssessmentQuestionHandler returns the next unanswered question of an assessment session.
+func NextAssessmentQuestionHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    question, remaining, err := services.NextAssessmentQuestion(r.Context(), userID, mux.Vars(r)["sessionID"])
+    if err != nil {
+        writeSessionError(w, err, "retrieve next question")
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "question":  question,
+        "remaining": remaining,
+    })
+}
+
+// SubmitAssessmentSessionHandler scores answers to questions of an assessment session.
+func SubmitAssessmentSessionHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    var request struct {
+        Submissions []models.AssessmentSubmission `json:"submissions"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+        return
+    }
+    if len(request.Submissions) == 0 || len(request.Submissions) > 100 {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Submissions cannot be empty or exceed 100"})
+        return
+    }
+
+    session, answers, err := services.SubmitAssessmentSessionAnswers(r.Context(), userID, mux.Vars(r)["sessionID"], request.Submissions)
+    if err != nil {
+        writeSessionError(w, err, "submit answers")
+        return
+    }
+    achievements := evaluateAchievements(r, userID, nil)
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "session":      session,
+        "answers":      answers,
+        "achievements": achievements,
+    })
+}
+
+// FinishAssessmentSessionHandler finishes an assessment session.
+func FinishAssessmentSessionHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    session, err := services.FinishAssessmentSession(r.Context(), userID, mux.Vars(r)["sessionID"])
+    if err != nil {
+        writeSessionError(w, err, "finish assessment session")
+        return
+    }
+    achievements := evaluateAchievements(r, userID, nil)
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "session":      session,
+        "achievements": achievements,
+    })
+}
+
+// writeSessionError maps assessment session errors to HTTP responses.
+func writeSessionError(w http.ResponseWriter, err error, action string) {
+    status := http.StatusInternalServerError
+    switch {
+    case errors.Is(err, repository.ErrNotFound):
+        status = http.StatusNotFound
+        err = errors.New("assessment session not found")
+    case errors.Is(err, services.ErrNoQuestions):
+        status = http.StatusNotFound
+    case errors.Is(err, services.ErrInvalidQuestionType), errors.Is(err, services.ErrQuestionNotInSession):
+        status = http.StatusBadRequest
+    case errors.Is(err, services.ErrSessionFinished):
+        status = http.StatusConflict
+    default:
+        log.Printf("Failed to %s: %v", action, err)
+    }
+
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to " + action + ": " + err.Error()})
+}
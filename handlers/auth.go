@@ -3,33 +3,75 @@ package handlers
 import (
     "context"
     "encoding/json"
-    "fmt"
+    "errors"
     "log"
     "net/http"
     "os"
     "sync"
     "time"
 
-    "cloud.google.com/go/firestore"
     "github.com/golang-jwt/jwt/v5"
+    "github.com/gorilla/mux"
     "github.com/dzuura/neurodyx-be/config"
+    "github.com/dzuura/neurodyx-be/middleware"
     "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+    "github.com/dzuura/neurodyx-be/services"
     "google.golang.org/api/idtoken"
-    "google.golang.org/grpc/codes"
-    "google.golang.org/grpc/status"
 )
 
 var (
     tokenCache = sync.Map{}
 )
 
-// generateToken creates a JWT token with the specified user ID and expiry.
-func generateToken(uid string, expiry time.Duration) (string, error) {
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// generateToken creates a JWT token with the specified user ID, auth session, token ID and expiry.
+// tokenType is empty for access tokens and middleware.RefreshTokenType for refresh tokens, whose
+// token ID identifies them within the session's refresh-token family. Access tokens carry the
+// user's roles, which refresh tokens do not, so roles are read again on every refresh.
+func generateToken(uid, sessionID, tokenID, tokenType string, roles []string, expiry time.Duration) (string, error) {
+    claims := jwt.MapClaims{
         "uid": uid,
+        "sid": sessionID,
+        "jti": tokenID,
         "exp": time.Now().Add(expiry).Unix(),
+    }
+    if tokenType != "" {
+        claims["typ"] = tokenType
+    }
+    if roles != nil {
+        claims["roles"] = roles
+    }
+    return config.SignJWT(claims)
+}
+
+// writeSessionTokens issues an access token carrying roles and a refresh token for the session.
+func writeSessionTokens(w http.ResponseWriter, session models.AuthSession, roles []string) {
+    accessTokenID, err := services.NewTokenID()
+    if err != nil {
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error generating access token"})
+        return
+    }
+    accessToken, err := generateToken(session.UserID, session.ID, accessTokenID, "", roles, services.AccessTokenTTL)
+    if err != nil {
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error generating access token"})
+        return
+    }
+
+    refreshToken, err := generateToken(session.UserID, session.ID, session.TokenID, middleware.RefreshTokenType, nil, time.Until(session.ExpiresAt))
+    if err != nil {
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error generating refresh token"})
+        return
+    }
+
+    json.NewEncoder(w).Encode(models.AuthResponse{
+        Token:        accessToken,
+        RefreshToken: refreshToken,
+        SessionID:    session.ID,
+        Roles:        roles,
     })
-    return token.SignedString(config.JWTSecret)
 }
 
 // AuthHandler authenticates users and issues access and refresh tokens.
@@ -43,7 +85,8 @@ func AuthHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    if req.Token == "" || (req.AuthType != "firebase" && req.AuthType != "google") {
+    localAuth := req.AuthType == "local" && config.LocalAuthEnabled()
+    if req.Token == "" || (req.AuthType != "firebase" && req.AuthType != "google" && !localAuth) {
         w.WriteHeader(http.StatusBadRequest)
         json.NewEncoder(w).Encode(models.AuthResponse{Error: "Missing or invalid auth type or token"})
         return
@@ -53,10 +96,18 @@ func AuthHandler(w http.ResponseWriter, r *http.Request) {
     defer cancel()
 
     var token *idtoken.Payload
-    if cached, ok := tokenCache.Load(req.Token); ok {
+    if localAuth {
+        // Local auth is a dev-only shortcut without an identity provider, so the token is used directly as the user ID.
+        token = &idtoken.Payload{Subject: req.Token, Claims: map[string]interface{}{}}
+    } else if cached, ok := tokenCache.Load(req.Token); ok {
         token = cached.(*idtoken.Payload)
     } else {
         if req.AuthType == "firebase" {
+            if config.App == nil {
+                w.WriteHeader(http.StatusInternalServerError)
+                json.NewEncoder(w).Encode(models.AuthResponse{Error: "Authentication service unavailable"})
+                return
+            }
             client, err := config.App.Auth(ctx)
             if err != nil {
                 w.WriteHeader(http.StatusInternalServerError)
@@ -87,19 +138,6 @@ func AuthHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     uid := token.Subject
-    accessToken, err := generateToken(uid, time.Hour*24)
-    if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error generating access token"})
-        return
-    }
-
-    refreshToken, err := generateToken(uid, time.Hour*24*30)
-    if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error generating refresh token"})
-        return
-    }
 
     email := ""
     username := ""
@@ -111,8 +149,8 @@ func AuthHandler(w http.ResponseWriter, r *http.Request) {
         username = name
     }
 
-    _, err = config.FirestoreClient.Collection("users").Doc(uid).Get(ctx)
-    isNewUser := status.Code(err) == codes.NotFound
+    user, err := services.GetUser(ctx, uid)
+    isNewUser := errors.Is(err, repository.ErrNotFound)
     if err != nil && !isNewUser {
         log.Printf("Error checking user existence: %v", err)
         w.WriteHeader(http.StatusInternalServerError)
@@ -120,30 +158,33 @@ func AuthHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    userData := map[string]interface{}{
-        "refreshToken":         refreshToken,
-        "refreshTokenCreatedAt": time.Now(),
-        "refreshTokenExpiresAt": time.Now().Add(time.Hour * 24 * 30),
-        "email":                email,
-        "username":             username,
+    user.ID = uid
+    user.Email = email
+    user.Username = username
+    if isNewUser {
+        user.CreatedAt = time.Now()
     }
 
-    if isNewUser {
-        userData["createdAt"] = time.Now()
+    if err := services.SaveUser(ctx, user); err != nil {
+        log.Printf("Error saving user data: %v", err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error saving user data"})
+        return
     }
 
-    _, err = config.FirestoreClient.Collection("users").Doc(uid).Set(ctx, userData, firestore.MergeAll)
+    device := req.Device
+    if device == "" {
+        device = r.UserAgent()
+    }
+    session, err := services.StartAuthSession(ctx, uid, device)
     if err != nil {
-        log.Printf("Error saving user data to Firestore: %v", err)
+        log.Printf("Error starting auth session: %v", err)
         w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error saving user data"})
+        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error starting session"})
         return
     }
 
-    json.NewEncoder(w).Encode(models.AuthResponse{
-        Token:        accessToken,
-        RefreshToken: refreshToken,
-    })
+    writeSessionTokens(w, session, services.UserRoles(user))
 }
 
 // RefreshHandler refreshes an access token using a refresh token.
@@ -170,12 +211,7 @@ func RefreshHandler(w http.ResponseWriter, r *http.Request) {
     var parseErr error
 
     go func() {
-        token, err := jwt.Parse(req.RefreshToken, func(token *jwt.Token) (interface{}, error) {
-            if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-                return nil, fmt.Errorf("unexpected signing method")
-            }
-            return config.JWTSecret, nil
-        })
+        token, err := jwt.Parse(req.RefreshToken, config.JWTKeyFunc)
         parseErr = err
         tokenChan <- token
     }()
@@ -215,67 +251,172 @@ func RefreshHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    doc, err := config.FirestoreClient.Collection("users").Doc(uid).Get(ctx)
-    if err != nil {
-        log.Printf("Error retrieving user data from Firestore: %v", err)
+    // Refresh tokens issued before sessions existed carry no session or token ID and must be
+    // exchanged by signing in again.
+    sessionID, _ := claims["sid"].(string)
+    tokenID, _ := claims["jti"].(string)
+    if claims["typ"] != middleware.RefreshTokenType || sessionID == "" || tokenID == "" {
         w.WriteHeader(http.StatusUnauthorized)
         json.NewEncoder(w).Encode(models.AuthResponse{Error: "Invalid refresh token"})
         return
     }
 
-    var userData map[string]interface{}
-    if err := doc.DataTo(&userData); err != nil {
-        log.Printf("Error parsing user data: %v", err)
-        w.WriteHeader(http.StatusUnauthorized)
-        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Invalid refresh token"})
+    session, err := services.RotateAuthSession(ctx, uid, sessionID, tokenID)
+    if err != nil {
+        switch {
+        case errors.Is(err, services.ErrRefreshTokenReused):
+            config.RevokedTokenCache.SetDefault(sessionID, true)
+            w.WriteHeader(http.StatusUnauthorized)
+            json.NewEncoder(w).Encode(models.AuthResponse{Error: "Refresh token reuse detected; session revoked"})
+        case errors.Is(err, repository.ErrNotFound), errors.Is(err, services.ErrInvalidRefreshToken):
+            w.WriteHeader(http.StatusUnauthorized)
+            json.NewEncoder(w).Encode(models.AuthResponse{Error: "Invalid refresh token"})
+        default:
+            log.Printf("Error rotating auth session %s for userID %s: %v", sessionID, uid, err)
+            w.WriteHeader(http.StatusInternalServerError)
+            json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error updating session"})
+        }
         return
     }
 
-    storedRefreshToken, ok := userData["refreshToken"].(string)
-    if !ok || storedRefreshToken != req.RefreshToken {
-        log.Printf("Refresh token mismatch for userID %s", uid)
-        w.WriteHeader(http.StatusUnauthorized)
-        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Invalid refresh token"})
+    roles, err := services.GetUserRoles(ctx, uid)
+    if err != nil {
+        log.Printf("Error retrieving roles for userID %s: %v", uid, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error retrieving user roles"})
         return
     }
 
-    refreshTokenExpiresAt, ok := userData["refreshTokenExpiresAt"].(time.Time)
-    if !ok || refreshTokenExpiresAt.Before(time.Now()) {
-        log.Printf("Refresh token expired for userID %s", uid)
+    writeSessionTokens(w, session, roles)
+}
+
+// ListAuthSessionsHandler lists the devices the user is signed in on.
+func ListAuthSessionsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
         w.WriteHeader(http.StatusUnauthorized)
-        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Expired refresh token"})
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
         return
     }
+    sessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
 
-    newAccessToken, err := generateToken(uid, time.Hour*24)
+    sessions, err := services.ListAuthSessions(r.Context(), userID, sessionID)
     if err != nil {
+        log.Printf("Error listing auth sessions for userID %s: %v", userID, err)
         w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error generating new access token"})
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve sessions: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(sessions)
+}
+
+// RevokeAuthSessionHandler signs one of the user's devices out.
+func RevokeAuthSessionHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
         return
     }
 
-    newRefreshToken, err := generateToken(uid, time.Hour*24*30)
+    sessionID := mux.Vars(r)["sessionID"]
+    err := services.RevokeAuthSession(r.Context(), userID, sessionID, models.AuthSessionRevokedByUser)
+    if err == nil {
+        config.RevokedTokenCache.SetDefault(sessionID, true)
+    }
+    if errors.Is(err, repository.ErrNotFound) {
+        w.WriteHeader(http.StatusNotFound)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Session not found"})
+        return
+    }
     if err != nil {
+        log.Printf("Error revoking auth session %s for userID %s: %v", sessionID, userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to revoke session: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]string{"message": "Session revoked successfully"})
+}
+
+// LogoutHandler signs the current device out, revoking its access token and session.
+func LogoutHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+    sessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
+    tokenID, _ := r.Context().Value(middleware.TokenIDKey).(string)
+    expiresAt, _ := r.Context().Value(middleware.TokenExpiresAtKey).(time.Time)
+
+    if err := services.Logout(r.Context(), userID, sessionID, tokenID, expiresAt); err != nil {
+        log.Printf("Error logging out userID %s: %v", userID, err)
         w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error generating new refresh token"})
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to log out: " + err.Error()})
+        return
+    }
+    for _, id := range []string{tokenID, sessionID} {
+        if id != "" {
+            config.RevokedTokenCache.SetDefault(id, true)
+        }
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]string{"message": "Logged out successfully"})
+}
+
+// LogoutAllHandler signs the user out of every device.
+func LogoutAllHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
         return
     }
+    tokenID, _ := r.Context().Value(middleware.TokenIDKey).(string)
+    expiresAt, _ := r.Context().Value(middleware.TokenExpiresAtKey).(time.Time)
 
-    updatedUserData := map[string]interface{}{
-        "refreshToken": newRefreshToken,
-        "refreshTokenCreatedAt": time.Now(),
-        "refreshTokenExpiresAt": time.Now().Add(time.Hour * 24 * 30),
+    revoked, err := services.RevokeAllAuthSessions(r.Context(), userID, models.AuthSessionRevokedLogout)
+    for _, id := range revoked {
+        config.RevokedTokenCache.SetDefault(id, true)
+    }
+    if err == nil && tokenID != "" {
+        err = services.RevokeAccessToken(r.Context(), userID, tokenID, expiresAt)
     }
-    _, err = config.FirestoreClient.Collection("users").Doc(uid).Set(ctx, updatedUserData, firestore.MergeAll)
     if err != nil {
-        log.Printf("Error updating user data in Firestore: %v", err)
+        log.Printf("Error logging out all sessions of userID %s: %v", userID, err)
         w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.AuthResponse{Error: "Error updating user data"})
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to log out: " + err.Error()})
         return
     }
+    if tokenID != "" {
+        config.RevokedTokenCache.SetDefault(tokenID, true)
+    }
 
-    json.NewEncoder(w).Encode(models.AuthResponse{
-        Token:        newAccessToken,
-        RefreshToken: newRefreshToken,
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "message":         "Logged out of all sessions successfully",
+        "revokedSessions": len(revoked),
     })
-}
\ No newline at end of file
+}
+
+// JWKSHandler publishes the public token verification keys as a JSON Web Key Set.
+func JWKSHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+    w.Header().Set("Cache-Control", "public, max-age=3600")
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(config.JWKS())
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+    "encoding/json"
+    "errors"
+    "log"
+    "net/http"
+
+    "github.com/gorilla/mux"
+    "github.com/dzuura/neurodyx-be/middleware"
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+    "github.com/dzuura/neurodyx-be/services"
+)
+
+// ListChildProfilesHandler lists the child profiles of the signed-in guardian.
+func ListChildProfilesHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    profiles, err := services.ListChildProfiles(r.Context(), userID)
+    if err != nil {
+        log.Printf("Error listing child profiles for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve profiles: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(profiles)
+}
+
+// CreateChildProfileHandler adds a child profile to the signed-in guardian's account.
+func CreateChildProfileHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    var req models.ChildProfile
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+        return
+    }
+
+    profile, err := services.CreateChildProfile(r.Context(), userID, req)
+    if errors.Is(err, services.ErrInvalidChildProfile) || errors.Is(err, services.ErrInvalidAgeGroup) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
+    if errors.Is(err, services.ErrChildProfileLimit) {
+        w.WriteHeader(http.StatusConflict)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
+    if err != nil {
+        log.Printf("Error creating child profile for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to create profile: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(profile)
+}
+
+// UpdateChildProfileHandler renames one of the signed-in guardian's child profiles or changes its age group.
+func UpdateChildProfileHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    var req models.ChildProfile
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+        return
+    }
+
+    profileID := mux.Vars(r)["profileID"]
+    profile, err := services.UpdateChildProfile(r.Context(), userID, profileID, req)
+    if errors.Is(err, services.ErrInvalidChildProfile) || errors.Is(err, services.ErrInvalidAgeGroup) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
+    if errors.Is(err, repository.ErrNotFound) {
+        w.WriteHeader(http.StatusNotFound)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Profile not found"})
+        return
+    }
+    if err != nil {
+        log.Printf("Error updating child profile %s for userID %s: %v", profileID, userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to update profile: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(profile)
+}
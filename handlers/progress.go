@@ -2,10 +2,11 @@ package handlers
 
 import (
     "encoding/json"
+    "errors"
     "log"
     "net/http"
     "strconv"
-	"time"
+    "time"
 
     "github.com/dzuura/neurodyx-be/middleware"
     "github.com/dzuura/neurodyx-be/models"
@@ -23,7 +24,12 @@ func GetWeeklyProgressHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    progress, err := services.GetWeeklyProgress(r.Context(), userID)
+    loc, ok := requestLocation(w, r, userID)
+    if !ok {
+        return
+    }
+
+    progress, err := services.GetWeeklyProgress(r.Context(), userID, loc)
     if err != nil {
         log.Printf("Error retrieving weekly progress for userID %s: %v", userID, err)
         w.WriteHeader(http.StatusInternalServerError)
@@ -35,6 +41,34 @@ func GetWeeklyProgressHandler(w http.ResponseWriter, r *http.Request) {
     json.NewEncoder(w).Encode(progress)
 }
 
+// GetProgressSummaryHandler retrieves the user's streak and today's progress.
+func GetProgressSummaryHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    loc, ok := requestLocation(w, r, userID)
+    if !ok {
+        return
+    }
+
+    summary, err := services.GetProgressSummary(r.Context(), userID, loc)
+    if err != nil {
+        log.Printf("Error retrieving progress summary for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve progress summary: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(summary)
+}
+
 // GetMonthlyProgressHandler retrieves the user's therapy progress for a specific month.
 func GetMonthlyProgressHandler(w http.ResponseWriter, r *http.Request) {
     w.Header().Set("Content-Type", "application/json")
@@ -46,7 +80,12 @@ func GetMonthlyProgressHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    now := time.Now().UTC()
+    loc, ok := requestLocation(w, r, userID)
+    if !ok {
+        return
+    }
+
+    now := time.Now().In(loc)
     yearStr := r.URL.Query().Get("year")
     monthStr := r.URL.Query().Get("month")
 
@@ -70,4 +109,34 @@ func GetMonthlyProgressHandler(w http.ResponseWriter, r *http.Request) {
 
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(progress)
+}
+
+// requestLocation resolves the timezone for the user's progress, honouring a timezone query
+// parameter over the user's settings. Only read endpoints use it. It writes an error response
+// and reports false on failure.
+func requestLocation(w http.ResponseWriter, r *http.Request, userID string) (*time.Location, bool) {
+    return resolveLocation(w, r, userID, r.URL.Query().Get("timezone"))
+}
+
+// storedLocation resolves the timezone stored for the user, ignoring any timezone query parameter.
+// Endpoints that record progress use it so a request cannot credit a different day.
+func storedLocation(w http.ResponseWriter, r *http.Request, userID string) (*time.Location, bool) {
+    return resolveLocation(w, r, userID, "")
+}
+
+// resolveLocation resolves the user's timezone with an optional override and writes an error
+// response when it cannot.
+func resolveLocation(w http.ResponseWriter, r *http.Request, userID, override string) (*time.Location, bool) {
+    loc, err := services.UserLocation(r.Context(), userID, override)
+    if err != nil {
+        if errors.Is(err, services.ErrInvalidTimezone) {
+            w.WriteHeader(http.StatusBadRequest)
+        } else {
+            log.Printf("Error resolving timezone for userID %s: %v", userID, err)
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to resolve timezone: " + err.Error()})
+        return nil, false
+    }
+    return loc, true
 }
\ No newline at end of file
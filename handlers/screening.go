@@ -2,14 +2,15 @@ package handlers
 
 import (
     "encoding/json"
+    "errors"
     "log"
     "net/http"
-    "strconv"
 
     "github.com/gorilla/mux"
     "github.com/dzuura/neurodyx-be/config"
     "github.com/dzuura/neurodyx-be/middleware"
     "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
     "github.com/dzuura/neurodyx-be/services"
 )
 
@@ -45,6 +46,11 @@ func AddScreeningQuestionHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     questionID, err := services.SaveScreeningQuestion(r.Context(), question, userID)
+    if errors.Is(err, services.ErrInvalidQuestion) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
     if err != nil {
         log.Printf("Error saving screening question: %v", err)
         w.WriteHeader(http.StatusInternalServerError)
@@ -52,6 +58,9 @@ func AddScreeningQuestionHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    config.ScreeningQuestionCache.Delete(question.AgeGroup)
+    config.ScreeningQuestionCache.Delete("")
+
     w.WriteHeader(http.StatusCreated)
     json.NewEncoder(w).Encode(map[string]string{"questionID": questionID})
 }
@@ -70,6 +79,9 @@ func GetScreeningQuestionsHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     if cached, ok := config.LoadFromCache(config.ScreeningQuestionCache, ageGroup); ok {
+        if questions, ok := cached.([]models.ScreeningQuestion); ok && ageGroup != "" {
+            w.Header().Set("X-Question-Set-Version", services.ScreeningQuestionSetVersion(questions))
+        }
         w.WriteHeader(http.StatusOK)
         json.NewEncoder(w).Encode(cached)
         return
@@ -84,6 +96,9 @@ func GetScreeningQuestionsHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     config.StoreInCache(config.ScreeningQuestionCache, ageGroup, questions)
+    if ageGroup != "" {
+        w.Header().Set("X-Question-Set-Version", services.ScreeningQuestionSetVersion(questions))
+    }
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(questions)
 }
@@ -126,29 +141,19 @@ func UpdateScreeningQuestionHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    firestoreClient, err := config.App.Firestore(r.Context())
-    if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to connect to Firestore"})
-        return
-    }
-    defer firestoreClient.Close()
-
-    var originalAgeGroup string
-    ageGroups := []string{"adult", "kid"}
-    for _, ag := range ageGroups {
-        doc, err := firestoreClient.Collection("screeningQuestions").Doc(ag).Collection("questions").Doc(questionID).Get(r.Context())
-        if err == nil && doc.Exists() {
-            originalAgeGroup = ag
-            break
-        }
-    }
-
-    if originalAgeGroup == "" {
+    existing, err := services.FindScreeningQuestion(r.Context(), questionID)
+    if errors.Is(err, repository.ErrNotFound) {
         w.WriteHeader(http.StatusNotFound)
         json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Screening question not found"})
         return
     }
+    if err != nil {
+        log.Printf("Error looking up screening question %s: %v", questionID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve screening question: " + err.Error()})
+        return
+    }
+    originalAgeGroup := existing.AgeGroup
 
     if question.AgeGroup != originalAgeGroup {
         w.WriteHeader(http.StatusBadRequest)
@@ -157,6 +162,11 @@ func UpdateScreeningQuestionHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     err = services.UpdateScreeningQuestion(r.Context(), questionID, question, userID)
+    if errors.Is(err, services.ErrInvalidQuestion) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
     if err != nil {
         log.Printf("Error updating screening question %s: %v", questionID, err)
         w.WriteHeader(http.StatusInternalServerError)
@@ -173,6 +183,7 @@ func UpdateScreeningQuestionHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     config.ScreeningQuestionCache.Delete(question.AgeGroup)
+    config.ScreeningQuestionCache.Delete("")
 
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(updatedQuestion)
@@ -196,29 +207,19 @@ func DeleteScreeningQuestionHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    firestoreClient, err := config.App.Firestore(r.Context())
-    if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to connect to Firestore"})
-        return
-    }
-    defer firestoreClient.Close()
-
-    var ageGroup string
-    ageGroups := []string{"adult", "kid"}
-    for _, ag := range ageGroups {
-        doc, err := firestoreClient.Collection("screeningQuestions").Doc(ag).Collection("questions").Doc(questionID).Get(r.Context())
-        if err == nil && doc.Exists() {
-            ageGroup = ag
-            break
-        }
-    }
-
-    if ageGroup == "" {
+    existing, err := services.FindScreeningQuestion(r.Context(), questionID)
+    if errors.Is(err, repository.ErrNotFound) {
         w.WriteHeader(http.StatusNotFound)
         json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Screening question not found"})
         return
     }
+    if err != nil {
+        log.Printf("Error looking up screening question %s: %v", questionID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve screening question: " + err.Error()})
+        return
+    }
+    ageGroup := existing.AgeGroup
 
     err = services.DeleteScreeningQuestion(r.Context(), questionID, ageGroup, userID)
     if err != nil {
@@ -229,6 +230,7 @@ func DeleteScreeningQuestionHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     config.ScreeningQuestionCache.Delete(ageGroup)
+    config.ScreeningQuestionCache.Delete("")
 
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(map[string]string{"message": "Screening question deleted successfully"})
@@ -245,9 +247,10 @@ func SubmitScreeningHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    if submission.AgeGroup == "" || len(submission.Answers) == 0 || len(submission.Answers) > 50 {
+    answerCount := len(submission.Responses) + len(submission.Answers)
+    if submission.AgeGroup == "" || answerCount == 0 || answerCount > 50 {
         w.WriteHeader(http.StatusBadRequest)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Missing required field: ageGroup, or answers empty/exceed 50"})
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Missing required field: ageGroup, or responses empty/exceed 50"})
         return
     }
 
@@ -258,53 +261,134 @@ func SubmitScreeningHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    questions, err := services.GetScreeningQuestions(r.Context(), submission.AgeGroup, userID)
+    set, err := services.BindScreeningAnswers(r.Context(), submission)
     if err != nil {
-        log.Printf("Error retrieving screening questions: %v", err)
+        switch {
+        case errors.Is(err, services.ErrScreeningSetChanged):
+            w.WriteHeader(http.StatusConflict)
+            json.NewEncoder(w).Encode(map[string]string{"error": err.Error(), "version": set.Version})
+            return
+        case errors.Is(err, services.ErrInvalidAgeGroup), errors.Is(err, services.ErrInvalidScreeningAnswers), errors.Is(err, services.ErrNoQuestions):
+            w.WriteHeader(http.StatusBadRequest)
+        default:
+            log.Printf("Error binding screening answers for ageGroup %s: %v", submission.AgeGroup, err)
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to bind screening answers: " + err.Error()})
+        return
+    }
+
+    score, err := services.ScoreScreening(r.Context(), submission.AgeGroup, set.Questions, set.Answers)
+    if err != nil {
+        if errors.Is(err, services.ErrInvalidAgeGroup) {
+            w.WriteHeader(http.StatusBadRequest)
+        } else {
+            log.Printf("Error scoring screening for ageGroup %s: %v", submission.AgeGroup, err)
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to score screening: " + err.Error()})
+        return
+    }
+
+    result, err := services.SaveScreeningResult(r.Context(), userID, submission.AgeGroup, set, score, "")
+    if err != nil {
+        log.Printf("Error saving screening result: %v", err)
         w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve screening questions: " + err.Error()})
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to save screening result: " + err.Error()})
         return
     }
 
-    expectedAnswerCount := len(questions)
-    if expectedAnswerCount == 0 {
-        w.WriteHeader(http.StatusBadRequest)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "No screening questions found for ageGroup: " + submission.AgeGroup})
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "id":                result.ID,
+        "version":           result.QuestionSetVersion,
+        "questionIDs":       result.QuestionIDs,
+        "ignored":           set.Ignored,
+        "score":             score.Score,
+        "riskLevel":         result.RiskLevel,
+        "domains":           score.Domains,
+        "criticalItems":     score.CriticalItems,
+        "reasons":           score.Reasons,
+        "previousRiskLevel": result.PreviousRiskLevel,
+        "trend":             result.Trend,
+    })
+}
+
+// GetScreeningResultsHandler retrieves the user's latest and historical screening results.
+func GetScreeningResultsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
         return
     }
-    if len(submission.Answers) != expectedAnswerCount {
-        w.WriteHeader(http.StatusBadRequest)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid number of answers: expected " + strconv.Itoa(expectedAnswerCount) + ", got " + strconv.Itoa(len(submission.Answers))})
+
+    history, err := services.GetScreeningHistory(r.Context(), userID)
+    if err != nil {
+        log.Printf("Error retrieving screening results for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve screening results: " + err.Error()})
         return
     }
 
-    score := 0
-    for _, answer := range submission.Answers {
-        if answer {
-            score++
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(history)
+}
+
+// GetScreeningRuleHandler retrieves the scoring rule of an age group.
+func GetScreeningRuleHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    ageGroup := mux.Vars(r)["ageGroup"]
+    rule, err := services.GetScreeningRule(r.Context(), ageGroup)
+    if err != nil {
+        if errors.Is(err, services.ErrInvalidAgeGroup) {
+            w.WriteHeader(http.StatusBadRequest)
+        } else {
+            log.Printf("Error retrieving screening rule for ageGroup %s: %v", ageGroup, err)
+            w.WriteHeader(http.StatusInternalServerError)
         }
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve screening rule: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(rule)
+}
+
+// UpdateScreeningRuleHandler replaces the scoring rule of an age group.
+func UpdateScreeningRuleHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
     }
 
-    totalQuestions := float64(expectedAnswerCount)
-    truePercentage := float64(score) / totalQuestions * 100
-    var riskLevel string
-    switch {
-    case truePercentage <= 40:
-        riskLevel = "low"
-    case truePercentage <= 70:
-        riskLevel = "moderate"
-    default:
-        riskLevel = "high"
+    var rule models.ScreeningRule
+    if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+        return
     }
+    rule.AgeGroup = mux.Vars(r)["ageGroup"]
 
-    err = services.SaveScreeningResult(r.Context(), userID, submission.AgeGroup, submission.Answers, riskLevel, "")
+    rule, err := services.UpdateScreeningRule(r.Context(), rule, userID)
     if err != nil {
-        log.Printf("Error saving screening result: %v", err)
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to save screening result: " + err.Error()})
+        if errors.Is(err, services.ErrInvalidAgeGroup) || errors.Is(err, services.ErrInvalidScreeningRule) {
+            w.WriteHeader(http.StatusBadRequest)
+        } else {
+            log.Printf("Error updating screening rule for ageGroup %s: %v", rule.AgeGroup, err)
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to update screening rule: " + err.Error()})
         return
     }
 
     w.WriteHeader(http.StatusOK)
-    json.NewEncoder(w).Encode(map[string]string{"riskLevel": riskLevel})
-}
\ No newline at end of file
+    json.NewEncoder(w).Encode(rule)
+}
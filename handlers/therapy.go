@@ -2,13 +2,16 @@ package handlers
 
 import (
     "encoding/json"
+    "errors"
     "log"
     "net/http"
+    "strconv"
 
     "github.com/gorilla/mux"
     "github.com/dzuura/neurodyx-be/config"
     "github.com/dzuura/neurodyx-be/middleware"
     "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
     "github.com/dzuura/neurodyx-be/services"
 )
 
@@ -43,6 +46,11 @@ func AddTherapyQuestionHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     questionID, err := services.SaveTherapyQuestion(r.Context(), question, userID)
+    if errors.Is(err, services.ErrInvalidQuestion) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
     if err != nil {
         log.Printf("Error saving therapy question: %v", err)
         w.WriteHeader(http.StatusInternalServerError)
@@ -97,23 +105,38 @@ func GetTherapyQuestionsHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     cacheKey := questionType + ":" + category
+    var questions []models.TherapyQuestion
     if cached, ok := config.LoadFromCache(config.TherapyQuestionCache, cacheKey); ok {
+        questions = cached.([]models.TherapyQuestion)
+    } else {
+        var err error
+        questions, err = services.GetTherapyQuestions(r.Context(), questionType, category, userID)
+        if err != nil {
+            log.Printf("Error retrieving therapy questions for type %s, category %s: %v", questionType, category, err)
+            w.WriteHeader(http.StatusInternalServerError)
+            json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve questions: " + err.Error()})
+            return
+        }
+        config.StoreInCache(config.TherapyQuestionCache, cacheKey, questions)
+    }
+
+    if r.URL.Query().Get("adaptive") == "false" {
         w.WriteHeader(http.StatusOK)
-        json.NewEncoder(w).Encode(cached)
+        json.NewEncoder(w).Encode(questions)
         return
     }
 
-    questions, err := services.GetTherapyQuestions(r.Context(), questionType, category, userID)
+    adapted, difficulty, err := services.AdaptTherapyQuestions(r.Context(), userID, questionType, category, questions)
     if err != nil {
-        log.Printf("Error retrieving therapy questions for type %s, category %s: %v", questionType, category, err)
+        log.Printf("Error adapting therapy questions for type %s, category %s: %v", questionType, category, err)
         w.WriteHeader(http.StatusInternalServerError)
         json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve questions: " + err.Error()})
         return
     }
 
-    config.StoreInCache(config.TherapyQuestionCache, cacheKey, questions)
+    w.Header().Set("X-Difficulty-Level", strconv.Itoa(difficulty))
     w.WriteHeader(http.StatusOK)
-    json.NewEncoder(w).Encode(questions)
+    json.NewEncoder(w).Encode(adapted)
 }
 
 // GetTherapyQuestionByIDHandler retrieves a therapy question by ID.
@@ -153,43 +176,19 @@ func UpdateTherapyQuestionHandler(w http.ResponseWriter, r *http.Request) {
         }
     }
 
-    firestoreClient, err := config.App.Firestore(r.Context())
-    if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to connect to Firestore"})
-        return
-    }
-    defer firestoreClient.Close()
-
-    var originalType, originalCategory string
-    types := []string{"visual", "auditory", "kinesthetic", "tactile"}
-    found := false
-    for _, t := range types {
-        categoriesIter := firestoreClient.Collection("therapyQuestions").Doc(t).Collections(r.Context())
-        categories, err := categoriesIter.GetAll()
-        if err != nil {
-            log.Printf("Failed to retrieve categories for type %s: %v", t, err)
-            continue
-        }
-        for _, category := range categories {
-            doc, err := category.Doc(questionID).Get(r.Context())
-            if err == nil && doc.Exists() {
-                originalType = t
-                originalCategory = category.ID
-                found = true
-                break
-            }
-        }
-        if found {
-            break
-        }
-    }
-
-    if !found {
+    existing, err := services.FindTherapyQuestion(r.Context(), questionID)
+    if errors.Is(err, repository.ErrNotFound) {
         w.WriteHeader(http.StatusNotFound)
         json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Therapy question not found"})
         return
     }
+    if err != nil {
+        log.Printf("Error looking up therapy question %s: %v", questionID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve therapy question: " + err.Error()})
+        return
+    }
+    originalType, originalCategory := existing.Type, existing.Category
 
     if question.Type != originalType || question.Category != originalCategory {
         w.WriteHeader(http.StatusBadRequest)
@@ -198,6 +197,11 @@ func UpdateTherapyQuestionHandler(w http.ResponseWriter, r *http.Request) {
     }
 
     err = services.UpdateTherapyQuestion(r.Context(), questionID, question, userID)
+    if errors.Is(err, services.ErrInvalidQuestion) {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: err.Error()})
+        return
+    }
     if err != nil {
         log.Printf("Error updating therapy question %s: %v", questionID, err)
         w.WriteHeader(http.StatusInternalServerError)
@@ -238,43 +242,19 @@ func DeleteTherapyQuestionHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    firestoreClient, err := config.App.Firestore(r.Context())
-    if err != nil {
-        w.WriteHeader(http.StatusInternalServerError)
-        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to connect to Firestore"})
-        return
-    }
-    defer firestoreClient.Close()
-
-    var questionType, category string
-    types := []string{"visual", "auditory", "kinesthetic", "tactile"}
-    found := false
-    for _, t := range types {
-        categoriesIter := firestoreClient.Collection("therapyQuestions").Doc(t).Collections(r.Context())
-        categories, err := categoriesIter.GetAll()
-        if err != nil {
-            log.Printf("Failed to retrieve categories for type %s: %v", t, err)
-            continue
-        }
-        for _, cat := range categories {
-            doc, err := cat.Doc(questionID).Get(r.Context())
-            if err == nil && doc.Exists() {
-                questionType = t
-                category = cat.ID
-                found = true
-                break
-            }
-        }
-        if found {
-            break
-        }
-    }
-
-    if !found {
+    existing, err := services.FindTherapyQuestion(r.Context(), questionID)
+    if errors.Is(err, repository.ErrNotFound) {
         w.WriteHeader(http.StatusNotFound)
         json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Therapy question not found"})
         return
     }
+    if err != nil {
+        log.Printf("Error looking up therapy question %s: %v", questionID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve therapy question: " + err.Error()})
+        return
+    }
+    questionType, category := existing.Type, existing.Category
 
     err = services.DeleteTherapyQuestion(r.Context(), questionID, questionType, category, userID)
     if err != nil {
@@ -296,9 +276,10 @@ func SubmitTherapyAnswerHandler(w http.ResponseWriter, r *http.Request) {
     w.Header().Set("Content-Type", "application/json")
 
     var submission struct {
-        Submissions []models.TherapySubmission `json:"submissions"`
-        Type        string                     `json:"type"`
-        Category    string                     `json:"category"`
+        Submissions     []models.TherapySubmission `json:"submissions"`
+        Type            string                     `json:"type"`
+        Category        string                     `json:"category"`
+        DurationSeconds int                        `json:"durationSeconds,omitempty"`
     }
     if err := json.NewDecoder(r.Body).Decode(&submission); err != nil {
         w.WriteHeader(http.StatusBadRequest)
@@ -313,6 +294,12 @@ func SubmitTherapyAnswerHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    if submission.DurationSeconds < 0 || submission.DurationSeconds > 24*60*60 {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "durationSeconds must be between 0 and 86400"})
+        return
+    }
+
     userID, ok := r.Context().Value(middleware.UserIDKey).(string)
     if !ok {
         w.WriteHeader(http.StatusUnauthorized)
@@ -320,33 +307,55 @@ func SubmitTherapyAnswerHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    loc, ok := storedLocation(w, r, userID)
+    if !ok {
+        return
+    }
+
     totalCorrect := 0
+    totalScore := 0.0
+    answers := make([]models.AnswerFeedback, 0, len(submission.Submissions))
+    scored := make([]models.TherapyResult, 0, len(submission.Submissions))
     for _, sub := range submission.Submissions {
-        result, err := services.SaveTherapyResult(r.Context(), userID, sub, "")
+        result, err := services.SaveTherapyResult(r.Context(), userID, sub, "", loc)
         if err != nil {
             log.Printf("Error submitting answer for question %s: %v", sub.QuestionID, err)
             continue
         }
         totalCorrect += result.CorrectAnswers
+        totalScore += result.Score
+        answers = append(answers, result.Answers...)
+        scored = append(scored, result)
     }
 
-    _, err := services.UpdateDailyProgress(r.Context(), userID, submission.Type, submission.Category)
-    if err != nil {
-        log.Printf("Error updating daily progress for userID %s: %v", userID, err)
+    if len(scored) > 0 {
+        if _, err := services.UpdateDailyProgress(r.Context(), userID, scored, submission.DurationSeconds, loc); err != nil {
+            log.Printf("Error updating daily progress for userID %s: %v", userID, err)
+        }
+    } else {
+        log.Printf("No therapy answers were scored for userID: %s, daily progress not updated", userID)
+    }
+
+    achievements := []models.Achievement{}
+    if len(scored) > 0 {
+        achievements = evaluateAchievements(r, userID, scored)
     }
 
     result := models.TherapyResult{
         Type:           submission.Type,
         Category:       submission.Category,
         CorrectAnswers: totalCorrect,
+        Score:          totalScore,
         TotalQuestions: len(submission.Submissions),
         Status:         "completed",
+        Answers:        answers,
     }
 
     log.Printf("Successfully processed %d therapy submissions for userID: %s, type: %s, category: %s, correct: %d", len(submission.Submissions), userID, submission.Type, submission.Category, totalCorrect)
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(map[string]interface{}{
-        "result": result,
+        "result":       result,
+        "achievements": achievements,
     })
 }
 
@@ -379,4 +388,73 @@ func GetTherapyResultsHandler(w http.ResponseWriter, r *http.Request) {
 
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(result)
+}
+
+// GetTherapyRecommendationsHandler returns the user's ranked daily therapy plan.
+func GetTherapyRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    loc, ok := requestLocation(w, r, userID)
+    if !ok {
+        return
+    }
+
+    plan, err := services.RecommendTherapy(r.Context(), userID, loc)
+    if err != nil {
+        log.Printf("Error recommending therapy for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to recommend therapy: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(plan)
+}
+
+// GetDueReviewsHandler returns the therapy questions due for review today.
+func GetDueReviewsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    limit := 0
+    if raw := r.URL.Query().Get("limit"); raw != "" {
+        var err error
+        limit, err = strconv.Atoi(raw)
+        if err != nil || limit < 0 {
+            w.WriteHeader(http.StatusBadRequest)
+            json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid limit parameter"})
+            return
+        }
+    }
+
+    loc, ok := requestLocation(w, r, userID)
+    if !ok {
+        return
+    }
+
+    reviews, err := services.GetDueReviews(r.Context(), userID, limit, loc)
+    if err != nil {
+        log.Printf("Error retrieving due reviews for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve due reviews: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "reviews": reviews,
+    })
 }
\ No newline at end of file
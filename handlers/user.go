@@ -0,0 +1,131 @@
+package handlers
+
+import (
+    "encoding/json"
+    "errors"
+    "log"
+    "net/http"
+
+    "github.com/dzuura/neurodyx-be/middleware"
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/services"
+)
+
+// GetUserSettingsHandler retrieves the user's settings.
+func GetUserSettingsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    settings, err := services.GetUserSettings(r.Context(), userID)
+    if err != nil {
+        log.Printf("Error retrieving settings for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve settings: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateUserSettingsHandler replaces the user's settings.
+func UpdateUserSettingsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    var settings models.UserSettings
+    if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+        return
+    }
+
+    settings, err := services.UpdateUserSettings(r.Context(), userID, settings)
+    if err != nil {
+        if errors.Is(err, services.ErrInvalidTimezone) {
+            w.WriteHeader(http.StatusBadRequest)
+        } else {
+            log.Printf("Error updating settings for userID %s: %v", userID, err)
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to update settings: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(settings)
+}
+
+// GetDailyGoalHandler retrieves the user's daily therapy goal.
+func GetDailyGoalHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    goal, err := services.GetDailyGoal(r.Context(), userID)
+    if err != nil {
+        log.Printf("Error retrieving daily goal for userID %s: %v", userID, err)
+        w.WriteHeader(http.StatusInternalServerError)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to retrieve daily goal: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(goal)
+}
+
+// UpdateDailyGoalHandler replaces the user's own daily therapy goal.
+func UpdateDailyGoalHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    userID, ok := r.Context().Value(middleware.UserIDKey).(string)
+    if !ok {
+        w.WriteHeader(http.StatusUnauthorized)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "User ID missing"})
+        return
+    }
+
+    updateDailyGoal(w, r, userID, userID)
+}
+
+// updateDailyGoal decodes a daily goal from the request body and stores it for userID on behalf of setBy.
+func updateDailyGoal(w http.ResponseWriter, r *http.Request, userID, setBy string) {
+    var goal models.DailyGoal
+    if err := json.NewDecoder(r.Body).Decode(&goal); err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Invalid request body: " + err.Error()})
+        return
+    }
+
+    goal, err := services.UpdateDailyGoal(r.Context(), userID, setBy, goal)
+    if err != nil {
+        if errors.Is(err, services.ErrInvalidDailyGoal) {
+            w.WriteHeader(http.StatusBadRequest)
+        } else {
+            log.Printf("Error updating daily goal for userID %s: %v", userID, err)
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+        json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Failed to update daily goal: " + err.Error()})
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(goal)
+}
@@ -10,6 +10,9 @@ import (
     "github.com/dzuura/neurodyx-be/config"
     "github.com/dzuura/neurodyx-be/handlers"
     "github.com/dzuura/neurodyx-be/middleware"
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+    "github.com/dzuura/neurodyx-be/services"
 )
 
 func main() {
@@ -18,9 +21,31 @@ func main() {
         log.Fatalf("Error loading .env file: %v", err)
     }
 
-    // Initialize Firebase
-    if err := config.InitFirebase(); err != nil {
-        log.Fatalf("Failed to initialize Firebase: %v", err)
+    // Load shared configuration
+    if err := config.InitConfig(); err != nil {
+        log.Fatalf("Failed to load configuration: %v", err)
+    }
+
+    if config.LocalAuthEnabled() {
+        log.Printf("WARNING: ALLOW_LOCAL_AUTH is enabled, anyone can sign in as any user ID. Never enable it in production")
+    }
+
+    // Initialize storage
+    switch backend := config.StorageBackend(); backend {
+    case config.StorageFirestore:
+        if err := config.InitFirebase(); err != nil {
+            log.Fatalf("Failed to initialize Firebase: %v", err)
+        }
+        services.SetRepository(repository.NewFirestoreRepository(config.FirestoreClient))
+    case config.StorageMemory:
+        log.Printf("Using in-memory storage, data will be lost on restart")
+        memoryRepo := repository.NewMemoryRepository()
+        for _, uid := range config.AdminUserIDs() {
+            memoryRepo.SetAdmin(uid, true)
+        }
+        services.SetRepository(memoryRepo)
+    default:
+        log.Fatalf("Unknown STORAGE_BACKEND %q, expected %q or %q", backend, config.StorageFirestore, config.StorageMemory)
     }
 
     // Setup rate limiters
@@ -34,46 +59,95 @@ func main() {
     r.HandleFunc("/api/health", middleware.PanicRecoveryMiddleware(func(w http.ResponseWriter, r *http.Request) {
         w.Write([]byte("Neurodyx Backend is running"))
     })).Methods("GET")
+    r.HandleFunc("/.well-known/jwks.json", middleware.PanicRecoveryMiddleware(handlers.JWKSHandler)).Methods("GET")
     r.HandleFunc("/api/auth", middleware.PanicRecoveryMiddleware(middleware.RateLimitMiddleware(authLimiterStore, handlers.AuthHandler))).Methods("POST")
     r.HandleFunc("/api/refresh", middleware.PanicRecoveryMiddleware(middleware.RateLimitMiddleware(refreshLimiterStore, handlers.RefreshHandler))).Methods("POST")
+    r.HandleFunc("/api/auth/logout", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.LogoutHandler))).Methods("POST")
+    r.HandleFunc("/api/auth/logout-all", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.LogoutAllHandler))).Methods("POST")
+    r.HandleFunc("/api/auth/sessions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.ListAuthSessionsHandler))).Methods("GET")
+    r.HandleFunc("/api/auth/sessions/{sessionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.RevokeAuthSessionHandler))).Methods("DELETE")
 
     // Protected routes for screening
     screeningRouter := r.PathPrefix("/api/screening").Subrouter()
-    screeningRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.GetScreeningQuestionsHandler))).Methods("GET")
-    screeningRouter.HandleFunc("/submit", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.SubmitScreeningHandler))).Methods("POST")
+    screeningRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetScreeningQuestionsHandler)))).Methods("GET")
+    screeningRouter.HandleFunc("/submit", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.SubmitScreeningHandler)))).Methods("POST")
+    screeningRouter.HandleFunc("/results", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetScreeningResultsHandler)))).Methods("GET")
+    screeningRouter.HandleFunc("/rules/{ageGroup}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetScreeningRuleHandler)))).Methods("GET")
 
-    // Protected admin routes for screening
-    screeningRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.AdminMiddleware(handlers.AddScreeningQuestionHandler)))).Methods("POST")
-    screeningRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.AdminMiddleware(handlers.UpdateScreeningQuestionHandler)))).Methods("PUT")
-    screeningRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.AdminMiddleware(handlers.DeleteScreeningQuestionHandler)))).Methods("DELETE")
+    // Protected content management routes for screening
+    screeningRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageQuestions, handlers.AddScreeningQuestionHandler)))).Methods("POST")
+    screeningRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageQuestions, handlers.UpdateScreeningQuestionHandler)))).Methods("PUT")
+    screeningRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageQuestions, handlers.DeleteScreeningQuestionHandler)))).Methods("DELETE")
+    screeningRouter.HandleFunc("/rules/{ageGroup}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageScreeningRules, handlers.UpdateScreeningRuleHandler)))).Methods("PUT")
 
     // Protected routes for assessment
     assessmentRouter := r.PathPrefix("/api/assessment").Subrouter()
-    assessmentRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.GetAssessmentQuestionsHandler))).Methods("GET")
-    assessmentRouter.HandleFunc("/submit", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.SubmitAnswerHandler))).Methods("POST")
-    assessmentRouter.HandleFunc("/results", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.GetAssessmentResultsHandler))).Methods("GET")
+    assessmentRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetAssessmentQuestionsHandler)))).Methods("GET")
+    assessmentRouter.HandleFunc("/submit", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.SubmitAnswerHandler)))).Methods("POST")
+    assessmentRouter.HandleFunc("/results", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetAssessmentResultsHandler)))).Methods("GET")
+    assessmentRouter.HandleFunc("/profile", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetLearningProfileHandler)))).Methods("GET")
+    assessmentRouter.HandleFunc("/sessions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.StartAssessmentSessionHandler)))).Methods("POST")
+    assessmentRouter.HandleFunc("/sessions/{sessionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetAssessmentSessionHandler)))).Methods("GET")
+    assessmentRouter.HandleFunc("/sessions/{sessionID}/next", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.NextAssessmentQuestionHandler)))).Methods("GET")
+    assessmentRouter.HandleFunc("/sessions/{sessionID}/submit", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.SubmitAssessmentSessionHandler)))).Methods("POST")
+    assessmentRouter.HandleFunc("/sessions/{sessionID}/finish", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.FinishAssessmentSessionHandler)))).Methods("POST")
+    assessmentRouter.HandleFunc("/attempts", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.ListAssessmentAttemptsHandler)))).Methods("GET")
+    assessmentRouter.HandleFunc("/attempts/diff", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.DiffAssessmentAttemptsHandler)))).Methods("GET")
+    assessmentRouter.HandleFunc("/attempts/{attemptID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetAssessmentAttemptHandler)))).Methods("GET")
 
-    // Protected admin routes for assessment
-    assessmentRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.AdminMiddleware(handlers.AddAssessmentQuestionHandler)))).Methods("POST")
-    assessmentRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.AdminMiddleware(handlers.UpdateAssessmentQuestionHandler)))).Methods("PUT")
-    assessmentRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.AdminMiddleware(handlers.DeleteAssessmentQuestionHandler)))).Methods("DELETE")
+    // Protected content management routes for assessment
+    assessmentRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageQuestions, handlers.AddAssessmentQuestionHandler)))).Methods("POST")
+    assessmentRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageQuestions, handlers.UpdateAssessmentQuestionHandler)))).Methods("PUT")
+    assessmentRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageQuestions, handlers.DeleteAssessmentQuestionHandler)))).Methods("DELETE")
 
     // Protected routes for therapy
     therapyRouter := r.PathPrefix("/api/therapy").Subrouter()
-    therapyRouter.HandleFunc("/categories", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.GetTherapyCategoriesHandler))).Methods("GET")
-    therapyRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.GetTherapyQuestionsHandler))).Methods("GET")
-    therapyRouter.HandleFunc("/submit", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.SubmitTherapyAnswerHandler))).Methods("POST")
-    therapyRouter.HandleFunc("/results", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.GetTherapyResultsHandler))).Methods("GET")
+    therapyRouter.HandleFunc("/categories", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetTherapyCategoriesHandler)))).Methods("GET")
+    therapyRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetTherapyQuestionsHandler)))).Methods("GET")
+    therapyRouter.HandleFunc("/submit", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.SubmitTherapyAnswerHandler)))).Methods("POST")
+    therapyRouter.HandleFunc("/results", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetTherapyResultsHandler)))).Methods("GET")
+    therapyRouter.HandleFunc("/recommendations", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetTherapyRecommendationsHandler)))).Methods("GET")
+    therapyRouter.HandleFunc("/reviews/due", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetDueReviewsHandler)))).Methods("GET")
 
-    // Protected admin routes for therapy
-    therapyRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.AdminMiddleware(handlers.AddTherapyQuestionHandler)))).Methods("POST")
-    therapyRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.AdminMiddleware(handlers.UpdateTherapyQuestionHandler)))).Methods("PUT")
-    therapyRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.AdminMiddleware(handlers.DeleteTherapyQuestionHandler)))).Methods("DELETE")
+    // Protected content management routes for therapy
+    therapyRouter.HandleFunc("/questions", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageQuestions, handlers.AddTherapyQuestionHandler)))).Methods("POST")
+    therapyRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageQuestions, handlers.UpdateTherapyQuestionHandler)))).Methods("PUT")
+    therapyRouter.HandleFunc("/questions/{questionID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageQuestions, handlers.DeleteTherapyQuestionHandler)))).Methods("DELETE")
 
     // Protected routes for progress tracking
     progressRouter := r.PathPrefix("/api/progress").Subrouter()
-    progressRouter.HandleFunc("/weekly", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.GetWeeklyProgressHandler))).Methods("GET")
-    progressRouter.HandleFunc("/monthly", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.GetMonthlyProgressHandler))).Methods("GET")
+    progressRouter.HandleFunc("/weekly", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetWeeklyProgressHandler)))).Methods("GET")
+    progressRouter.HandleFunc("/summary", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetProgressSummaryHandler)))).Methods("GET")
+    progressRouter.HandleFunc("/monthly", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetMonthlyProgressHandler)))).Methods("GET")
+
+    // Protected routes for achievements
+    r.HandleFunc("/api/achievements", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetAchievementsHandler)))).Methods("GET")
+
+    // Protected routes for user settings
+    userRouter := r.PathPrefix("/api/user").Subrouter()
+    userRouter.HandleFunc("/settings", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetUserSettingsHandler)))).Methods("GET")
+    userRouter.HandleFunc("/settings", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.UpdateUserSettingsHandler)))).Methods("PUT")
+    userRouter.HandleFunc("/goal", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.GetDailyGoalHandler)))).Methods("GET")
+    userRouter.HandleFunc("/goal", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.ProfileMiddleware(handlers.UpdateDailyGoalHandler)))).Methods("PUT")
+
+    // Protected routes for child profiles
+    profileRouter := r.PathPrefix("/api/profiles").Subrouter()
+    profileRouter.HandleFunc("", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.ListChildProfilesHandler))).Methods("GET")
+    profileRouter.HandleFunc("", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.CreateChildProfileHandler))).Methods("POST")
+    profileRouter.HandleFunc("/{profileID}", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(handlers.UpdateChildProfileHandler))).Methods("PUT")
+
+    // Protected admin routes for maintenance
+    adminRouter := r.PathPrefix("/api/admin").Subrouter()
+    adminRouter.HandleFunc("/question-index/rebuild", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionMaintenance, handlers.RebuildQuestionIndexHandler)))).Methods("POST")
+    adminRouter.HandleFunc("/users/{userID}/roles", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageRoles, handlers.GetUserRolesHandler)))).Methods("GET")
+    adminRouter.HandleFunc("/users/{userID}/roles", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageRoles, handlers.SetUserRolesHandler)))).Methods("PUT")
+    adminRouter.HandleFunc("/users/{userID}/therapists", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageRoles, handlers.GetUserTherapistsHandler)))).Methods("GET")
+    adminRouter.HandleFunc("/users/{userID}/therapists", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageRoles, handlers.SetUserTherapistsHandler)))).Methods("PUT")
+    adminRouter.HandleFunc("/users/{userID}/goal", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageUserGoals, handlers.SetUserDailyGoalHandler)))).Methods("PUT")
+
+    // Protected routes for therapists and the learners assigned to them
+    therapistRouter := r.PathPrefix("/api/therapist").Subrouter()
+    therapistRouter.HandleFunc("/learners/{userID}/goal", middleware.PanicRecoveryMiddleware(middleware.AuthMiddleware(middleware.PermissionMiddleware(models.PermissionManageAssignedGoals, handlers.SetAssignedLearnerGoalHandler)))).Methods("PUT")
 
     // Start server
     port := os.Getenv("PORT")
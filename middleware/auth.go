@@ -6,10 +6,10 @@ import (
     "net/http"
     "strings"
     "time"
-    "fmt"
 
     "github.com/golang-jwt/jwt/v5"
     "github.com/dzuura/neurodyx-be/config"
+    "github.com/dzuura/neurodyx-be/services"
 )
 
 // contextKey is a custom type for context keys to avoid collisions
@@ -18,6 +18,21 @@ type contextKey string
 // UserIDKey is the key used to store user ID in the context
 const UserIDKey contextKey = "userID"
 
+// SessionIDKey is the key used to store the auth session ID in the context
+const SessionIDKey contextKey = "sessionID"
+
+// TokenIDKey is the key used to store the access token ID (jti) in the context
+const TokenIDKey contextKey = "tokenID"
+
+// TokenExpiresAtKey is the key used to store the access token's expiry in the context
+const TokenExpiresAtKey contextKey = "tokenExpiresAt"
+
+// RolesKey is the key used to store the roles carried by the access token in the context
+const RolesKey contextKey = "roles"
+
+// RefreshTokenType is the typ claim of refresh tokens, which are not accepted as access tokens.
+const RefreshTokenType = "refresh"
+
 // AuthMiddleware verifies JWT tokens and adds userID to the request context.
 func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
@@ -33,14 +48,7 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
             return
         }
 
-        log.Printf("Received token for verification: %s", tokenStr)
-
-        token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-            if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-                return nil, fmt.Errorf("unexpected signing method")
-            }
-            return config.JWTSecret, nil
-        })
+        token, err := jwt.Parse(tokenStr, config.JWTKeyFunc)
         if err != nil {
             log.Printf("Token parsing error for %s %s: %v", r.Method, r.URL.Path, err)
             http.Error(w, "Invalid token", http.StatusUnauthorized)
@@ -68,7 +76,62 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
             return
         }
 
+        if claims["typ"] == RefreshTokenType {
+            log.Printf("Refresh token used as access token for %s %s", r.Method, r.URL.Path)
+            http.Error(w, "Invalid token", http.StatusUnauthorized)
+            return
+        }
+
+        sessionID, _ := claims["sid"].(string)
+        tokenID, _ := claims["jti"].(string)
+        for _, id := range []string{tokenID, sessionID} {
+            if id == "" {
+                continue
+            }
+            revoked, err := tokenRevoked(r.Context(), id)
+            if err != nil {
+                log.Printf("Token revocation lookup failed for %s %s: %v", r.Method, r.URL.Path, err)
+                http.Error(w, "Failed to verify token", http.StatusInternalServerError)
+                return
+            }
+            if revoked {
+                log.Printf("Revoked token used for %s %s", r.Method, r.URL.Path)
+                http.Error(w, "Token revoked", http.StatusUnauthorized)
+                return
+            }
+        }
+
         ctx := context.WithValue(r.Context(), UserIDKey, uid)
+        ctx = context.WithValue(ctx, SessionIDKey, sessionID)
+        ctx = context.WithValue(ctx, TokenIDKey, tokenID)
+        ctx = context.WithValue(ctx, TokenExpiresAtKey, time.Unix(int64(exp), 0))
+        ctx = context.WithValue(ctx, RolesKey, tokenRoles(claims))
         next.ServeHTTP(w, r.WithContext(ctx))
     }
-}
\ No newline at end of file
+}
+// tokenRoles returns the roles claim of an access token. Tokens issued before roles were added to
+// the claims carry none and grant no permissions until they are refreshed.
+func tokenRoles(claims jwt.MapClaims) []string {
+    values, _ := claims["roles"].([]interface{})
+    roles := make([]string, 0, len(values))
+    for _, value := range values {
+        if role, ok := value.(string); ok {
+            roles = append(roles, role)
+        }
+    }
+    return roles
+}
+
+// tokenRevoked reports whether an access token or session ID is on the revocation list, caching
+// the answer briefly so most requests skip the lookup.
+func tokenRevoked(ctx context.Context, id string) (bool, error) {
+    if cached, ok := config.LoadFromCache(config.RevokedTokenCache, id); ok {
+        return cached.(bool), nil
+    }
+    revoked, err := services.IsTokenRevoked(ctx, id)
+    if err != nil {
+        return false, err
+    }
+    config.RevokedTokenCache.SetDefault(id, revoked)
+    return revoked, nil
+}
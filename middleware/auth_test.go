@@ -0,0 +1,109 @@
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/config"
+    "github.com/dzuura/neurodyx-be/repository"
+    "github.com/dzuura/neurodyx-be/services"
+    "github.com/golang-jwt/jwt/v5"
+    "github.com/patrickmn/go-cache"
+)
+
+// useTestAuth signs tokens with a shared test secret, resets the revocation cache and points the
+// services at an empty in-memory repository.
+func useTestAuth(t *testing.T) {
+    t.Helper()
+    secret, keys, signingKey := config.JWTSecret, config.JWTKeys, config.JWTSigningKey
+    revocations := config.RevokedTokenCache
+    config.JWTSecret = []byte("test-secret-that-is-at-least-32-bytes")
+    config.JWTKeys, config.JWTSigningKey = nil, nil
+    config.RevokedTokenCache = cache.New(time.Minute, time.Minute)
+    services.SetRepository(repository.NewMemoryRepository())
+    t.Cleanup(func() {
+        config.JWTSecret, config.JWTKeys, config.JWTSigningKey = secret, keys, signingKey
+        config.RevokedTokenCache = revocations
+    })
+}
+
+// signTestToken signs an access token for userID with the given extra claims.
+func signTestToken(t *testing.T, userID string, extra jwt.MapClaims) string {
+    t.Helper()
+    claims := jwt.MapClaims{
+        "uid": userID,
+        "exp": time.Now().Add(time.Hour).Unix(),
+    }
+    for name, value := range extra {
+        claims[name] = value
+    }
+    token, err := config.SignJWT(claims)
+    if err != nil {
+        t.Fatalf("SignJWT() error = %v", err)
+    }
+    return token
+}
+
+// serveAuthenticated runs a request with token through AuthMiddleware and returns the response
+// together with the user ID the wrapped handler saw.
+func serveAuthenticated(token string) (*httptest.ResponseRecorder, string) {
+    var userID string
+    handler := AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+        userID, _ = r.Context().Value(UserIDKey).(string)
+        w.WriteHeader(http.StatusOK)
+    })
+    req := httptest.NewRequest(http.MethodGet, "/progress/weekly", nil)
+    if token != "" {
+        req.Header.Set("Authorization", "Bearer "+token)
+    }
+    rec := httptest.NewRecorder()
+    handler(rec, req)
+    return rec, userID
+}
+
+func TestAuthMiddleware(t *testing.T) {
+    useTestAuth(t)
+
+    tests := []struct {
+        name       string
+        token      string
+        wantStatus int
+    }{
+        {"valid token", signTestToken(t, "user", jwt.MapClaims{"jti": "token-1"}), http.StatusOK},
+        {"missing token", "", http.StatusUnauthorized},
+        {"malformed token", "not-a-jwt", http.StatusUnauthorized},
+        {"refresh token", signTestToken(t, "user", jwt.MapClaims{"typ": RefreshTokenType}), http.StatusUnauthorized},
+        {"expired token", signTestToken(t, "user", jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}), http.StatusUnauthorized},
+        {"missing uid", signTestToken(t, "", jwt.MapClaims{"uid": nil}), http.StatusUnauthorized},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            rec, _ := serveAuthenticated(tt.token)
+            if rec.Code != tt.wantStatus {
+                t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+            }
+        })
+    }
+}
+
+func TestAuthMiddlewareRejectsRevokedToken(t *testing.T) {
+    useTestAuth(t)
+    ctx := context.Background()
+    token := signTestToken(t, "user", jwt.MapClaims{"jti": "token-1"})
+    other := signTestToken(t, "user", jwt.MapClaims{"jti": "token-2"})
+
+    // The revocation is read from the store, as it is on instances that did not handle the logout.
+    if err := services.RevokeAccessToken(ctx, "user", "token-1", time.Now().Add(time.Hour)); err != nil {
+        t.Fatalf("RevokeAccessToken() error = %v", err)
+    }
+
+    if rec, _ := serveAuthenticated(token); rec.Code != http.StatusUnauthorized {
+        t.Errorf("revoked token: status = %d, want 401", rec.Code)
+    }
+    if rec, userID := serveAuthenticated(other); rec.Code != http.StatusOK || userID != "user" {
+        t.Errorf("other token: status = %d, user = %q, want 200 for user", rec.Code, userID)
+    }
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+    "log"
+    "net/http"
+
+    "github.com/dzuura/neurodyx-be/services"
+)
+
+// PermissionMiddleware ensures that one of the roles in the user's access token grants permission.
+// The roles come from the token claims, so no storage lookup is made.
+func PermissionMiddleware(permission string, next http.HandlerFunc) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        userID, ok := r.Context().Value(UserIDKey).(string)
+        if !ok {
+            http.Error(w, "User ID missing", http.StatusUnauthorized)
+            return
+        }
+
+        roles, _ := r.Context().Value(RolesKey).([]string)
+        if !services.HasPermission(roles, permission) {
+            log.Printf("Permission %s denied for userID %s with roles %v on %s %s", permission, userID, roles, r.Method, r.URL.Path)
+            http.Error(w, "Permission "+permission+" required", http.StatusForbidden)
+            return
+        }
+
+        next.ServeHTTP(w, r)
+    }
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestPermissionMiddleware(t *testing.T) {
+    tests := []struct {
+        name       string
+        userID     interface{}
+        roles      interface{}
+        wantStatus int
+    }{
+        {"granted", "editor", []string{models.RoleContentEditor}, http.StatusOK},
+        {"not granted", "learner", []string{models.RoleLearner}, http.StatusForbidden},
+        {"token without roles", "learner", nil, http.StatusForbidden},
+        {"missing user", nil, []string{models.RoleAdmin}, http.StatusUnauthorized},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            called := false
+            handler := PermissionMiddleware(models.PermissionManageQuestions, func(w http.ResponseWriter, r *http.Request) {
+                called = true
+                w.WriteHeader(http.StatusOK)
+            })
+
+            ctx := context.Background()
+            if tt.userID != nil {
+                ctx = context.WithValue(ctx, UserIDKey, tt.userID)
+            }
+            if tt.roles != nil {
+                ctx = context.WithValue(ctx, RolesKey, tt.roles)
+            }
+            req := httptest.NewRequest(http.MethodPost, "/api/questions", nil).WithContext(ctx)
+            rec := httptest.NewRecorder()
+            handler(rec, req)
+
+            if rec.Code != tt.wantStatus {
+                t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+            }
+            if called != (tt.wantStatus == http.StatusOK) {
+                t.Errorf("handler called = %v, want %v", called, tt.wantStatus == http.StatusOK)
+            }
+        })
+    }
+}
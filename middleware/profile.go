@@ -0,0 +1,66 @@
+package middleware
+
+import (
+    "context"
+    "log"
+    "net/http"
+
+    "github.com/dzuura/neurodyx-be/config"
+    "github.com/dzuura/neurodyx-be/services"
+)
+
+// ProfileHeader selects the child profile a guardian is acting for.
+const ProfileHeader = "X-Profile-ID"
+
+// AccountIDKey is the key used to store the signed-in user ID in the context, which differs from
+// UserIDKey while a child profile is selected
+const AccountIDKey contextKey = "accountID"
+
+// ProfileMiddleware lets a guardian act for one of their child profiles. When the request selects
+// a profile with ProfileHeader, the profile ID replaces the user ID in the context, so the handler
+// reads and stores the child's data instead of the guardian's.
+func ProfileMiddleware(next http.HandlerFunc) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        userID, ok := r.Context().Value(UserIDKey).(string)
+        if !ok {
+            http.Error(w, "User ID missing", http.StatusUnauthorized)
+            return
+        }
+
+        ctx := context.WithValue(r.Context(), AccountIDKey, userID)
+        profileID := r.Header.Get(ProfileHeader)
+        if profileID != "" && profileID != userID {
+            isChild, err := childProfileOf(r.Context(), userID, profileID)
+            if err != nil {
+                log.Printf("Child profile lookup failed for %s %s: %v", r.Method, r.URL.Path, err)
+                http.Error(w, "Failed to verify profile", http.StatusInternalServerError)
+                return
+            }
+            if !isChild {
+                log.Printf("Profile %s is not a child of userID %s for %s %s", profileID, userID, r.Method, r.URL.Path)
+                http.Error(w, "Profile not managed by this account", http.StatusForbidden)
+                return
+            }
+            ctx = context.WithValue(ctx, UserIDKey, profileID)
+        }
+
+        next.ServeHTTP(w, r.WithContext(ctx))
+    }
+}
+
+// childProfileOf reports whether profileID is one of the guardian's child profiles. Profiles cannot
+// be removed from a guardian, so only positive answers are cached.
+func childProfileOf(ctx context.Context, guardianID, profileID string) (bool, error) {
+    key := guardianID + "/" + profileID
+    if _, ok := config.LoadFromCache(config.ChildProfileCache, key); ok {
+        return true, nil
+    }
+    isChild, err := services.IsChildProfile(ctx, guardianID, profileID)
+    if err != nil {
+        return false, err
+    }
+    if isChild {
+        config.StoreInCache(config.ChildProfileCache, key, true)
+    }
+    return isChild, nil
+}
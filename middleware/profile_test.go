@@ -0,0 +1,70 @@
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/config"
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+    "github.com/dzuura/neurodyx-be/services"
+    "github.com/patrickmn/go-cache"
+)
+
+func TestProfileMiddleware(t *testing.T) {
+    services.SetRepository(repository.NewMemoryRepository())
+    profiles := config.ChildProfileCache
+    config.ChildProfileCache = cache.New(time.Minute, time.Minute)
+    t.Cleanup(func() { config.ChildProfileCache = profiles })
+
+    ctx := context.Background()
+    child, err := services.CreateChildProfile(ctx, "guardian", models.ChildProfile{Name: "Ayu"})
+    if err != nil {
+        t.Fatalf("CreateChildProfile() error = %v", err)
+    }
+    otherChild, err := services.CreateChildProfile(ctx, "other-guardian", models.ChildProfile{Name: "Budi"})
+    if err != nil {
+        t.Fatalf("CreateChildProfile() error = %v", err)
+    }
+
+    tests := []struct {
+        name       string
+        profileID  string
+        wantStatus int
+        wantUserID string
+    }{
+        {"no profile", "", http.StatusOK, "guardian"},
+        {"own user ID", "guardian", http.StatusOK, "guardian"},
+        {"own child", child.ID, http.StatusOK, child.ID},
+        {"another guardian's child", otherChild.ID, http.StatusForbidden, ""},
+        {"another guardian's account", "other-guardian", http.StatusForbidden, ""},
+        {"unknown profile", "missing", http.StatusForbidden, ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var userID, accountID string
+            handler := ProfileMiddleware(func(w http.ResponseWriter, r *http.Request) {
+                userID, _ = r.Context().Value(UserIDKey).(string)
+                accountID, _ = r.Context().Value(AccountIDKey).(string)
+                w.WriteHeader(http.StatusOK)
+            })
+            req := httptest.NewRequest(http.MethodGet, "/api/progress/weekly", nil)
+            req = req.WithContext(context.WithValue(req.Context(), UserIDKey, "guardian"))
+            if tt.profileID != "" {
+                req.Header.Set(ProfileHeader, tt.profileID)
+            }
+            rec := httptest.NewRecorder()
+            handler(rec, req)
+
+            if rec.Code != tt.wantStatus {
+                t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+            }
+            if tt.wantStatus == http.StatusOK && (userID != tt.wantUserID || accountID != "guardian") {
+                t.Errorf("user = %q, account = %q, want %q acting from guardian", userID, accountID, tt.wantUserID)
+            }
+        })
+    }
+}
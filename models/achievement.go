@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Achievement is a badge unlocked by a user.
+type Achievement struct {
+    BadgeID     string    `json:"badgeID"`
+    Name        string    `json:"name"`
+    Description string    `json:"description"`
+    UnlockedAt  time.Time `json:"unlockedAt"`
+}
+
+// BadgeStatus reports a user's progress toward a badge and whether it has been unlocked.
+type BadgeStatus struct {
+    BadgeID     string     `json:"badgeID"`
+    Name        string     `json:"name"`
+    Description string     `json:"description"`
+    Unlocked    bool       `json:"unlocked"`
+    UnlockedAt  *time.Time `json:"unlockedAt,omitempty"`
+    Progress    int        `json:"progress"`
+    Target      int        `json:"target"`
+}
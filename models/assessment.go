@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // AssessmentQuestion represents a single assessment question with various types and answer formats.
 type AssessmentQuestion struct {
     ID             string            `json:"id"`
@@ -8,12 +10,15 @@ type AssessmentQuestion struct {
     Content        string            `json:"content,omitempty"`
     ImageURL       string            `json:"imageURL,omitempty"`
     SoundURL       string            `json:"soundURL,omitempty"`
+    AnswerFormat   string            `json:"answerFormat,omitempty"`
     Options        []string          `json:"options,omitempty"`
     LeftItems      []string          `json:"leftItems,omitempty"`
     RightItems     []string          `json:"rightItems,omitempty"`
     CorrectAnswer  string            `json:"correctAnswer,omitempty"`
     CorrectSequence []string         `json:"correctSequence,omitempty"`
     CorrectPairs   map[string]string `json:"correctPairs,omitempty"`
+    CorrectOptions []string          `json:"correctOptions,omitempty"`
+    Matching       *MatchOptions     `json:"matching,omitempty"`
 }
 
 // AssessmentSubmission represents a user's submission for an assessment question.
@@ -24,8 +29,127 @@ type AssessmentSubmission struct {
 
 // AssessmentResult represents the result of a user's assessment for a specific type.
 type AssessmentResult struct {
-    Type           string `json:"type"`
-    CorrectAnswers int    `json:"correctAnswers"`
-    TotalQuestions int    `json:"totalQuestions"`
-    Status         string `json:"status"`
-}
\ No newline at end of file
+    Type           string           `json:"type"`
+    CorrectAnswers int              `json:"correctAnswers"`
+    Score          float64          `json:"score"`
+    TotalQuestions int              `json:"totalQuestions"`
+    Status         string           `json:"status"`
+    Answers        []AnswerFeedback `json:"answers,omitempty"`
+}
+
+// AssessmentSubmissionRecord represents a scored assessment answer stored for a user.
+type AssessmentSubmissionRecord struct {
+    QuestionID     string       `json:"questionID"`
+    Type           string       `json:"type"`
+    Category       string       `json:"category"`
+    CorrectAnswers int          `json:"correctAnswers"`
+    Score          float64      `json:"score"`
+    Items          []ItemResult `json:"items,omitempty"`
+    Match          *MatchResult `json:"match,omitempty"`
+    Answer         interface{}  `json:"answer"`
+    Status         string       `json:"status"`
+    Timestamp      time.Time    `json:"timestamp"`
+}
+
+// Assessment session statuses.
+const (
+    SessionStatusInProgress = "in_progress"
+    SessionStatusFinished   = "finished"
+)
+
+// AssessmentSession tracks a user's pass through the assessment questions of one type.
+// The question set is fixed when the session starts.
+type AssessmentSession struct {
+    ID             string             `json:"id"`
+    UserID         string             `json:"userID"`
+    Type           string             `json:"type"`
+    Status         string             `json:"status"`
+    QuestionIDs    []string           `json:"questionIDs"`
+    Answers        []AssessmentAnswer `json:"answers"`
+    CorrectAnswers int                `json:"correctAnswers"`
+    Score          float64            `json:"score"`
+    StartedAt      time.Time          `json:"startedAt"`
+    UpdatedAt      time.Time          `json:"updatedAt"`
+    FinishedAt     *time.Time         `json:"finishedAt,omitempty"`
+}
+
+// AssessmentAnswer records a scored answer to one question of an assessment session or attempt.
+type AssessmentAnswer struct {
+    QuestionID string       `json:"questionID"`
+    Category   string       `json:"category"`
+    Answer     interface{}  `json:"answer"`
+    Correct    bool         `json:"correct"`
+    Score      float64      `json:"score"`
+    Items      []ItemResult `json:"items,omitempty"`
+    Match      *MatchResult `json:"match,omitempty"`
+    AnsweredAt time.Time    `json:"answeredAt"`
+}
+
+// CategoryScore summarizes the answers of one category within an assessment attempt.
+type CategoryScore struct {
+    Category       string  `json:"category"`
+    CorrectAnswers int     `json:"correctAnswers"`
+    Score          float64 `json:"score"`
+    TotalQuestions int     `json:"totalQuestions"`
+}
+
+// AssessmentAttempt is an immutable record of one completed assessment run for a type. Partial
+// attempts record a batch of answers submitted outside a session, which may not cover the whole type.
+type AssessmentAttempt struct {
+    ID             string             `json:"id"`
+    UserID         string             `json:"userID"`
+    Type           string             `json:"type"`
+    SessionID      string             `json:"sessionID,omitempty"`
+    Partial        bool               `json:"partial"`
+    Answers        []AssessmentAnswer `json:"answers"`
+    Categories     []CategoryScore    `json:"categories"`
+    CorrectAnswers int                `json:"correctAnswers"`
+    Score          float64            `json:"score"`
+    TotalQuestions int                `json:"totalQuestions"`
+    CompletedAt    time.Time          `json:"completedAt"`
+}
+
+// ScoreChange compares the scores of two assessment attempts.
+type ScoreChange struct {
+    FromCorrect  int     `json:"fromCorrect"`
+    ToCorrect    int     `json:"toCorrect"`
+    CorrectDelta int     `json:"correctDelta"`
+    FromScore    float64 `json:"fromScore"`
+    ToScore      float64 `json:"toScore"`
+    ScoreDelta   float64 `json:"scoreDelta"`
+}
+
+// CategoryChange compares one category's scores between two assessment attempts.
+type CategoryChange struct {
+    Category string `json:"category"`
+    ScoreChange
+}
+
+// Question changes reported when diffing assessment attempts.
+const (
+    QuestionImproved  = "improved"
+    QuestionRegressed = "regressed"
+    QuestionUnchanged = "unchanged"
+    QuestionAdded     = "added"
+    QuestionRemoved   = "removed"
+)
+
+// QuestionChange compares the answers to one question between two assessment attempts.
+// FromScore or ToScore is nil when the question was not answered in that attempt.
+type QuestionChange struct {
+    QuestionID string   `json:"questionID"`
+    Category   string   `json:"category"`
+    Change     string   `json:"change"`
+    FromScore  *float64 `json:"fromScore"`
+    ToScore    *float64 `json:"toScore"`
+}
+
+// AttemptDiff compares two assessment attempts of the same type.
+type AttemptDiff struct {
+    Type       string           `json:"type"`
+    From       string           `json:"from"`
+    To         string           `json:"to"`
+    Overall    ScoreChange      `json:"overall"`
+    Categories []CategoryChange `json:"categories"`
+    Questions  []QuestionChange `json:"questions"`
+}
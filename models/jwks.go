@@ -0,0 +1,19 @@
+package models
+
+// JWK is the public half of a token signing key in JSON Web Key form. RSA keys set N and E;
+// Ed25519 keys set Crv and X.
+type JWK struct {
+    Kty string `json:"kty"`
+    Use string `json:"use"`
+    Alg string `json:"alg"`
+    Kid string `json:"kid"`
+    N   string `json:"n,omitempty"`
+    E   string `json:"e,omitempty"`
+    Crv string `json:"crv,omitempty"`
+    X   string `json:"x,omitempty"`
+}
+
+// JWKSet is a JSON Web Key Set, served so other services can verify issued tokens.
+type JWKSet struct {
+    Keys []JWK `json:"keys"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Levels a modality or category score is classified into.
+const (
+    LevelStrength = "strength"
+    LevelAverage  = "average"
+    LevelWeakness = "weakness"
+)
+
+// ModalityProfile summarizes the user's latest assessment attempt for one learning-style type.
+// Score is normalized to the range 0 to 1.
+type ModalityProfile struct {
+    Type           string  `json:"type"`
+    Assessed       bool    `json:"assessed"`
+    CorrectAnswers int     `json:"correctAnswers"`
+    TotalQuestions int     `json:"totalQuestions"`
+    Score          float64 `json:"score"`
+    Level          string  `json:"level,omitempty"`
+}
+
+// CategoryProfile summarizes the user's normalized score in one category of a type.
+type CategoryProfile struct {
+    Type           string  `json:"type"`
+    Category       string  `json:"category"`
+    CorrectAnswers int     `json:"correctAnswers"`
+    TotalQuestions int     `json:"totalQuestions"`
+    Score          float64 `json:"score"`
+    Level          string  `json:"level"`
+}
+
+// LearningProfile is the learning-style profile derived from the user's assessment attempts.
+type LearningProfile struct {
+    DominantModality string            `json:"dominantModality,omitempty"`
+    Modalities       []ModalityProfile `json:"modalities"`
+    Categories       []CategoryProfile `json:"categories"`
+    Strengths        []string          `json:"strengths"`
+    Weaknesses       []string          `json:"weaknesses"`
+    RiskAreas        []CategoryProfile `json:"riskAreas"`
+    Complete         bool              `json:"complete"`
+    ComputedAt       time.Time         `json:"computedAt"`
+}
@@ -2,16 +2,81 @@ package models
 
 import "time"
 
-// DailyProgress represents a user's daily progress for therapy activities.
+// Metrics a daily goal can be measured in.
+const (
+    GoalMetricSessions       = "sessions"
+    GoalMetricMinutes        = "minutes"
+    GoalMetricCorrectAnswers = "correctAnswers"
+)
+
+// DailyGoal is the amount of therapy a user aims to complete each day.
+type DailyGoal struct {
+    Metric    string    `json:"metric"`
+    Target    int       `json:"target"`
+    SetBy     string    `json:"setBy,omitempty"`
+    UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// ActivityBreakdown totals the therapy a user practiced in one type, or in one category of a type.
+type ActivityBreakdown struct {
+    Type           string `json:"type"`
+    Category       string `json:"category,omitempty"`
+    Sessions       int    `json:"sessions"`
+    CorrectAnswers int    `json:"correctAnswers"`
+    TotalQuestions int    `json:"totalQuestions"`
+}
+
+// DailyProgress represents a user's daily progress for therapy activities. TherapyCount counts
+// submission batches with at least one scored answer. Goal is the goal in effect when the day
+// was last updated; Achieved and CompletionPercent are measured against it.
 type DailyProgress struct {
-    UserID         string    `json:"userID"`
-    Date           time.Time `json:"date"`
-    TherapyCount   int       `json:"therapyCount"`
-    StreakAchieved bool      `json:"streakAchieved"`
+    UserID            string              `json:"userID"`
+    Date              time.Time           `json:"date"`
+    TherapyCount      int                 `json:"therapyCount"`
+    CorrectAnswers    int                 `json:"correctAnswers"`
+    TotalQuestions    int                 `json:"totalQuestions"`
+    SecondsSpent      int                 `json:"secondsSpent"`
+    Types             []ActivityBreakdown `json:"types,omitempty"`
+    Categories        []ActivityBreakdown `json:"categories,omitempty"`
+    StreakAchieved    bool                `json:"streakAchieved"`
+    Goal              DailyGoal           `json:"goal"`
+    Achieved          int                 `json:"achieved"`
+    CompletionPercent float64             `json:"completionPercent"`
 }
 
 // ProgressDetail represents the detailed progress for a specific month.
 type ProgressDetail struct {
-    Date           time.Time `json:"date"`
-    Status         string    `json:"status"`
+    Date              time.Time `json:"date"`
+    Status            string    `json:"status"`
+    Goal              DailyGoal `json:"goal"`
+    Achieved          int       `json:"achieved"`
+    CompletionPercent float64   `json:"completionPercent"`
+}
+
+// StreakSummary tracks a user's consecutive days of reaching the daily therapy goal.
+// Freeze tokens are earned by keeping a streak going and cover missed days. CorrectAnswers keeps
+// the running total of correct therapy answers per question type; it is nil for summaries stored
+// before the totals were tracked.
+type StreakSummary struct {
+    UserID         string         `json:"userID"`
+    CurrentStreak  int            `json:"currentStreak"`
+    LongestStreak  int            `json:"longestStreak"`
+    LastStreakDate time.Time      `json:"lastStreakDate,omitempty"`
+    AchievedToday  bool           `json:"achievedToday"`
+    FreezeTokens   int            `json:"freezeTokens"`
+    FreezesUsed    int            `json:"freezesUsed"`
+    CorrectAnswers map[string]int `json:"correctAnswers,omitempty"`
+}
+
+// WeeklyProgress represents a user's progress for the last 7 days together with their streak.
+type WeeklyProgress struct {
+    Days   []DailyProgress `json:"days"`
+    Streak StreakSummary   `json:"streak"`
+}
+
+// ProgressSummary represents a user's streak and today's progress toward the daily goal.
+type ProgressSummary struct {
+    Streak    StreakSummary `json:"streak"`
+    Today     DailyProgress `json:"today"`
+    DailyGoal DailyGoal     `json:"dailyGoal"`
 }
\ No newline at end of file
@@ -0,0 +1,97 @@
+package models
+
+// Question kinds recorded in the question index.
+const (
+    QuestionKindAssessment = "assessment"
+    QuestionKindTherapy    = "therapy"
+)
+
+// QuestionLocation records the kind, type and category under which a question is stored.
+type QuestionLocation struct {
+    QuestionID string `json:"questionID"`
+    Kind       string `json:"kind"`
+    Type       string `json:"type"`
+    Category   string `json:"category"`
+}
+
+// QuestionIndexRebuild reports the outcome of rebuilding the question index. SkippedQuestionIDs
+// lists malformed question documents that were left out of the index.
+type QuestionIndexRebuild struct {
+    IndexedQuestions   int      `json:"indexedQuestions"`
+    SkippedQuestionIDs []string `json:"skippedQuestionIDs"`
+}
+
+// Answer formats a question can declare to select the validator that scores it.
+const (
+    AnswerFormatExactString  = "exact_string"
+    AnswerFormatSequence     = "ordered_sequence"
+    AnswerFormatPairs        = "pair_matching"
+    AnswerFormatSingleChoice = "single_choice"
+    AnswerFormatMultiSelect  = "multi_select"
+)
+
+// AnswerKey holds the answer fields shared by assessment and therapy questions.
+type AnswerKey struct {
+    Options         []string
+    CorrectAnswer   string
+    CorrectSequence []string
+    CorrectPairs    map[string]string
+    CorrectOptions  []string
+    Matching        *MatchOptions
+}
+
+// ItemResult reports whether a single sequence position or pair was answered correctly.
+type ItemResult struct {
+    Index    int    `json:"index"`
+    Left     string `json:"left,omitempty"`
+    Expected string `json:"expected"`
+    Answer   string `json:"answer"`
+    Correct  bool   `json:"correct"`
+}
+
+// AnswerScore is the outcome of validating a single answer. Score is the fraction of
+// credit earned, from 0 to 1.
+type AnswerScore struct {
+    Correct bool         `json:"correct"`
+    Score   float64      `json:"score"`
+    Items   []ItemResult `json:"items,omitempty"`
+    Match   *MatchResult `json:"match,omitempty"`
+}
+
+// AnswerFeedback reports how a submitted answer to a question was scored.
+type AnswerFeedback struct {
+    QuestionID string `json:"questionID"`
+    AnswerScore
+}
+
+// Normalizers that can be applied to string answers before they are compared.
+const (
+    NormalizeCase        = "case"
+    NormalizeUnicode     = "unicode"
+    NormalizePunctuation = "punctuation"
+    NormalizeNone        = "none"
+)
+
+// Match qualities recorded for string answers, from strongest to weakest.
+const (
+    MatchExact      = "exact"
+    MatchNormalized = "normalized"
+    MatchFuzzy      = "fuzzy"
+    MatchPhonetic   = "phonetic"
+    MatchNone       = "none"
+)
+
+// MatchOptions configures how a question's string answers are compared. When Normalizers is
+// empty the case, unicode and punctuation normalizers are applied; "none" disables them.
+type MatchOptions struct {
+    Normalizers     []string `json:"normalizers,omitempty"`
+    MaxEditDistance int      `json:"maxEditDistance,omitempty"`
+    Phonetic        bool     `json:"phonetic,omitempty"`
+}
+
+// MatchResult records how closely a string answer matched the correct answer.
+type MatchResult struct {
+    Quality    string `json:"quality"`
+    Normalized string `json:"normalized"`
+    Distance   int    `json:"distance"`
+}
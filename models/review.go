@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ReviewState is a user's spaced-repetition schedule for one therapy question, following SM-2.
+type ReviewState struct {
+    QuestionID     string    `json:"questionID"`
+    Type           string    `json:"type"`
+    Category       string    `json:"category"`
+    EaseFactor     float64   `json:"easeFactor"`
+    IntervalDays   int       `json:"intervalDays"`
+    Repetitions    int       `json:"repetitions"`
+    LastQuality    int       `json:"lastQuality"`
+    LastReviewedAt time.Time `json:"lastReviewedAt"`
+    DueAt          time.Time `json:"dueAt"`
+}
+
+// DueReview is a therapy question due for review together with its schedule.
+type DueReview struct {
+    Question TherapyQuestion `json:"question"`
+    Review   ReviewState     `json:"review"`
+}
@@ -1,14 +1,116 @@
 package models
 
-// ScreeningQuestion represents a screening question with unique fields.
+import "time"
+
+// ScreeningQuestion represents a screening question with unique fields. Weight scales the
+// question's share of the screening score, and Domain optionally groups it into a sub-score.
 type ScreeningQuestion struct {
-    ID       string `json:"id,omitempty"`
-    AgeGroup string `json:"ageGroup"`
-    Question string `json:"question"`
+    ID       string  `json:"id,omitempty"`
+    AgeGroup string  `json:"ageGroup"`
+    Question string  `json:"question"`
+    Weight   float64 `json:"weight,omitempty"`
+    Domain   string  `json:"domain,omitempty"`
+}
+
+// Weights a screening question can carry.
+const (
+    DefaultScreeningWeight = 1.0
+    MaxScreeningWeight     = 10.0
+)
+
+// ScreeningDomainRule sets the risk thresholds of one domain's sub-score. A domain with a rule
+// can raise the overall risk level to its own.
+type ScreeningDomainRule struct {
+    Domain      string  `json:"domain"`
+    LowMax      float64 `json:"lowMax"`
+    ModerateMax float64 `json:"moderateMax"`
+}
+
+// ScreeningRule is the scoring rule of an age group. Weighted scores up to LowMax percent are low
+// risk, up to ModerateMax moderate and above that high. A positive answer to any of CriticalItems
+// raises the risk level to at least CriticalRiskLevel.
+type ScreeningRule struct {
+    AgeGroup          string                `json:"ageGroup"`
+    LowMax            float64               `json:"lowMax"`
+    ModerateMax       float64               `json:"moderateMax"`
+    Domains           []ScreeningDomainRule `json:"domains,omitempty"`
+    CriticalItems     []string              `json:"criticalItems,omitempty"`
+    CriticalRiskLevel string                `json:"criticalRiskLevel,omitempty"`
+    UpdatedBy         string                `json:"updatedBy,omitempty"`
+    UpdatedAt         time.Time             `json:"updatedAt,omitempty"`
+}
+
+// ScreeningDomainScore is the weighted sub-score of the questions tagged with one domain.
+// Driver marks domains whose risk level matches the screening's overall risk level.
+type ScreeningDomainScore struct {
+    Domain    string  `json:"domain"`
+    Score     float64 `json:"score"`
+    RiskLevel string  `json:"riskLevel"`
+    Driver    bool    `json:"driver"`
+}
+
+// ScreeningScore is a scored screening together with the reasons for its risk level.
+type ScreeningScore struct {
+    Score         float64                `json:"score"`
+    RiskLevel     string                 `json:"riskLevel"`
+    Domains       []ScreeningDomainScore `json:"domains,omitempty"`
+    CriticalItems []string               `json:"criticalItems,omitempty"`
+    Reasons       []string               `json:"reasons"`
 }
 
-// ScreeningSubmission represents a user's submission for a screening question.
+// ScreeningAnswer is a user's yes or no answer to one screening question.
+type ScreeningAnswer struct {
+    QuestionID string `json:"questionID"`
+    Answer     bool   `json:"answer"`
+}
+
+// ScreeningSubmission represents a user's submission for a screening question. Responses are
+// keyed by question ID; the positional Answers follow the question order of Version and are kept
+// for older clients. Version is the question-set version the user was shown.
 type ScreeningSubmission struct {
-    AgeGroup string  `json:"ageGroup"`
-    Answers  []bool  `json:"answers,omitempty"`
+    AgeGroup  string            `json:"ageGroup"`
+    Version   string            `json:"version,omitempty"`
+    Responses []ScreeningAnswer `json:"responses,omitempty"`
+    Answers   []bool            `json:"answers,omitempty"`
+}
+
+// ScreeningAnswerSet is a submission bound to the current questions of its age group. Answers[i]
+// answers Questions[i]; Ignored lists answered question IDs that are no longer in the set.
+type ScreeningAnswerSet struct {
+    Version   string
+    Questions []ScreeningQuestion
+    Answers   []bool
+    Ignored   []string
+}
+
+// Trends of a screening's risk level compared with the user's previous screening.
+const (
+    ScreeningTrendFirst     = "first"
+    ScreeningTrendImproved  = "improved"
+    ScreeningTrendUnchanged = "unchanged"
+    ScreeningTrendWorsened  = "worsened"
+)
+
+// ScreeningResult represents a user's scored screening submission. Answers[i] answers the question
+// QuestionIDs[i]. Every screening is kept as a dated record; Trend compares its risk level with
+// PreviousRiskLevel from the screening before it.
+type ScreeningResult struct {
+    ID                 string                 `json:"id,omitempty"`
+    AgeGroup           string                 `json:"ageGroup"`
+    QuestionSetVersion string                 `json:"questionSetVersion,omitempty"`
+    QuestionIDs        []string               `json:"questionIDs,omitempty"`
+    Answers            []bool                 `json:"answers"`
+    Score              float64                `json:"score"`
+    RiskLevel          string                 `json:"riskLevel"`
+    Domains            []ScreeningDomainScore `json:"domains,omitempty"`
+    CriticalItems      []string               `json:"criticalItems,omitempty"`
+    PreviousRiskLevel  string                 `json:"previousRiskLevel,omitempty"`
+    Trend              string                 `json:"trend,omitempty"`
+    Timestamp          time.Time              `json:"timestamp"`
+}
+
+// ScreeningHistory holds a user's latest screening result and every result, newest first.
+type ScreeningHistory struct {
+    Latest  *ScreeningResult  `json:"latest"`
+    History []ScreeningResult `json:"history"`
 }
\ No newline at end of file
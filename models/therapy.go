@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // TherapyQuestion represents a therapy question with unique fields.
 type TherapyQuestion struct {
     ID             string            `json:"id"`
@@ -9,31 +11,77 @@ type TherapyQuestion struct {
     Description    string            `json:"description,omitempty"`
     ImageURL       string            `json:"imageURL,omitempty"`
     SoundURL       string            `json:"soundURL,omitempty"`
+    AnswerFormat   string            `json:"answerFormat,omitempty"`
     Options        []string          `json:"options,omitempty"`
     LeftItems      []string          `json:"leftItems,omitempty"`
     RightItems     []string          `json:"rightItems,omitempty"`
     CorrectAnswer  string            `json:"correctAnswer,omitempty"`
     CorrectSequence []string         `json:"correctSequence,omitempty"`
     CorrectPairs   map[string]string `json:"correctPairs,omitempty"`
+    CorrectOptions []string          `json:"correctOptions,omitempty"`
+    Matching       *MatchOptions     `json:"matching,omitempty"`
+    Difficulty     int               `json:"difficulty,omitempty"`
 }
 
+// Difficulty levels a therapy question can carry, from easiest to hardest.
+const (
+    MinDifficulty     = 1
+    MaxDifficulty     = 5
+    DefaultDifficulty = 3
+)
+
 // TherapySubmission represents a user's submission for a therapy question.
 type TherapySubmission struct {
     QuestionID string      `json:"questionID"`
     Answer     interface{} `json:"answer"`
 }
 
+// TherapySubmissionRecord represents a scored therapy answer stored for a user.
+type TherapySubmissionRecord struct {
+    QuestionID     string       `json:"questionID"`
+    Type           string       `json:"type"`
+    Category       string       `json:"category"`
+    CorrectAnswers int          `json:"correctAnswers"`
+    Score          float64      `json:"score"`
+    Items          []ItemResult `json:"items,omitempty"`
+    Match          *MatchResult `json:"match,omitempty"`
+    Answer         interface{}  `json:"answer"`
+    Status         string       `json:"status"`
+    Timestamp      time.Time    `json:"timestamp"`
+}
+
 // TherapyResult represents the result of a user's therapy session.
 type TherapyResult struct {
-    Type           string `json:"type"`
-    Category       string `json:"category"`
-    CorrectAnswers int    `json:"correctAnswers"`
-    TotalQuestions int    `json:"totalQuestions"`
-    Status         string `json:"status"`
+    Type           string           `json:"type"`
+    Category       string           `json:"category"`
+    CorrectAnswers int              `json:"correctAnswers"`
+    Score          float64          `json:"score"`
+    TotalQuestions int              `json:"totalQuestions"`
+    Status         string           `json:"status"`
+    Answers        []AnswerFeedback `json:"answers,omitempty"`
 }
 
 // TherapyCategory represents a therapy category with its description.
 type TherapyCategory struct {
     Category    string `json:"category"`
     Description string `json:"description"`
+}
+
+// TherapyRecommendation is one ranked item of a user's daily therapy plan.
+type TherapyRecommendation struct {
+    Rank        int     `json:"rank"`
+    Type        string  `json:"type"`
+    Category    string  `json:"category"`
+    Description string  `json:"description,omitempty"`
+    Priority    float64 `json:"priority"`
+    Reason      string  `json:"reason"`
+}
+
+// TherapyPlan is the ordered list of therapy categories recommended to a user for the day,
+// together with the questions due for review.
+type TherapyPlan struct {
+    RiskLevel   string                  `json:"riskLevel,omitempty"`
+    Items       []TherapyRecommendation `json:"items"`
+    Reviews     []DueReview             `json:"reviews"`
+    GeneratedAt time.Time               `json:"generatedAt"`
 }
\ No newline at end of file
@@ -14,14 +14,69 @@ type User struct {
     Username           string       `json:"username,omitempty"`
     Email              string       `json:"email,omitempty"`
     CreatedAt          time.Time    `json:"createdAt,omitempty"`
-    RefreshTokenCreatedAt time.Time `json:"refreshTokenCreatedAt,omitempty"`
-    RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt,omitempty"`
+    IsAdmin            bool         `json:"isAdmin,omitempty"`
+    Roles              []string     `json:"roles,omitempty"`
+    TherapistIDs       []string     `json:"therapistIDs,omitempty"`
+    LearningProfile    *LearningProfile `json:"learningProfile,omitempty"`
+    Settings           UserSettings     `json:"settings"`
+    DailyGoal          *DailyGoal       `json:"dailyGoal,omitempty"`
 }
 
-// AuthRequest represents a request for authentication with unique fields.
+// Roles a user can hold. Users without stored roles are learners, or admins when they have the
+// legacy isAdmin flag.
+const (
+    RoleLearner       = "learner"
+    RoleParent        = "parent"
+    RoleTherapist     = "therapist"
+    RoleContentEditor = "content-editor"
+    RoleAdmin         = "admin"
+)
+
+// Permissions checked by routes beyond signing in. Roles map to permissions in services/role.go.
+const (
+    PermissionManageQuestions      = "questions:manage"
+    PermissionManageScreeningRules = "screening-rules:manage"
+    PermissionManageUserGoals      = "user-goals:manage"
+    PermissionManageAssignedGoals  = "assigned-goals:manage"
+    PermissionManageRoles          = "roles:manage"
+    PermissionMaintenance          = "maintenance"
+)
+
+// UserRoles represents the roles assigned to a user.
+type UserRoles struct {
+    UserID string   `json:"userID"`
+    Roles  []string `json:"roles"`
+}
+
+// LearnerTherapists represents the therapists assigned to a learner.
+type LearnerTherapists struct {
+    UserID       string   `json:"userID"`
+    TherapistIDs []string `json:"therapistIDs"`
+}
+
+// ChildProfile is a child managed by a guardian account. Its ID is used in place of a user ID for
+// the child's screening, assessment, therapy and progress data, so each child's data is stored under
+// users/{profileID} as if the child were a user.
+type ChildProfile struct {
+    ID         string    `json:"id"`
+    GuardianID string    `json:"guardianID"`
+    Name       string    `json:"name"`
+    AgeGroup   string    `json:"ageGroup,omitempty"`
+    CreatedAt  time.Time `json:"createdAt"`
+    UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// UserSettings represents preferences a user can change.
+type UserSettings struct {
+    Timezone string `json:"timezone,omitempty"`
+}
+
+// AuthRequest represents a request for authentication with unique fields. Device names the
+// signed-in device in the user's session list.
 type AuthRequest struct {
     Token      string `json:"token"`
     AuthType   string `json:"authType"`
+    Device     string `json:"device,omitempty"`
 }
 
 // RegisterRequest represents a request for user registration with unique fields.
@@ -31,7 +86,47 @@ type RefreshRequest struct {
 
 // AuthResponse represents a response for authentication with unique fields.
 type AuthResponse struct {
-    Token        string `json:"token,omitempty"`
-    RefreshToken string `json:"refreshToken,omitempty"`
-    Error        string `json:"error,omitempty"`
-}
\ No newline at end of file
+    Token        string   `json:"token,omitempty"`
+    RefreshToken string   `json:"refreshToken,omitempty"`
+    SessionID    string   `json:"sessionID,omitempty"`
+    Roles        []string `json:"roles,omitempty"`
+    Error        string   `json:"error,omitempty"`
+}
+
+// Reasons an auth session was revoked.
+const (
+    AuthSessionRevokedByUser = "revoked"
+    AuthSessionRevokedReuse  = "reuse"
+    AuthSessionRevokedLogout = "logout"
+)
+
+// AuthSession is one signed-in device. Its refresh tokens form a single family: every refresh
+// replaces TokenID, and presenting a refresh token whose ID is no longer current revokes the
+// session. Current marks the session of the request that listed it.
+type AuthSession struct {
+    ID            string     `json:"id"`
+    UserID        string     `json:"userID"`
+    Device        string     `json:"device"`
+    TokenID       string     `json:"-"`
+    CreatedAt     time.Time  `json:"createdAt"`
+    LastUsedAt    time.Time  `json:"lastUsedAt"`
+    ExpiresAt     time.Time  `json:"expiresAt"`
+    RevokedAt     *time.Time `json:"revokedAt,omitempty"`
+    RevokedReason string     `json:"revokedReason,omitempty"`
+    Current       bool       `json:"current" firestore:"-"`
+}
+// Kinds of identifiers on the token revocation list.
+const (
+    TokenRevocationAccessToken = "accessToken"
+    TokenRevocationSession     = "session"
+)
+
+// TokenRevocation lists an access token ID, or an auth session ID whose access tokens are all
+// rejected. Entries are only needed until ExpiresAt, when every token they cover has expired.
+type TokenRevocation struct {
+    ID        string    `json:"id"`
+    UserID    string    `json:"userID"`
+    Kind      string    `json:"kind"`
+    RevokedAt time.Time `json:"revokedAt"`
+    ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+    "fmt"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// DecodeError describes a stored question document that does not match the expected shape.
+type DecodeError struct {
+    DocumentID string
+    Field      string
+    Reason     string
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+    return fmt.Sprintf("malformed document %s: field %q %s", e.DocumentID, e.Field, e.Reason)
+}
+
+// fieldReader reads typed values from a document, keeping the first error it encounters.
+type fieldReader struct {
+    id   string
+    data map[string]interface{}
+    err  error
+}
+
+// fail records a decode error unless one was already recorded.
+func (r *fieldReader) fail(field, reason string) {
+    if r.err == nil {
+        r.err = &DecodeError{DocumentID: r.id, Field: field, Reason: reason}
+    }
+}
+
+// requiredString reads a string field that must be present and non-empty.
+func (r *fieldReader) requiredString(field string) string {
+    value := r.string(field)
+    if value == "" && r.data[field] == nil {
+        r.fail(field, "is missing")
+    } else if value == "" {
+        r.fail(field, "is empty")
+    }
+    return value
+}
+
+// string reads an optional string field.
+func (r *fieldReader) string(field string) string {
+    raw, ok := r.data[field]
+    if !ok || raw == nil {
+        return ""
+    }
+    value, ok := raw.(string)
+    if !ok {
+        r.fail(field, fmt.Sprintf("must be a string, got %T", raw))
+    }
+    return value
+}
+
+// int reads an optional integer field.
+func (r *fieldReader) int(field string) int {
+    raw, ok := r.data[field]
+    if !ok || raw == nil {
+        return 0
+    }
+    switch value := raw.(type) {
+    case int64:
+        return int(value)
+    case float64:
+        if value == float64(int(value)) {
+            return int(value)
+        }
+    }
+    r.fail(field, fmt.Sprintf("must be an integer, got %v", raw))
+    return 0
+}
+
+// bool reads an optional boolean field.
+func (r *fieldReader) bool(field string) bool {
+    raw, ok := r.data[field]
+    if !ok || raw == nil {
+        return false
+    }
+    value, ok := raw.(bool)
+    if !ok {
+        r.fail(field, fmt.Sprintf("must be a boolean, got %T", raw))
+    }
+    return value
+}
+
+// float reads an optional numeric field.
+func (r *fieldReader) float(field string) float64 {
+    raw, ok := r.data[field]
+    if !ok || raw == nil {
+        return 0
+    }
+    switch value := raw.(type) {
+    case int64:
+        return float64(value)
+    case float64:
+        return value
+    }
+    r.fail(field, fmt.Sprintf("must be a number, got %T", raw))
+    return 0
+}
+
+// strings reads an optional array-of-strings field.
+func (r *fieldReader) strings(field string) []string {
+    raw, ok := r.data[field]
+    if !ok || raw == nil {
+        return nil
+    }
+    items, ok := raw.([]interface{})
+    if !ok {
+        r.fail(field, fmt.Sprintf("must be an array, got %T", raw))
+        return nil
+    }
+    values := make([]string, len(items))
+    for i, item := range items {
+        value, ok := item.(string)
+        if !ok {
+            r.fail(field, fmt.Sprintf("item %d must be a string, got %T", i, item))
+            return nil
+        }
+        values[i] = value
+    }
+    return values
+}
+
+// stringMap reads an optional map-of-strings field.
+func (r *fieldReader) stringMap(field string) map[string]string {
+    raw, ok := r.data[field]
+    if !ok || raw == nil {
+        return nil
+    }
+    entries, ok := raw.(map[string]interface{})
+    if !ok {
+        r.fail(field, fmt.Sprintf("must be a map, got %T", raw))
+        return nil
+    }
+    values := make(map[string]string, len(entries))
+    for key, entry := range entries {
+        value, ok := entry.(string)
+        if !ok {
+            r.fail(field, fmt.Sprintf("value for %q must be a string, got %T", key, entry))
+            return nil
+        }
+        values[key] = value
+    }
+    return values
+}
+
+// matchOptions reads an optional string-matching configuration.
+func (r *fieldReader) matchOptions(field string) *models.MatchOptions {
+    raw, ok := r.data[field]
+    if !ok || raw == nil {
+        return nil
+    }
+    entries, ok := raw.(map[string]interface{})
+    if !ok {
+        r.fail(field, fmt.Sprintf("must be a map, got %T", raw))
+        return nil
+    }
+    nested := &fieldReader{id: r.id, data: entries}
+    opts := &models.MatchOptions{
+        Normalizers:     nested.strings("normalizers"),
+        MaxEditDistance: nested.int("maxEditDistance"),
+        Phonetic:        nested.bool("phonetic"),
+    }
+    if nested.err != nil {
+        if r.err == nil {
+            decodeErr := *nested.err.(*DecodeError)
+            decodeErr.Field = field + "." + decodeErr.Field
+            r.err = &decodeErr
+        }
+        return nil
+    }
+    return opts
+}
+
+// decodeAssessmentQuestion converts stored document fields into an AssessmentQuestion.
+func decodeAssessmentQuestion(id string, data map[string]interface{}) (models.AssessmentQuestion, error) {
+    r := &fieldReader{id: id, data: data}
+    q := models.AssessmentQuestion{
+        ID:              id,
+        Type:            r.requiredString("type"),
+        Category:        r.requiredString("category"),
+        Content:         r.string("content"),
+        ImageURL:        r.string("imageURL"),
+        SoundURL:        r.string("soundURL"),
+        AnswerFormat:    r.string("answerFormat"),
+        Options:         r.strings("options"),
+        LeftItems:       r.strings("leftItems"),
+        RightItems:      r.strings("rightItems"),
+        CorrectAnswer:   r.string("correctAnswer"),
+        CorrectSequence: r.strings("correctSequence"),
+        CorrectPairs:    r.stringMap("correctPairs"),
+        CorrectOptions:  r.strings("correctOptions"),
+        Matching:        r.matchOptions("matching"),
+    }
+    if r.err != nil {
+        return models.AssessmentQuestion{}, r.err
+    }
+    return q, nil
+}
+
+// encodeAssessmentQuestion converts an AssessmentQuestion into document fields.
+func encodeAssessmentQuestion(q models.AssessmentQuestion) map[string]interface{} {
+    return map[string]interface{}{
+        "type":            q.Type,
+        "category":        q.Category,
+        "content":         q.Content,
+        "imageURL":        q.ImageURL,
+        "soundURL":        q.SoundURL,
+        "answerFormat":    q.AnswerFormat,
+        "options":         q.Options,
+        "leftItems":       q.LeftItems,
+        "rightItems":      q.RightItems,
+        "correctAnswer":   q.CorrectAnswer,
+        "correctSequence": q.CorrectSequence,
+        "correctPairs":    q.CorrectPairs,
+        "correctOptions":  q.CorrectOptions,
+        "matching":        encodeMatchOptions(q.Matching),
+        "timestamp":       firestore.ServerTimestamp,
+    }
+}
+
+// decodeTherapyQuestion converts stored document fields into a TherapyQuestion.
+func decodeTherapyQuestion(id string, data map[string]interface{}) (models.TherapyQuestion, error) {
+    r := &fieldReader{id: id, data: data}
+    q := models.TherapyQuestion{
+        ID:              id,
+        Type:            r.requiredString("type"),
+        Category:        r.requiredString("category"),
+        Content:         r.string("content"),
+        Description:     r.string("description"),
+        ImageURL:        r.string("imageURL"),
+        SoundURL:        r.string("soundURL"),
+        AnswerFormat:    r.string("answerFormat"),
+        Options:         r.strings("options"),
+        LeftItems:       r.strings("leftItems"),
+        RightItems:      r.strings("rightItems"),
+        CorrectAnswer:   r.string("correctAnswer"),
+        CorrectSequence: r.strings("correctSequence"),
+        CorrectPairs:    r.stringMap("correctPairs"),
+        CorrectOptions:  r.strings("correctOptions"),
+        Matching:        r.matchOptions("matching"),
+        Difficulty:      r.int("difficulty"),
+    }
+    if r.err != nil {
+        return models.TherapyQuestion{}, r.err
+    }
+    return q, nil
+}
+
+// encodeTherapyQuestion converts a TherapyQuestion into document fields.
+func encodeTherapyQuestion(q models.TherapyQuestion) map[string]interface{} {
+    return map[string]interface{}{
+        "type":            q.Type,
+        "category":        q.Category,
+        "content":         q.Content,
+        "description":     q.Description,
+        "imageURL":        q.ImageURL,
+        "soundURL":        q.SoundURL,
+        "answerFormat":    q.AnswerFormat,
+        "options":         q.Options,
+        "leftItems":       q.LeftItems,
+        "rightItems":      q.RightItems,
+        "correctAnswer":   q.CorrectAnswer,
+        "correctSequence": q.CorrectSequence,
+        "correctPairs":    q.CorrectPairs,
+        "correctOptions":  q.CorrectOptions,
+        "matching":        encodeMatchOptions(q.Matching),
+        "difficulty":      q.Difficulty,
+        "timestamp":       firestore.ServerTimestamp,
+    }
+}
+
+// decodeScreeningQuestion converts stored document fields into a ScreeningQuestion.
+func decodeScreeningQuestion(id string, data map[string]interface{}) (models.ScreeningQuestion, error) {
+    r := &fieldReader{id: id, data: data}
+    q := models.ScreeningQuestion{
+        ID:       id,
+        AgeGroup: r.requiredString("ageGroup"),
+        Question: r.string("question"),
+        Weight:   r.float("weight"),
+        Domain:   r.string("domain"),
+    }
+    if r.err != nil {
+        return models.ScreeningQuestion{}, r.err
+    }
+    return q, nil
+}
+
+// encodeScreeningQuestion converts a ScreeningQuestion into document fields.
+func encodeScreeningQuestion(q models.ScreeningQuestion) map[string]interface{} {
+    return map[string]interface{}{
+        "ageGroup":  q.AgeGroup,
+        "question":  q.Question,
+        "weight":    q.Weight,
+        "domain":    q.Domain,
+        "timestamp": firestore.ServerTimestamp,
+    }
+}
+
+// encodeMatchOptions converts a string-matching configuration into document fields.
+func encodeMatchOptions(opts *models.MatchOptions) map[string]interface{} {
+    if opts == nil {
+        return nil
+    }
+    return map[string]interface{}{
+        "normalizers":     opts.Normalizers,
+        "maxEditDistance": opts.MaxEditDistance,
+        "phonetic":        opts.Phonetic,
+    }
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+    "errors"
+    "reflect"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestDecodeTherapyQuestion(t *testing.T) {
+    data := map[string]interface{}{
+        "type":            "auditory",
+        "category":        "phoneme",
+        "description":     "Phonemes",
+        "answerFormat":    models.AnswerFormatPairs,
+        "leftItems":       []interface{}{"b", "d"},
+        "rightItems":      []interface{}{"bat", "dog"},
+        "correctPairs":    map[string]interface{}{"b": "bat", "d": "dog"},
+        "correctSequence": nil,
+        "matching":        map[string]interface{}{"normalizers": []interface{}{"case"}, "maxEditDistance": int64(1), "phonetic": true},
+        "difficulty":      int64(3),
+        "timestamp":       nil,
+    }
+    got, err := decodeTherapyQuestion("q1", data)
+    if err != nil {
+        t.Fatalf("decodeTherapyQuestion() error = %v", err)
+    }
+    want := models.TherapyQuestion{
+        ID:           "q1",
+        Type:         "auditory",
+        Category:     "phoneme",
+        Description:  "Phonemes",
+        AnswerFormat: models.AnswerFormatPairs,
+        LeftItems:    []string{"b", "d"},
+        RightItems:   []string{"bat", "dog"},
+        CorrectPairs: map[string]string{"b": "bat", "d": "dog"},
+        Matching:     &models.MatchOptions{Normalizers: []string{"case"}, MaxEditDistance: 1, Phonetic: true},
+        Difficulty:   3,
+    }
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("decodeTherapyQuestion() = %+v, want %+v", got, want)
+    }
+}
+
+func TestDecodeMalformedQuestion(t *testing.T) {
+    valid := func(extra map[string]interface{}) map[string]interface{} {
+        data := map[string]interface{}{"type": "visual", "category": "letters"}
+        for field, value := range extra {
+            data[field] = value
+        }
+        return data
+    }
+    tests := []struct {
+        name      string
+        data      map[string]interface{}
+        wantField string
+    }{
+        {"missing type", map[string]interface{}{"category": "letters"}, "type"},
+        {"empty category", valid(map[string]interface{}{"category": ""}), "category"},
+        {"number for a string", valid(map[string]interface{}{"correctAnswer": int64(4)}), "correctAnswer"},
+        {"string for an array", valid(map[string]interface{}{"options": "a,b"}), "options"},
+        {"number in an array", valid(map[string]interface{}{"options": []interface{}{"a", int64(2)}}), "options"},
+        {"number in a map", valid(map[string]interface{}{"correctPairs": map[string]interface{}{"a": int64(1)}}), "correctPairs"},
+        {"fractional integer", valid(map[string]interface{}{"difficulty": 1.5}), "difficulty"},
+        {"list for matching", valid(map[string]interface{}{"matching": []interface{}{"case"}}), "matching"},
+        {"string for a nested integer", valid(map[string]interface{}{"matching": map[string]interface{}{"maxEditDistance": "1"}}), "matching.maxEditDistance"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := decodeTherapyQuestion("q1", tt.data)
+            var decodeErr *DecodeError
+            if !errors.As(err, &decodeErr) {
+                t.Fatalf("decodeTherapyQuestion() error = %v, want a DecodeError", err)
+            }
+            if decodeErr.DocumentID != "q1" || decodeErr.Field != tt.wantField {
+                t.Errorf("DecodeError for document %s field %q, want q1 field %q", decodeErr.DocumentID, decodeErr.Field, tt.wantField)
+            }
+        })
+    }
+}
+
+func TestDecodeAssessmentQuestion(t *testing.T) {
+    data := map[string]interface{}{
+        "type":           "visual",
+        "category":       "letters",
+        "options":        []interface{}{"b", "d", "p"},
+        "correctOptions": []interface{}{"b", "d"},
+    }
+    got, err := decodeAssessmentQuestion("q2", data)
+    if err != nil {
+        t.Fatalf("decodeAssessmentQuestion() error = %v", err)
+    }
+    if got.ID != "q2" || !reflect.DeepEqual(got.CorrectOptions, []string{"b", "d"}) || got.Matching != nil {
+        t.Errorf("decodeAssessmentQuestion() = %+v, want q2 with correct options [b d] and no matching options", got)
+    }
+    if _, err := decodeAssessmentQuestion("q2", map[string]interface{}{"type": "visual"}); err == nil {
+        t.Error("decodeAssessmentQuestion() without a category succeeded, want an error")
+    }
+}
+
+func TestDecodeScreeningQuestion(t *testing.T) {
+    tests := []struct {
+        name       string
+        weight     interface{}
+        wantWeight float64
+    }{
+        {"integer weight", int64(2), 2},
+        {"fractional weight", 1.5, 1.5},
+        {"no weight", nil, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := decodeScreeningQuestion("s1", map[string]interface{}{"ageGroup": "kid", "question": "Reverses letters", "weight": tt.weight})
+            if err != nil {
+                t.Fatalf("decodeScreeningQuestion() error = %v", err)
+            }
+            if got.Weight != tt.wantWeight || got.AgeGroup != "kid" {
+                t.Errorf("decodeScreeningQuestion() = %+v, want a kid question with weight %v", got, tt.wantWeight)
+            }
+        })
+    }
+    if _, err := decodeScreeningQuestion("s1", map[string]interface{}{"ageGroup": "kid", "weight": "heavy"}); err == nil {
+        t.Error("decodeScreeningQuestion() with a string weight succeeded, want an error")
+    }
+}
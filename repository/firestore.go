@@ -0,0 +1,65 @@
+package repository
+
+import (
+    "cloud.google.com/go/firestore"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+// questionTypes lists the assessment and therapy types stored in Firestore.
+var questionTypes = []string{"visual", "auditory", "kinesthetic", "tactile"}
+
+// FirestoreRepository implements Repository on top of Cloud Firestore.
+type FirestoreRepository struct {
+    client *firestore.Client
+}
+
+// Ensure FirestoreRepository satisfies Repository.
+var _ Repository = (*FirestoreRepository)(nil)
+
+// NewFirestoreRepository creates a Repository backed by the given Firestore client.
+func NewFirestoreRepository(client *firestore.Client) *FirestoreRepository {
+    return &FirestoreRepository{client: client}
+}
+
+// notFound maps Firestore NotFound errors to ErrNotFound.
+func notFound(err error) error {
+    if status.Code(err) == codes.NotFound {
+        return ErrNotFound
+    }
+    return err
+}
+
+// alreadyExists maps Firestore AlreadyExists errors to ErrAlreadyExists.
+func alreadyExists(err error) error {
+    if status.Code(err) == codes.AlreadyExists {
+        return ErrAlreadyExists
+    }
+    return err
+}
+
+// intField reads a numeric field that Firestore may return as int64.
+func intField(data map[string]interface{}, key string) int {
+    switch v := data[key].(type) {
+    case int64:
+        return int(v)
+    case int:
+        return v
+    case float64:
+        return int(v)
+    }
+    return 0
+}
+
+// floatField reads a numeric document field as a float64, returning 0 when it is missing.
+func floatField(data map[string]interface{}, key string) float64 {
+    switch v := data[key].(type) {
+    case float64:
+        return v
+    case int64:
+        return float64(v)
+    case int:
+        return float64(v)
+    }
+    return 0
+}
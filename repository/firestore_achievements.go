@@ -0,0 +1,51 @@
+package repository
+
+import (
+    "context"
+    "log"
+    "sort"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// achievements returns the collection holding a user's unlocked badges, keyed by badge ID.
+func (r *FirestoreRepository) achievements(userID string) *firestore.CollectionRef {
+    return r.client.Collection("users").Doc(userID).Collection("achievements")
+}
+
+// CreateAchievement stores a newly unlocked badge. Unlocked badges are never updated once written.
+func (r *FirestoreRepository) CreateAchievement(ctx context.Context, userID string, achievement models.Achievement) error {
+    _, err := r.achievements(userID).Doc(achievement.BadgeID).Create(ctx, map[string]interface{}{
+        "badgeID":     achievement.BadgeID,
+        "name":        achievement.Name,
+        "description": achievement.Description,
+        "unlockedAt":  achievement.UnlockedAt,
+    })
+    if err != nil {
+        return alreadyExists(err)
+    }
+    return nil
+}
+
+// ListAchievements retrieves the user's unlocked badges, oldest first.
+func (r *FirestoreRepository) ListAchievements(ctx context.Context, userID string) ([]models.Achievement, error) {
+    docs, err := r.achievements(userID).Documents(ctx).GetAll()
+    if err != nil {
+        return nil, err
+    }
+
+    achievements := make([]models.Achievement, 0, len(docs))
+    for _, doc := range docs {
+        var achievement models.Achievement
+        if err := doc.DataTo(&achievement); err != nil {
+            log.Printf("Failed to parse achievement %s: %v", doc.Ref.ID, err)
+            continue
+        }
+        achievements = append(achievements, achievement)
+    }
+    sort.Slice(achievements, func(i, j int) bool {
+        return achievements[i].UnlockedAt.Before(achievements[j].UnlockedAt)
+    })
+    return achievements, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "sort"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// assessmentAttempts returns the collection holding a user's assessment attempts.
+func (r *FirestoreRepository) assessmentAttempts(userID string) *firestore.CollectionRef {
+    return r.client.Collection("users").Doc(userID).Collection("assessmentAttempts")
+}
+
+// CreateAssessmentAttempt stores a new attempt. Attempts are never updated once written.
+func (r *FirestoreRepository) CreateAssessmentAttempt(ctx context.Context, attempt models.AssessmentAttempt) (string, error) {
+    if attempt.ID == "" {
+        docRef, _, err := r.assessmentAttempts(attempt.UserID).Add(ctx, assessmentAttemptData(attempt))
+        if err != nil {
+            return "", err
+        }
+        return docRef.ID, nil
+    }
+
+    _, err := r.assessmentAttempts(attempt.UserID).Doc(attempt.ID).Create(ctx, assessmentAttemptData(attempt))
+    if err != nil {
+        return "", alreadyExists(err)
+    }
+    return attempt.ID, nil
+}
+
+// GetAssessmentAttempt retrieves one of the user's assessment attempts.
+func (r *FirestoreRepository) GetAssessmentAttempt(ctx context.Context, userID, attemptID string) (models.AssessmentAttempt, error) {
+    doc, err := r.assessmentAttempts(userID).Doc(attemptID).Get(ctx)
+    if err != nil {
+        return models.AssessmentAttempt{}, notFound(err)
+    }
+    return decodeAssessmentAttempt(doc)
+}
+
+// ListAssessmentAttempts retrieves the user's attempts for a type, or for every type, oldest first.
+func (r *FirestoreRepository) ListAssessmentAttempts(ctx context.Context, userID, questionType string) ([]models.AssessmentAttempt, error) {
+    query := r.assessmentAttempts(userID).Query
+    if questionType != "" {
+        query = query.Where("type", "==", questionType)
+    }
+    docs, err := query.Documents(ctx).GetAll()
+    if err != nil {
+        return nil, err
+    }
+
+    attempts := make([]models.AssessmentAttempt, 0, len(docs))
+    for _, doc := range docs {
+        attempt, err := decodeAssessmentAttempt(doc)
+        if err != nil {
+            log.Printf("Failed to parse assessment attempt %s: %v", doc.Ref.ID, err)
+            continue
+        }
+        attempts = append(attempts, attempt)
+    }
+    sort.Slice(attempts, func(i, j int) bool {
+        return attempts[i].CompletedAt.Before(attempts[j].CompletedAt)
+    })
+    return attempts, nil
+}
+
+// decodeAssessmentAttempt converts an attempt document into an AssessmentAttempt. Attempts stored
+// before the partial flag existed are partial when they have no session.
+func decodeAssessmentAttempt(doc *firestore.DocumentSnapshot) (models.AssessmentAttempt, error) {
+    var attempt models.AssessmentAttempt
+    if err := doc.DataTo(&attempt); err != nil {
+        return models.AssessmentAttempt{}, fmt.Errorf("failed to parse assessment attempt: %w", err)
+    }
+    attempt.ID = doc.Ref.ID
+    if attempt.SessionID == "" {
+        attempt.Partial = true
+    }
+    return attempt, nil
+}
+
+// assessmentAttemptData converts an AssessmentAttempt into its Firestore document fields.
+func assessmentAttemptData(attempt models.AssessmentAttempt) map[string]interface{} {
+    categories := make([]map[string]interface{}, len(attempt.Categories))
+    for i, category := range attempt.Categories {
+        categories[i] = map[string]interface{}{
+            "category":       category.Category,
+            "correctAnswers": category.CorrectAnswers,
+            "score":          category.Score,
+            "totalQuestions": category.TotalQuestions,
+        }
+    }
+
+    return map[string]interface{}{
+        "userID":         attempt.UserID,
+        "type":           attempt.Type,
+        "sessionID":      attempt.SessionID,
+        "partial":        attempt.Partial,
+        "answers":        assessmentAnswersData(attempt.Answers),
+        "categories":     categories,
+        "correctAnswers": attempt.CorrectAnswers,
+        "score":          attempt.Score,
+        "totalQuestions": attempt.TotalQuestions,
+        "completedAt":    attempt.CompletedAt,
+    }
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// authSessions returns the collection holding a user's auth sessions.
+func (r *FirestoreRepository) authSessions(userID string) *firestore.CollectionRef {
+    return r.client.Collection("users").Doc(userID).Collection("authSessions")
+}
+
+// CreateAuthSession stores a new auth session and returns its generated ID.
+func (r *FirestoreRepository) CreateAuthSession(ctx context.Context, session models.AuthSession) (string, error) {
+    docRef, _, err := r.authSessions(session.UserID).Add(ctx, authSessionData(session))
+    if err != nil {
+        return "", err
+    }
+    return docRef.ID, nil
+}
+
+// GetAuthSession retrieves one of the user's auth sessions.
+func (r *FirestoreRepository) GetAuthSession(ctx context.Context, userID, sessionID string) (models.AuthSession, error) {
+    doc, err := r.authSessions(userID).Doc(sessionID).Get(ctx)
+    if err != nil {
+        return models.AuthSession{}, notFound(err)
+    }
+    return decodeAuthSession(doc)
+}
+
+// UpdateAuthSession applies update to an existing session inside a transaction.
+func (r *FirestoreRepository) UpdateAuthSession(ctx context.Context, userID, sessionID string, update func(session *models.AuthSession) error) (models.AuthSession, error) {
+    docRef := r.authSessions(userID).Doc(sessionID)
+
+    var session models.AuthSession
+    err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+        doc, err := tx.Get(docRef)
+        if err != nil {
+            return notFound(err)
+        }
+        session, err = decodeAuthSession(doc)
+        if err != nil {
+            return err
+        }
+
+        if err := update(&session); err != nil {
+            return err
+        }
+        return tx.Set(docRef, authSessionData(session))
+    })
+    if err != nil {
+        return models.AuthSession{}, err
+    }
+    return session, nil
+}
+
+// ListAuthSessions retrieves every session of the user, including revoked ones, oldest first.
+func (r *FirestoreRepository) ListAuthSessions(ctx context.Context, userID string) ([]models.AuthSession, error) {
+    docs, err := r.authSessions(userID).OrderBy("createdAt", firestore.Asc).Documents(ctx).GetAll()
+    if err != nil {
+        return nil, err
+    }
+
+    sessions := make([]models.AuthSession, 0, len(docs))
+    for _, doc := range docs {
+        session, err := decodeAuthSession(doc)
+        if err != nil {
+            log.Printf("Failed to parse auth session %s: %v", doc.Ref.ID, err)
+            continue
+        }
+        sessions = append(sessions, session)
+    }
+    return sessions, nil
+}
+
+// decodeAuthSession converts a session document into an AuthSession.
+func decodeAuthSession(doc *firestore.DocumentSnapshot) (models.AuthSession, error) {
+    var session models.AuthSession
+    if err := doc.DataTo(&session); err != nil {
+        return models.AuthSession{}, fmt.Errorf("failed to parse auth session: %w", err)
+    }
+    session.ID = doc.Ref.ID
+    return session, nil
+}
+
+// authSessionData converts an AuthSession into its Firestore document fields.
+func authSessionData(session models.AuthSession) map[string]interface{} {
+    data := map[string]interface{}{
+        "userID":     session.UserID,
+        "device":     session.Device,
+        "tokenID":    session.TokenID,
+        "createdAt":  session.CreatedAt,
+        "lastUsedAt": session.LastUsedAt,
+        "expiresAt":  session.ExpiresAt,
+    }
+    if session.RevokedAt != nil {
+        data["revokedAt"] = *session.RevokedAt
+        data["revokedReason"] = session.RevokedReason
+    }
+    return data
+}
+
+// RevokeTokens adds entries to the top-level revokedTokens collection. A Firestore TTL policy on
+// expiresAt can remove entries once every token they cover has expired.
+func (r *FirestoreRepository) RevokeTokens(ctx context.Context, revocations []models.TokenRevocation) error {
+    if len(revocations) == 0 {
+        return nil
+    }
+    bw := r.client.BulkWriter(ctx)
+    var jobs []*firestore.BulkWriterJob
+    for _, revocation := range revocations {
+        job, err := bw.Set(r.client.Collection("revokedTokens").Doc(revocation.ID), map[string]interface{}{
+            "userID":    revocation.UserID,
+            "kind":      revocation.Kind,
+            "revokedAt": revocation.RevokedAt,
+            "expiresAt": revocation.ExpiresAt,
+        })
+        if err != nil {
+            bw.End()
+            return err
+        }
+        jobs = append(jobs, job)
+    }
+    bw.End()
+
+    for _, job := range jobs {
+        if _, err := job.Results(); err != nil {
+            return fmt.Errorf("failed to write token revocation: %w", err)
+        }
+    }
+    return nil
+}
+
+// GetTokenRevocation retrieves the revocation list entry of an access token or session ID.
+func (r *FirestoreRepository) GetTokenRevocation(ctx context.Context, id string) (models.TokenRevocation, error) {
+    doc, err := r.client.Collection("revokedTokens").Doc(id).Get(ctx)
+    if err != nil {
+        return models.TokenRevocation{}, notFound(err)
+    }
+
+    var revocation models.TokenRevocation
+    if err := doc.DataTo(&revocation); err != nil {
+        return models.TokenRevocation{}, fmt.Errorf("failed to parse token revocation: %w", err)
+    }
+    revocation.ID = doc.Ref.ID
+    return revocation, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// childProfiles returns the collection holding a guardian's child profiles.
+func (r *FirestoreRepository) childProfiles(guardianID string) *firestore.CollectionRef {
+    return r.client.Collection("users").Doc(guardianID).Collection("profiles")
+}
+
+// CreateChildProfile stores a new child profile and returns its generated ID.
+func (r *FirestoreRepository) CreateChildProfile(ctx context.Context, profile models.ChildProfile) (string, error) {
+    docRef, _, err := r.childProfiles(profile.GuardianID).Add(ctx, childProfileData(profile))
+    if err != nil {
+        return "", err
+    }
+    return docRef.ID, nil
+}
+
+// GetChildProfile retrieves one of the guardian's child profiles.
+func (r *FirestoreRepository) GetChildProfile(ctx context.Context, guardianID, profileID string) (models.ChildProfile, error) {
+    doc, err := r.childProfiles(guardianID).Doc(profileID).Get(ctx)
+    if err != nil {
+        return models.ChildProfile{}, notFound(err)
+    }
+    return decodeChildProfile(doc)
+}
+
+// UpdateChildProfile applies update to an existing child profile inside a transaction.
+func (r *FirestoreRepository) UpdateChildProfile(ctx context.Context, guardianID, profileID string, update func(profile *models.ChildProfile) error) (models.ChildProfile, error) {
+    docRef := r.childProfiles(guardianID).Doc(profileID)
+
+    var profile models.ChildProfile
+    err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+        doc, err := tx.Get(docRef)
+        if err != nil {
+            return notFound(err)
+        }
+        profile, err = decodeChildProfile(doc)
+        if err != nil {
+            return err
+        }
+
+        if err := update(&profile); err != nil {
+            return err
+        }
+        return tx.Set(docRef, childProfileData(profile))
+    })
+    if err != nil {
+        return models.ChildProfile{}, err
+    }
+    return profile, nil
+}
+
+// ListChildProfiles retrieves every child profile of the guardian, oldest first.
+func (r *FirestoreRepository) ListChildProfiles(ctx context.Context, guardianID string) ([]models.ChildProfile, error) {
+    docs, err := r.childProfiles(guardianID).OrderBy("createdAt", firestore.Asc).Documents(ctx).GetAll()
+    if err != nil {
+        return nil, err
+    }
+
+    profiles := make([]models.ChildProfile, 0, len(docs))
+    for _, doc := range docs {
+        profile, err := decodeChildProfile(doc)
+        if err != nil {
+            log.Printf("Failed to parse child profile %s: %v", doc.Ref.ID, err)
+            continue
+        }
+        profiles = append(profiles, profile)
+    }
+    return profiles, nil
+}
+
+// decodeChildProfile converts a profile document into a ChildProfile.
+func decodeChildProfile(doc *firestore.DocumentSnapshot) (models.ChildProfile, error) {
+    var profile models.ChildProfile
+    if err := doc.DataTo(&profile); err != nil {
+        return models.ChildProfile{}, fmt.Errorf("failed to parse child profile: %w", err)
+    }
+    profile.ID = doc.Ref.ID
+    return profile, nil
+}
+
+// childProfileData converts a ChildProfile into its Firestore document fields.
+func childProfileData(profile models.ChildProfile) map[string]interface{} {
+    return map[string]interface{}{
+        "guardianID": profile.GuardianID,
+        "name":       profile.Name,
+        "ageGroup":   profile.AgeGroup,
+        "createdAt":  profile.CreatedAt,
+        "updatedAt":  profile.UpdatedAt,
+    }
+}
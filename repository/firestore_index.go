@@ -0,0 +1,137 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "sort"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// questionCollections maps each indexed question kind to its Firestore collection.
+var questionCollections = map[string]string{
+    models.QuestionKindAssessment: "assessmentQuestions",
+    models.QuestionKindTherapy:    "therapyQuestions",
+}
+
+// GetQuestionLocation looks up where a question is stored.
+func (r *FirestoreRepository) GetQuestionLocation(ctx context.Context, questionID string) (models.QuestionLocation, error) {
+    doc, err := r.client.Collection("questionIndex").Doc(questionID).Get(ctx)
+    if err != nil {
+        return models.QuestionLocation{}, notFound(err)
+    }
+
+    data := doc.Data()
+    location := models.QuestionLocation{QuestionID: doc.Ref.ID}
+    location.Kind, _ = data["kind"].(string)
+    location.Type, _ = data["type"].(string)
+    location.Category, _ = data["category"].(string)
+    return location, nil
+}
+
+// SetQuestionLocation records where a question is stored.
+func (r *FirestoreRepository) SetQuestionLocation(ctx context.Context, location models.QuestionLocation) error {
+    _, err := r.client.Collection("questionIndex").Doc(location.QuestionID).Set(ctx, questionLocationData(location))
+    return err
+}
+
+// DeleteQuestionLocation removes a question from the index.
+func (r *FirestoreRepository) DeleteQuestionLocation(ctx context.Context, questionID string) error {
+    _, err := r.client.Collection("questionIndex").Doc(questionID).Delete(ctx)
+    return err
+}
+
+// RebuildQuestionIndex scans every assessment and therapy collection and rewrites the index,
+// removing entries for questions that no longer exist. Documents that cannot be decoded as
+// questions are left out of the index and reported as skipped.
+func (r *FirestoreRepository) RebuildQuestionIndex(ctx context.Context) (models.QuestionIndexRebuild, error) {
+    locations := make(map[string]models.QuestionLocation)
+    skipped := make([]string, 0)
+    for kind, collection := range questionCollections {
+        for _, t := range questionTypes {
+            categories, err := r.client.Collection(collection).Doc(t).Collections(ctx).GetAll()
+            if err != nil {
+                return models.QuestionIndexRebuild{}, fmt.Errorf("failed to retrieve categories for %s type %s: %w", kind, t, err)
+            }
+            for _, category := range categories {
+                docs, err := category.Documents(ctx).GetAll()
+                if err != nil {
+                    return models.QuestionIndexRebuild{}, fmt.Errorf("failed to retrieve %s questions for type %s, category %s: %w", kind, t, category.ID, err)
+                }
+                for _, doc := range docs {
+                    if err := decodeQuestion(kind, doc); err != nil {
+                        log.Printf("Skipping %s question in index rebuild: %v", kind, err)
+                        skipped = append(skipped, doc.Ref.ID)
+                        continue
+                    }
+                    locations[doc.Ref.ID] = models.QuestionLocation{
+                        QuestionID: doc.Ref.ID,
+                        Kind:       kind,
+                        Type:       t,
+                        Category:   category.ID,
+                    }
+                }
+            }
+        }
+    }
+    sort.Strings(skipped)
+
+    existing, err := r.client.Collection("questionIndex").DocumentRefs(ctx).GetAll()
+    if err != nil {
+        return models.QuestionIndexRebuild{}, fmt.Errorf("failed to retrieve question index: %w", err)
+    }
+
+    bw := r.client.BulkWriter(ctx)
+    var jobs []*firestore.BulkWriterJob
+    for _, ref := range existing {
+        if _, ok := locations[ref.ID]; ok {
+            continue
+        }
+        job, err := bw.Delete(ref)
+        if err != nil {
+            bw.End()
+            return models.QuestionIndexRebuild{}, err
+        }
+        jobs = append(jobs, job)
+    }
+    for id, location := range locations {
+        job, err := bw.Set(r.client.Collection("questionIndex").Doc(id), questionLocationData(location))
+        if err != nil {
+            bw.End()
+            return models.QuestionIndexRebuild{}, err
+        }
+        jobs = append(jobs, job)
+    }
+    bw.End()
+
+    for _, job := range jobs {
+        if _, err := job.Results(); err != nil {
+            return models.QuestionIndexRebuild{}, fmt.Errorf("failed to write question index: %w", err)
+        }
+    }
+    return models.QuestionIndexRebuild{IndexedQuestions: len(locations), SkippedQuestionIDs: skipped}, nil
+}
+
+// decodeQuestion reports whether a question document of the given kind can be decoded.
+func decodeQuestion(kind string, doc *firestore.DocumentSnapshot) error {
+    var err error
+    switch kind {
+    case models.QuestionKindAssessment:
+        _, err = decodeAssessmentQuestion(doc.Ref.ID, doc.Data())
+    case models.QuestionKindTherapy:
+        _, err = decodeTherapyQuestion(doc.Ref.ID, doc.Data())
+    }
+    return err
+}
+
+// questionLocationData converts a QuestionLocation into its Firestore document fields.
+func questionLocationData(location models.QuestionLocation) map[string]interface{} {
+    return map[string]interface{}{
+        "kind":      location.Kind,
+        "type":      location.Type,
+        "category":  location.Category,
+        "timestamp": firestore.ServerTimestamp,
+    }
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "time"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// streakSummary returns the document holding a user's streak summary.
+func (r *FirestoreRepository) streakSummary(userID string) *firestore.DocumentRef {
+    return r.client.Collection("users").Doc(userID).Collection("progressSummary").Doc("streak")
+}
+
+// UpdateDailyProgress applies update to the user's progress document for date and their streak
+// summary inside a single transaction.
+func (r *FirestoreRepository) UpdateDailyProgress(ctx context.Context, userID string, date time.Time, update func(progress *models.DailyProgress, streak *models.StreakSummary) error) (models.DailyProgress, models.StreakSummary, error) {
+    docRef := r.client.Collection("users").Doc(userID).Collection("progress").Doc(date.Format("20060102"))
+    streakRef := r.streakSummary(userID)
+
+    var progress models.DailyProgress
+    var streak models.StreakSummary
+    err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+        progress = models.DailyProgress{UserID: userID, Date: date}
+        doc, err := tx.Get(docRef)
+        if err != nil && notFound(err) != ErrNotFound {
+            return fmt.Errorf("failed to fetch progress: %w", err)
+        }
+        if err == nil {
+            if err := doc.DataTo(&progress); err != nil {
+                return fmt.Errorf("failed to parse progress data: %w", err)
+            }
+        }
+
+        streak = models.StreakSummary{UserID: userID}
+        streakDoc, err := tx.Get(streakRef)
+        if err != nil && notFound(err) != ErrNotFound {
+            return fmt.Errorf("failed to fetch streak summary: %w", err)
+        }
+        if err == nil {
+            if err := streakDoc.DataTo(&streak); err != nil {
+                return fmt.Errorf("failed to parse streak summary: %w", err)
+            }
+        }
+
+        if err := update(&progress, &streak); err != nil {
+            return err
+        }
+
+        if err := tx.Set(docRef, map[string]interface{}{
+            "userID":         progress.UserID,
+            "date":           progress.Date,
+            "therapyCount":   progress.TherapyCount,
+            "correctAnswers": progress.CorrectAnswers,
+            "totalQuestions": progress.TotalQuestions,
+            "secondsSpent":   progress.SecondsSpent,
+            "types":          activityBreakdownData(progress.Types),
+            "categories":     activityBreakdownData(progress.Categories),
+            "streakAchieved": progress.StreakAchieved,
+            "goal":           dailyGoalData(progress.Goal),
+        }); err != nil {
+            return err
+        }
+        return tx.Set(streakRef, streakSummaryData(streak))
+    })
+    if err != nil {
+        return models.DailyProgress{}, models.StreakSummary{}, err
+    }
+    return progress, streak, nil
+}
+
+// GetStreakSummary retrieves the user's streak summary.
+func (r *FirestoreRepository) GetStreakSummary(ctx context.Context, userID string) (models.StreakSummary, error) {
+    streak := models.StreakSummary{UserID: userID}
+    doc, err := r.streakSummary(userID).Get(ctx)
+    if err != nil {
+        if notFound(err) == ErrNotFound {
+            return streak, nil
+        }
+        return models.StreakSummary{}, err
+    }
+    if err := doc.DataTo(&streak); err != nil {
+        return models.StreakSummary{}, fmt.Errorf("failed to parse streak summary: %w", err)
+    }
+    return streak, nil
+}
+
+// streakSummaryData converts a streak summary into its Firestore representation.
+func streakSummaryData(streak models.StreakSummary) map[string]interface{} {
+    return map[string]interface{}{
+        "userID":         streak.UserID,
+        "currentStreak":  streak.CurrentStreak,
+        "longestStreak":  streak.LongestStreak,
+        "lastStreakDate": streak.LastStreakDate,
+        "freezeTokens":   streak.FreezeTokens,
+        "freezesUsed":    streak.FreezesUsed,
+        "correctAnswers": streak.CorrectAnswers,
+    }
+}
+
+// activityBreakdownData converts activity breakdowns into their Firestore representation.
+func activityBreakdownData(breakdown []models.ActivityBreakdown) []map[string]interface{} {
+    data := make([]map[string]interface{}, len(breakdown))
+    for i, b := range breakdown {
+        data[i] = map[string]interface{}{
+            "type":           b.Type,
+            "category":       b.Category,
+            "sessions":       b.Sessions,
+            "correctAnswers": b.CorrectAnswers,
+            "totalQuestions": b.TotalQuestions,
+        }
+    }
+    return data
+}
+
+// ListDailyProgress retrieves the user's progress documents dated between startDate and endDate inclusive.
+func (r *FirestoreRepository) ListDailyProgress(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.DailyProgress, error) {
+    docs, err := r.client.Collection("users").Doc(userID).Collection("progress").
+        Where("date", ">=", startDate).
+        Where("date", "<=", endDate).
+        Documents(ctx).GetAll()
+    if err != nil {
+        return nil, err
+    }
+
+    progress := make([]models.DailyProgress, 0, len(docs))
+    for _, doc := range docs {
+        var p models.DailyProgress
+        if err := doc.DataTo(&p); err != nil {
+            log.Printf("Failed to parse progress data for doc %s: %v", doc.Ref.ID, err)
+            continue
+        }
+        progress = append(progress, p)
+    }
+    return progress, nil
+}
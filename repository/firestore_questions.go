@@ -0,0 +1,213 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// ListScreeningQuestions retrieves screening questions, optionally filtered by ageGroup.
+// Malformed documents are skipped and their IDs returned.
+func (r *FirestoreRepository) ListScreeningQuestions(ctx context.Context, ageGroup string) ([]models.ScreeningQuestion, []string, error) {
+    var docs []*firestore.DocumentSnapshot
+    var err error
+    if ageGroup == "" {
+        ageGroups, err := r.client.Collection("screeningQuestions").DocumentRefs(ctx).GetAll()
+        if err != nil {
+            return nil, nil, fmt.Errorf("failed to retrieve age groups: %w", err)
+        }
+        for _, ageGroupDoc := range ageGroups {
+            groupDocs, err := ageGroupDoc.Collection("questions").Documents(ctx).GetAll()
+            if err != nil {
+                log.Printf("Failed to retrieve questions for ageGroup %s: %v", ageGroupDoc.ID, err)
+                continue
+            }
+            docs = append(docs, groupDocs...)
+        }
+    } else {
+        docs, err = r.client.Collection("screeningQuestions").Doc(ageGroup).Collection("questions").Documents(ctx).GetAll()
+        if err != nil {
+            return nil, nil, err
+        }
+    }
+
+    questions := make([]models.ScreeningQuestion, 0, len(docs))
+    var skipped []string
+    for _, doc := range docs {
+        q, err := decodeScreeningQuestion(doc.Ref.ID, doc.Data())
+        if err != nil {
+            log.Printf("Skipping screening question: %v", err)
+            skipped = append(skipped, doc.Ref.ID)
+            continue
+        }
+        questions = append(questions, q)
+    }
+    return questions, skipped, nil
+}
+
+// GetScreeningQuestion retrieves a screening question by age group and ID.
+func (r *FirestoreRepository) GetScreeningQuestion(ctx context.Context, ageGroup, questionID string) (models.ScreeningQuestion, error) {
+    doc, err := r.client.Collection("screeningQuestions").Doc(ageGroup).Collection("questions").Doc(questionID).Get(ctx)
+    if err != nil {
+        return models.ScreeningQuestion{}, notFound(err)
+    }
+    return decodeScreeningQuestion(doc.Ref.ID, doc.Data())
+}
+
+// CreateScreeningQuestion adds a screening question and returns its generated ID.
+func (r *FirestoreRepository) CreateScreeningQuestion(ctx context.Context, question models.ScreeningQuestion) (string, error) {
+    docRef, _, err := r.client.Collection("screeningQuestions").Doc(question.AgeGroup).Collection("questions").Add(ctx, encodeScreeningQuestion(question))
+    if err != nil {
+        return "", err
+    }
+    return docRef.ID, nil
+}
+
+// UpdateScreeningQuestion merges the question into its existing document.
+func (r *FirestoreRepository) UpdateScreeningQuestion(ctx context.Context, questionID string, question models.ScreeningQuestion) error {
+    _, err := r.client.Collection("screeningQuestions").Doc(question.AgeGroup).Collection("questions").Doc(questionID).Set(ctx, encodeScreeningQuestion(question), firestore.MergeAll)
+    return err
+}
+
+// DeleteScreeningQuestion removes a screening question.
+func (r *FirestoreRepository) DeleteScreeningQuestion(ctx context.Context, ageGroup, questionID string) error {
+    _, err := r.client.Collection("screeningQuestions").Doc(ageGroup).Collection("questions").Doc(questionID).Delete(ctx)
+    return err
+}
+
+// ListAssessmentQuestions retrieves every assessment question of a type across its categories.
+// Malformed documents are skipped and their IDs returned.
+func (r *FirestoreRepository) ListAssessmentQuestions(ctx context.Context, questionType string) ([]models.AssessmentQuestion, []string, error) {
+    categories, err := r.client.Collection("assessmentQuestions").Doc(questionType).Collections(ctx).GetAll()
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to retrieve categories for type %s: %w", questionType, err)
+    }
+
+    questions := make([]models.AssessmentQuestion, 0)
+    var skipped []string
+    for _, category := range categories {
+        docs, err := category.Documents(ctx).GetAll()
+        if err != nil {
+            log.Printf("Failed to retrieve questions for type %s, category %s: %v", questionType, category.ID, err)
+            continue
+        }
+        for _, doc := range docs {
+            q, err := decodeAssessmentQuestion(doc.Ref.ID, doc.Data())
+            if err != nil {
+                log.Printf("Skipping assessment question: %v", err)
+                skipped = append(skipped, doc.Ref.ID)
+                continue
+            }
+            questions = append(questions, q)
+        }
+    }
+    return questions, skipped, nil
+}
+
+// GetAssessmentQuestion retrieves an assessment question by type, category and ID.
+func (r *FirestoreRepository) GetAssessmentQuestion(ctx context.Context, questionType, category, questionID string) (models.AssessmentQuestion, error) {
+    doc, err := r.client.Collection("assessmentQuestions").Doc(questionType).Collection(category).Doc(questionID).Get(ctx)
+    if err != nil {
+        return models.AssessmentQuestion{}, notFound(err)
+    }
+    return decodeAssessmentQuestion(doc.Ref.ID, doc.Data())
+}
+
+// CreateAssessmentQuestion adds an assessment question and returns its generated ID.
+func (r *FirestoreRepository) CreateAssessmentQuestion(ctx context.Context, question models.AssessmentQuestion) (string, error) {
+    docRef, _, err := r.client.Collection("assessmentQuestions").Doc(question.Type).Collection(question.Category).Add(ctx, encodeAssessmentQuestion(question))
+    if err != nil {
+        return "", err
+    }
+    return docRef.ID, nil
+}
+
+// UpdateAssessmentQuestion merges the question into its existing document.
+func (r *FirestoreRepository) UpdateAssessmentQuestion(ctx context.Context, questionID string, question models.AssessmentQuestion) error {
+    _, err := r.client.Collection("assessmentQuestions").Doc(question.Type).Collection(question.Category).Doc(questionID).Set(ctx, encodeAssessmentQuestion(question), firestore.MergeAll)
+    return err
+}
+
+// DeleteAssessmentQuestion removes an assessment question.
+func (r *FirestoreRepository) DeleteAssessmentQuestion(ctx context.Context, questionType, category, questionID string) error {
+    _, err := r.client.Collection("assessmentQuestions").Doc(questionType).Collection(category).Doc(questionID).Delete(ctx)
+    return err
+}
+
+// ListTherapyCategories retrieves the categories of a therapy type with the description of their first question.
+func (r *FirestoreRepository) ListTherapyCategories(ctx context.Context, questionType string) ([]models.TherapyCategory, error) {
+    categories, err := r.client.Collection("therapyQuestions").Doc(questionType).Collections(ctx).GetAll()
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve categories for type %s: %w", questionType, err)
+    }
+
+    categoryList := make([]models.TherapyCategory, 0)
+    for _, category := range categories {
+        docs, err := category.Limit(1).Documents(ctx).GetAll()
+        if err != nil || len(docs) == 0 {
+            continue
+        }
+        if description, ok := docs[0].Data()["description"].(string); ok {
+            categoryList = append(categoryList, models.TherapyCategory{
+                Category:    category.ID,
+                Description: description,
+            })
+        }
+    }
+    return categoryList, nil
+}
+
+// ListTherapyQuestions retrieves therapy questions by type and category.
+// Malformed documents are skipped and their IDs returned.
+func (r *FirestoreRepository) ListTherapyQuestions(ctx context.Context, questionType, category string) ([]models.TherapyQuestion, []string, error) {
+    docs, err := r.client.Collection("therapyQuestions").Doc(questionType).Collection(category).Documents(ctx).GetAll()
+    if err != nil {
+        return nil, nil, err
+    }
+
+    questions := make([]models.TherapyQuestion, 0, len(docs))
+    var skipped []string
+    for _, doc := range docs {
+        q, err := decodeTherapyQuestion(doc.Ref.ID, doc.Data())
+        if err != nil {
+            log.Printf("Skipping therapy question: %v", err)
+            skipped = append(skipped, doc.Ref.ID)
+            continue
+        }
+        questions = append(questions, q)
+    }
+    return questions, skipped, nil
+}
+
+// GetTherapyQuestion retrieves a therapy question by type, category and ID.
+func (r *FirestoreRepository) GetTherapyQuestion(ctx context.Context, questionType, category, questionID string) (models.TherapyQuestion, error) {
+    doc, err := r.client.Collection("therapyQuestions").Doc(questionType).Collection(category).Doc(questionID).Get(ctx)
+    if err != nil {
+        return models.TherapyQuestion{}, notFound(err)
+    }
+    return decodeTherapyQuestion(doc.Ref.ID, doc.Data())
+}
+
+// CreateTherapyQuestion adds a therapy question and returns its generated ID.
+func (r *FirestoreRepository) CreateTherapyQuestion(ctx context.Context, question models.TherapyQuestion) (string, error) {
+    docRef, _, err := r.client.Collection("therapyQuestions").Doc(question.Type).Collection(question.Category).Add(ctx, encodeTherapyQuestion(question))
+    if err != nil {
+        return "", err
+    }
+    return docRef.ID, nil
+}
+
+// UpdateTherapyQuestion merges the question into its existing document.
+func (r *FirestoreRepository) UpdateTherapyQuestion(ctx context.Context, questionID string, question models.TherapyQuestion) error {
+    _, err := r.client.Collection("therapyQuestions").Doc(question.Type).Collection(question.Category).Doc(questionID).Set(ctx, encodeTherapyQuestion(question), firestore.MergeAll)
+    return err
+}
+
+// DeleteTherapyQuestion removes a therapy question.
+func (r *FirestoreRepository) DeleteTherapyQuestion(ctx context.Context, questionType, category, questionID string) error {
+    _, err := r.client.Collection("therapyQuestions").Doc(questionType).Collection(category).Doc(questionID).Delete(ctx)
+    return err
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "time"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// reviews returns the collection holding a user's spaced-repetition schedules.
+func (r *FirestoreRepository) reviews(userID string) *firestore.CollectionRef {
+    return r.client.Collection("users").Doc(userID).Collection("reviews")
+}
+
+// UpdateReviewState applies update to the user's schedule for a question inside a transaction.
+func (r *FirestoreRepository) UpdateReviewState(ctx context.Context, userID, questionID string, update func(state *models.ReviewState) error) (models.ReviewState, error) {
+    docRef := r.reviews(userID).Doc(questionID)
+
+    var state models.ReviewState
+    err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+        state = models.ReviewState{QuestionID: questionID}
+        doc, err := tx.Get(docRef)
+        if err != nil && notFound(err) != ErrNotFound {
+            return fmt.Errorf("failed to fetch review state: %w", err)
+        }
+        if err == nil {
+            if err := doc.DataTo(&state); err != nil {
+                return fmt.Errorf("failed to parse review state: %w", err)
+            }
+            state.QuestionID = questionID
+        }
+
+        if err := update(&state); err != nil {
+            return err
+        }
+
+        return tx.Set(docRef, map[string]interface{}{
+            "questionID":     state.QuestionID,
+            "type":           state.Type,
+            "category":       state.Category,
+            "easeFactor":     state.EaseFactor,
+            "intervalDays":   state.IntervalDays,
+            "repetitions":    state.Repetitions,
+            "lastQuality":    state.LastQuality,
+            "lastReviewedAt": state.LastReviewedAt,
+            "dueAt":          state.DueAt,
+        })
+    })
+    if err != nil {
+        return models.ReviewState{}, err
+    }
+    return state, nil
+}
+
+// ListDueReviews retrieves the user's schedules due at or before dueBy, earliest first.
+func (r *FirestoreRepository) ListDueReviews(ctx context.Context, userID string, dueBy time.Time) ([]models.ReviewState, error) {
+    docs, err := r.reviews(userID).Where("dueAt", "<=", dueBy).OrderBy("dueAt", firestore.Asc).Documents(ctx).GetAll()
+    if err != nil {
+        return nil, err
+    }
+
+    states := make([]models.ReviewState, 0, len(docs))
+    for _, doc := range docs {
+        var state models.ReviewState
+        if err := doc.DataTo(&state); err != nil {
+            log.Printf("Failed to parse review state for doc %s: %v", doc.Ref.ID, err)
+            continue
+        }
+        state.QuestionID = doc.Ref.ID
+        states = append(states, state)
+    }
+    return states, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// GetScreeningRule reads the scoringRule field of the age group's screeningQuestions document.
+func (r *FirestoreRepository) GetScreeningRule(ctx context.Context, ageGroup string) (models.ScreeningRule, error) {
+    doc, err := r.client.Collection("screeningQuestions").Doc(ageGroup).Get(ctx)
+    if err != nil {
+        return models.ScreeningRule{}, notFound(err)
+    }
+
+    var data struct {
+        ScoringRule *models.ScreeningRule `firestore:"scoringRule"`
+    }
+    if err := doc.DataTo(&data); err != nil {
+        return models.ScreeningRule{}, fmt.Errorf("failed to parse screening rule: %w", err)
+    }
+    if data.ScoringRule == nil {
+        return models.ScreeningRule{}, ErrNotFound
+    }
+    data.ScoringRule.AgeGroup = ageGroup
+    return *data.ScoringRule, nil
+}
+
+// SaveScreeningRule replaces the scoringRule field of the age group's screeningQuestions document.
+func (r *FirestoreRepository) SaveScreeningRule(ctx context.Context, rule models.ScreeningRule) error {
+    domains := make([]map[string]interface{}, len(rule.Domains))
+    for i, d := range rule.Domains {
+        domains[i] = map[string]interface{}{
+            "domain":      d.Domain,
+            "lowMax":      d.LowMax,
+            "moderateMax": d.ModerateMax,
+        }
+    }
+
+    _, err := r.client.Collection("screeningQuestions").Doc(rule.AgeGroup).Set(ctx, map[string]interface{}{
+        "scoringRule": map[string]interface{}{
+            "ageGroup":          rule.AgeGroup,
+            "lowMax":            rule.LowMax,
+            "moderateMax":       rule.ModerateMax,
+            "domains":           domains,
+            "criticalItems":     rule.CriticalItems,
+            "criticalRiskLevel": rule.CriticalRiskLevel,
+            "updatedBy":         rule.UpdatedBy,
+            "updatedAt":         rule.UpdatedAt,
+        },
+    }, firestore.Merge([]string{"scoringRule"}))
+    return err
+}
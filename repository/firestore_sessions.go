@@ -0,0 +1,129 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "sort"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// assessmentSessions returns the collection holding a user's assessment sessions.
+func (r *FirestoreRepository) assessmentSessions(userID string) *firestore.CollectionRef {
+    return r.client.Collection("users").Doc(userID).Collection("assessmentSessions")
+}
+
+// CreateAssessmentSession stores a new assessment session and returns its generated ID.
+func (r *FirestoreRepository) CreateAssessmentSession(ctx context.Context, session models.AssessmentSession) (string, error) {
+    docRef, _, err := r.assessmentSessions(session.UserID).Add(ctx, assessmentSessionData(session))
+    if err != nil {
+        return "", err
+    }
+    return docRef.ID, nil
+}
+
+// GetAssessmentSession retrieves one of the user's assessment sessions.
+func (r *FirestoreRepository) GetAssessmentSession(ctx context.Context, userID, sessionID string) (models.AssessmentSession, error) {
+    doc, err := r.assessmentSessions(userID).Doc(sessionID).Get(ctx)
+    if err != nil {
+        return models.AssessmentSession{}, notFound(err)
+    }
+    return decodeAssessmentSession(doc)
+}
+
+// UpdateAssessmentSession applies update to an existing session inside a transaction.
+func (r *FirestoreRepository) UpdateAssessmentSession(ctx context.Context, userID, sessionID string, update func(session *models.AssessmentSession) error) (models.AssessmentSession, error) {
+    docRef := r.assessmentSessions(userID).Doc(sessionID)
+
+    var session models.AssessmentSession
+    err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+        doc, err := tx.Get(docRef)
+        if err != nil {
+            return notFound(err)
+        }
+        session, err = decodeAssessmentSession(doc)
+        if err != nil {
+            return err
+        }
+
+        if err := update(&session); err != nil {
+            return err
+        }
+        return tx.Set(docRef, assessmentSessionData(session))
+    })
+    if err != nil {
+        return models.AssessmentSession{}, err
+    }
+    return session, nil
+}
+
+// ListAssessmentSessions retrieves the user's sessions for a type, oldest first.
+func (r *FirestoreRepository) ListAssessmentSessions(ctx context.Context, userID, questionType string) ([]models.AssessmentSession, error) {
+    docs, err := r.assessmentSessions(userID).Where("type", "==", questionType).Documents(ctx).GetAll()
+    if err != nil {
+        return nil, err
+    }
+
+    sessions := make([]models.AssessmentSession, 0, len(docs))
+    for _, doc := range docs {
+        session, err := decodeAssessmentSession(doc)
+        if err != nil {
+            log.Printf("Failed to parse assessment session %s: %v", doc.Ref.ID, err)
+            continue
+        }
+        sessions = append(sessions, session)
+    }
+    sort.Slice(sessions, func(i, j int) bool {
+        return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+    })
+    return sessions, nil
+}
+
+// decodeAssessmentSession converts a session document into an AssessmentSession.
+func decodeAssessmentSession(doc *firestore.DocumentSnapshot) (models.AssessmentSession, error) {
+    var session models.AssessmentSession
+    if err := doc.DataTo(&session); err != nil {
+        return models.AssessmentSession{}, fmt.Errorf("failed to parse assessment session: %w", err)
+    }
+    session.ID = doc.Ref.ID
+    return session, nil
+}
+
+// assessmentSessionData converts an AssessmentSession into its Firestore document fields.
+func assessmentSessionData(session models.AssessmentSession) map[string]interface{} {
+    data := map[string]interface{}{
+        "userID":         session.UserID,
+        "type":           session.Type,
+        "status":         session.Status,
+        "questionIDs":    session.QuestionIDs,
+        "answers":        assessmentAnswersData(session.Answers),
+        "correctAnswers": session.CorrectAnswers,
+        "score":          session.Score,
+        "startedAt":      session.StartedAt,
+        "updatedAt":      session.UpdatedAt,
+    }
+    if session.FinishedAt != nil {
+        data["finishedAt"] = *session.FinishedAt
+    }
+    return data
+}
+
+// assessmentAnswersData converts scored assessment answers into Firestore document fields.
+func assessmentAnswersData(answers []models.AssessmentAnswer) []map[string]interface{} {
+    data := make([]map[string]interface{}, len(answers))
+    for i, answer := range answers {
+        data[i] = map[string]interface{}{
+            "questionID": answer.QuestionID,
+            "category":   answer.Category,
+            "answer":     answer.Answer,
+            "correct":    answer.Correct,
+            "score":      answer.Score,
+            "items":      itemResultsData(answer.Items),
+            "match":      matchResultData(answer.Match),
+            "answeredAt": answer.AnsweredAt,
+        }
+    }
+    return data
+}
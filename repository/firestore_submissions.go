@@ -0,0 +1,293 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "time"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// SaveAssessmentSubmission stores a scored assessment answer, replacing any earlier answer to the same question.
+func (r *FirestoreRepository) SaveAssessmentSubmission(ctx context.Context, userID string, record models.AssessmentSubmissionRecord) error {
+    _, err := r.client.Collection("users").Doc(userID).Collection("assessments").Doc(record.Type).Collection("submissions").Doc(record.QuestionID).Set(ctx, map[string]interface{}{
+        "type":           record.Type,
+        "category":       record.Category,
+        "questionID":     record.QuestionID,
+        "correctAnswers": record.CorrectAnswers,
+        "score":          record.Score,
+        "items":          itemResultsData(record.Items),
+        "match":          matchResultData(record.Match),
+        "answer":         record.Answer,
+        "status":         record.Status,
+        "timestamp":      firestore.ServerTimestamp,
+    }, firestore.MergeAll)
+    return err
+}
+
+// ListAssessmentSubmissions retrieves a user's assessment answers for a type.
+func (r *FirestoreRepository) ListAssessmentSubmissions(ctx context.Context, userID, questionType string) ([]models.AssessmentSubmissionRecord, error) {
+    docs, err := r.client.Collection("users").Doc(userID).Collection("assessments").Doc(questionType).Collection("submissions").Documents(ctx).GetAll()
+    if err != nil {
+        return nil, notFound(err)
+    }
+
+    records := make([]models.AssessmentSubmissionRecord, 0, len(docs))
+    for _, doc := range docs {
+        data := doc.Data()
+        record := models.AssessmentSubmissionRecord{
+            QuestionID:     doc.Ref.ID,
+            Type:           questionType,
+            CorrectAnswers: intField(data, "correctAnswers"),
+            Score:          submissionScore(data),
+            Items:          itemResultsFromData(data["items"]),
+            Match:          matchResultFromData(data["match"]),
+            Answer:         data["answer"],
+        }
+        record.Category, _ = data["category"].(string)
+        record.Status, _ = data["status"].(string)
+        record.Timestamp, _ = data["timestamp"].(time.Time)
+        records = append(records, record)
+    }
+    return records, nil
+}
+
+// therapyAnswerHistory returns the collection holding a user's answer history for a therapy category.
+func (r *FirestoreRepository) therapyAnswerHistory(userID, questionType, category string) *firestore.CollectionRef {
+    return r.client.Collection("users").Doc(userID).Collection("therapyHistory").Doc(questionType).Collection(category)
+}
+
+// SaveTherapySubmission stores a scored therapy answer, replacing any earlier answer to the same
+// question, and appends it to the category's answer history in the same transaction.
+func (r *FirestoreRepository) SaveTherapySubmission(ctx context.Context, userID string, record models.TherapySubmissionRecord) error {
+    latest := r.client.Collection("users").Doc(userID).Collection("therapy").Doc(record.Type).Collection(record.Category).Doc(record.QuestionID)
+    history := r.therapyAnswerHistory(userID, record.Type, record.Category).NewDoc()
+    return r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+        err := tx.Set(latest, map[string]interface{}{
+            "type":           record.Type,
+            "category":       record.Category,
+            "questionID":     record.QuestionID,
+            "correctAnswers": record.CorrectAnswers,
+            "score":          record.Score,
+            "items":          itemResultsData(record.Items),
+            "match":          matchResultData(record.Match),
+            "answer":         record.Answer,
+            "status":         record.Status,
+            "timestamp":      firestore.ServerTimestamp,
+        }, firestore.MergeAll)
+        if err != nil {
+            return err
+        }
+        return tx.Create(history, map[string]interface{}{
+            "questionID":     record.QuestionID,
+            "correctAnswers": record.CorrectAnswers,
+            "score":          record.Score,
+            "timestamp":      firestore.ServerTimestamp,
+        })
+    })
+}
+
+// ListTherapyAnswerHistory retrieves up to limit of a user's most recent therapy answers for a
+// type and category, oldest first.
+func (r *FirestoreRepository) ListTherapyAnswerHistory(ctx context.Context, userID, questionType, category string, limit int) ([]models.TherapySubmissionRecord, error) {
+    docs, err := r.therapyAnswerHistory(userID, questionType, category).OrderBy("timestamp", firestore.Desc).Limit(limit).Documents(ctx).GetAll()
+    if err != nil {
+        return nil, err
+    }
+
+    records := make([]models.TherapySubmissionRecord, len(docs))
+    for i, doc := range docs {
+        data := doc.Data()
+        record := models.TherapySubmissionRecord{
+            Type:           questionType,
+            Category:       category,
+            CorrectAnswers: intField(data, "correctAnswers"),
+            Score:          submissionScore(data),
+            Status:         "completed",
+        }
+        record.QuestionID, _ = data["questionID"].(string)
+        record.Timestamp, _ = data["timestamp"].(time.Time)
+        records[len(docs)-1-i] = record
+    }
+    return records, nil
+}
+
+// ListTherapySubmissions retrieves a user's therapy answers for a type and category.
+func (r *FirestoreRepository) ListTherapySubmissions(ctx context.Context, userID, questionType, category string) ([]models.TherapySubmissionRecord, error) {
+    docs, err := r.client.Collection("users").Doc(userID).Collection("therapy").Doc(questionType).Collection(category).Documents(ctx).GetAll()
+    if err != nil {
+        return nil, notFound(err)
+    }
+
+    records := make([]models.TherapySubmissionRecord, 0, len(docs))
+    for _, doc := range docs {
+        data := doc.Data()
+        record := models.TherapySubmissionRecord{
+            QuestionID:     doc.Ref.ID,
+            Type:           questionType,
+            Category:       category,
+            CorrectAnswers: intField(data, "correctAnswers"),
+            Score:          submissionScore(data),
+            Items:          itemResultsFromData(data["items"]),
+            Match:          matchResultFromData(data["match"]),
+            Answer:         data["answer"],
+        }
+        record.Status, _ = data["status"].(string)
+        record.Timestamp, _ = data["timestamp"].(time.Time)
+        records = append(records, record)
+    }
+    return records, nil
+}
+
+// screenings returns the collection holding a user's screening results.
+func (r *FirestoreRepository) screenings(userID string) *firestore.CollectionRef {
+    return r.client.Collection("users").Doc(userID).Collection("screenings")
+}
+
+// SaveScreeningResult stores a new screening result. Results are never updated once written.
+func (r *FirestoreRepository) SaveScreeningResult(ctx context.Context, userID string, result models.ScreeningResult) (string, error) {
+    docRef, _, err := r.screenings(userID).Add(ctx, map[string]interface{}{
+        "ageGroup":           result.AgeGroup,
+        "questionSetVersion": result.QuestionSetVersion,
+        "questionIDs":        result.QuestionIDs,
+        "answers":            result.Answers,
+        "score":              result.Score,
+        "riskLevel":          result.RiskLevel,
+        "domains":            screeningDomainScoresData(result.Domains),
+        "criticalItems":      result.CriticalItems,
+        "previousRiskLevel":  result.PreviousRiskLevel,
+        "trend":              result.Trend,
+        "timestamp":          result.Timestamp,
+    })
+    if err != nil {
+        return "", err
+    }
+    return docRef.ID, nil
+}
+
+// GetScreeningResult retrieves the user's latest screening result.
+func (r *FirestoreRepository) GetScreeningResult(ctx context.Context, userID string) (models.ScreeningResult, error) {
+    docs, err := r.screenings(userID).OrderBy("timestamp", firestore.Desc).Limit(1).Documents(ctx).GetAll()
+    if err != nil {
+        return models.ScreeningResult{}, err
+    }
+    if len(docs) == 0 {
+        return models.ScreeningResult{}, ErrNotFound
+    }
+    return decodeScreeningResult(docs[0])
+}
+
+// ListScreeningResults retrieves every screening result of the user, oldest first.
+func (r *FirestoreRepository) ListScreeningResults(ctx context.Context, userID string) ([]models.ScreeningResult, error) {
+    docs, err := r.screenings(userID).OrderBy("timestamp", firestore.Asc).Documents(ctx).GetAll()
+    if err != nil {
+        return nil, err
+    }
+
+    results := make([]models.ScreeningResult, 0, len(docs))
+    for _, doc := range docs {
+        result, err := decodeScreeningResult(doc)
+        if err != nil {
+            log.Printf("Failed to parse screening result %s: %v", doc.Ref.ID, err)
+            continue
+        }
+        results = append(results, result)
+    }
+    return results, nil
+}
+
+// screeningDomainScoresData converts domain sub-scores into their Firestore representation.
+func screeningDomainScoresData(domains []models.ScreeningDomainScore) []map[string]interface{} {
+    data := make([]map[string]interface{}, len(domains))
+    for i, d := range domains {
+        data[i] = map[string]interface{}{
+            "domain":    d.Domain,
+            "score":     d.Score,
+            "riskLevel": d.RiskLevel,
+            "driver":    d.Driver,
+        }
+    }
+    return data
+}
+
+// decodeScreeningResult converts a screening document into a ScreeningResult.
+func decodeScreeningResult(doc *firestore.DocumentSnapshot) (models.ScreeningResult, error) {
+    var result models.ScreeningResult
+    if err := doc.DataTo(&result); err != nil {
+        return models.ScreeningResult{}, fmt.Errorf("failed to parse screening result: %w", err)
+    }
+    result.ID = doc.Ref.ID
+    return result, nil
+}
+
+// submissionScore reads a submission's score. Submissions stored before partial credit
+// have no score, so their correctAnswers count is used instead.
+func submissionScore(data map[string]interface{}) float64 {
+    if _, ok := data["score"]; !ok {
+        return float64(intField(data, "correctAnswers"))
+    }
+    return floatField(data, "score")
+}
+
+// itemResultsData converts per-item results into Firestore document fields.
+func itemResultsData(items []models.ItemResult) []map[string]interface{} {
+    data := make([]map[string]interface{}, len(items))
+    for i, item := range items {
+        data[i] = map[string]interface{}{
+            "index":    item.Index,
+            "left":     item.Left,
+            "expected": item.Expected,
+            "answer":   item.Answer,
+            "correct":  item.Correct,
+        }
+    }
+    return data
+}
+
+// itemResultsFromData converts stored per-item results back into ItemResults.
+func itemResultsFromData(raw interface{}) []models.ItemResult {
+    entries, ok := raw.([]interface{})
+    if !ok {
+        return nil
+    }
+    items := make([]models.ItemResult, 0, len(entries))
+    for _, entry := range entries {
+        data, ok := entry.(map[string]interface{})
+        if !ok {
+            continue
+        }
+        item := models.ItemResult{Index: intField(data, "index")}
+        item.Left, _ = data["left"].(string)
+        item.Expected, _ = data["expected"].(string)
+        item.Answer, _ = data["answer"].(string)
+        item.Correct, _ = data["correct"].(bool)
+        items = append(items, item)
+    }
+    return items
+}
+
+// matchResultData converts a string match result into Firestore document fields.
+func matchResultData(match *models.MatchResult) map[string]interface{} {
+    if match == nil {
+        return nil
+    }
+    return map[string]interface{}{
+        "quality":    match.Quality,
+        "normalized": match.Normalized,
+        "distance":   match.Distance,
+    }
+}
+
+// matchResultFromData converts a stored string match result back into a MatchResult.
+func matchResultFromData(raw interface{}) *models.MatchResult {
+    data, ok := raw.(map[string]interface{})
+    if !ok {
+        return nil
+    }
+    match := &models.MatchResult{Distance: intField(data, "distance")}
+    match.Quality, _ = data["quality"].(string)
+    match.Normalized, _ = data["normalized"].(string)
+    return match
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+    "context"
+    "fmt"
+
+    "cloud.google.com/go/firestore"
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// GetUser retrieves a user document.
+func (r *FirestoreRepository) GetUser(ctx context.Context, userID string) (models.User, error) {
+    doc, err := r.client.Collection("users").Doc(userID).Get(ctx)
+    if err != nil {
+        return models.User{}, notFound(err)
+    }
+
+    var user models.User
+    if err := doc.DataTo(&user); err != nil {
+        return models.User{}, fmt.Errorf("failed to parse user data: %w", err)
+    }
+    user.ID = doc.Ref.ID
+    return user, nil
+}
+
+// SaveUser merges the user's account fields into their document.
+func (r *FirestoreRepository) SaveUser(ctx context.Context, user models.User) error {
+    userData := map[string]interface{}{
+        "email":    user.Email,
+        "username": user.Username,
+    }
+    if !user.CreatedAt.IsZero() {
+        userData["createdAt"] = user.CreatedAt
+    }
+
+    _, err := r.client.Collection("users").Doc(user.ID).Set(ctx, userData, firestore.MergeAll)
+    return err
+}
+
+// SaveLearningProfile replaces the learningProfile field of the user's document.
+func (r *FirestoreRepository) SaveLearningProfile(ctx context.Context, userID string, profile models.LearningProfile) error {
+    _, err := r.client.Collection("users").Doc(userID).Set(ctx, map[string]interface{}{
+        "learningProfile": learningProfileData(profile),
+    }, firestore.Merge([]string{"learningProfile"}))
+    return err
+}
+
+// SaveUserSettings replaces the settings field of the user's document.
+func (r *FirestoreRepository) SaveUserSettings(ctx context.Context, userID string, settings models.UserSettings) error {
+    _, err := r.client.Collection("users").Doc(userID).Set(ctx, map[string]interface{}{
+        "settings": map[string]interface{}{
+            "timezone": settings.Timezone,
+        },
+    }, firestore.Merge([]string{"settings"}))
+    return err
+}
+
+// SaveDailyGoal replaces the dailyGoal field of the user's document.
+func (r *FirestoreRepository) SaveDailyGoal(ctx context.Context, userID string, goal models.DailyGoal) error {
+    _, err := r.client.Collection("users").Doc(userID).Set(ctx, map[string]interface{}{
+        "dailyGoal": dailyGoalData(goal),
+    }, firestore.Merge([]string{"dailyGoal"}))
+    return err
+}
+
+// SaveUserRoles replaces the roles field of the user's document.
+func (r *FirestoreRepository) SaveUserRoles(ctx context.Context, userID string, roles []string) error {
+    _, err := r.client.Collection("users").Doc(userID).Set(ctx, map[string]interface{}{
+        "roles": roles,
+    }, firestore.Merge([]string{"roles"}))
+    return err
+}
+
+// SaveUserTherapists replaces the therapistIDs field of the user's document.
+func (r *FirestoreRepository) SaveUserTherapists(ctx context.Context, userID string, therapistIDs []string) error {
+    _, err := r.client.Collection("users").Doc(userID).Set(ctx, map[string]interface{}{
+        "therapistIDs": therapistIDs,
+    }, firestore.Merge([]string{"therapistIDs"}))
+    return err
+}
+
+// dailyGoalData converts a daily goal into its Firestore representation.
+func dailyGoalData(goal models.DailyGoal) map[string]interface{} {
+    return map[string]interface{}{
+        "metric":    goal.Metric,
+        "target":    goal.Target,
+        "setBy":     goal.SetBy,
+        "updatedAt": goal.UpdatedAt,
+    }
+}
+
+// learningProfileData converts a learning profile into its Firestore representation.
+func learningProfileData(profile models.LearningProfile) map[string]interface{} {
+    modalities := make([]map[string]interface{}, len(profile.Modalities))
+    for i, m := range profile.Modalities {
+        modalities[i] = map[string]interface{}{
+            "type":           m.Type,
+            "assessed":       m.Assessed,
+            "correctAnswers": m.CorrectAnswers,
+            "totalQuestions": m.TotalQuestions,
+            "score":          m.Score,
+            "level":          m.Level,
+        }
+    }
+    return map[string]interface{}{
+        "dominantModality": profile.DominantModality,
+        "modalities":       modalities,
+        "categories":       categoryProfilesData(profile.Categories),
+        "strengths":        profile.Strengths,
+        "weaknesses":       profile.Weaknesses,
+        "riskAreas":        categoryProfilesData(profile.RiskAreas),
+        "complete":         profile.Complete,
+        "computedAt":       profile.ComputedAt,
+    }
+}
+
+// categoryProfilesData converts category profiles into their Firestore representation.
+func categoryProfilesData(categories []models.CategoryProfile) []map[string]interface{} {
+    data := make([]map[string]interface{}, len(categories))
+    for i, c := range categories {
+        data[i] = map[string]interface{}{
+            "type":           c.Type,
+            "category":       c.Category,
+            "correctAnswers": c.CorrectAnswers,
+            "totalQuestions": c.TotalQuestions,
+            "score":          c.Score,
+            "level":          c.Level,
+        }
+    }
+    return data
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+    "crypto/rand"
+    "sync"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// MemoryRepository implements Repository entirely in process memory.
+// It is intended for local development and tests; all data is lost on restart.
+type MemoryRepository struct {
+    mu sync.RWMutex
+
+    screeningQuestions  map[string]models.ScreeningQuestion
+    screeningRules      map[string]models.ScreeningRule
+    assessmentQuestions map[string]models.AssessmentQuestion
+    therapyQuestions    map[string]models.TherapyQuestion
+    questionIndex       map[string]models.QuestionLocation
+
+    assessmentSubmissions map[string]map[string]models.AssessmentSubmissionRecord
+    therapySubmissions    map[string]map[string]models.TherapySubmissionRecord
+    therapyHistory        map[string][]models.TherapySubmissionRecord
+    screeningResults      map[string][]models.ScreeningResult
+    assessmentSessions    map[string]map[string]models.AssessmentSession
+    assessmentAttempts    map[string]map[string]models.AssessmentAttempt
+
+    progress map[string]map[string]models.DailyProgress
+    streaks  map[string]models.StreakSummary
+    reviews  map[string]map[string]models.ReviewState
+
+    achievements map[string]map[string]models.Achievement
+    authSessions  map[string]map[string]models.AuthSession
+    childProfiles map[string]map[string]models.ChildProfile
+    revocations   map[string]models.TokenRevocation
+    users         map[string]models.User
+}
+
+// Ensure MemoryRepository satisfies Repository.
+var _ Repository = (*MemoryRepository)(nil)
+
+// NewMemoryRepository creates an empty in-memory Repository.
+func NewMemoryRepository() *MemoryRepository {
+    return &MemoryRepository{
+        screeningQuestions:    make(map[string]models.ScreeningQuestion),
+        screeningRules:        make(map[string]models.ScreeningRule),
+        assessmentQuestions:   make(map[string]models.AssessmentQuestion),
+        therapyQuestions:      make(map[string]models.TherapyQuestion),
+        questionIndex:         make(map[string]models.QuestionLocation),
+        assessmentSubmissions: make(map[string]map[string]models.AssessmentSubmissionRecord),
+        therapySubmissions:    make(map[string]map[string]models.TherapySubmissionRecord),
+        therapyHistory:        make(map[string][]models.TherapySubmissionRecord),
+        screeningResults:      make(map[string][]models.ScreeningResult),
+        assessmentSessions:    make(map[string]map[string]models.AssessmentSession),
+        assessmentAttempts:    make(map[string]map[string]models.AssessmentAttempt),
+        progress:              make(map[string]map[string]models.DailyProgress),
+        streaks:               make(map[string]models.StreakSummary),
+        reviews:               make(map[string]map[string]models.ReviewState),
+        achievements:          make(map[string]map[string]models.Achievement),
+        authSessions:          make(map[string]map[string]models.AuthSession),
+        childProfiles:         make(map[string]map[string]models.ChildProfile),
+        revocations:           make(map[string]models.TokenRevocation),
+        users:                 make(map[string]models.User),
+    }
+}
+
+// idAlphabet matches the characters Firestore uses for auto-generated document IDs.
+const idAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// newID generates a random 20-character document ID.
+func newID() string {
+    b := make([]byte, 20)
+    rand.Read(b)
+    for i := range b {
+        b[i] = idAlphabet[int(b[i])%len(idAlphabet)]
+    }
+    return string(b)
+}
+
+// userKey builds a map key scoped to a user.
+func userKey(userID string, parts ...string) string {
+    key := userID
+    for _, part := range parts {
+        key += "/" + part
+    }
+    return key
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+    "context"
+    "sort"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// CreateAchievement stores a newly unlocked badge for the user.
+func (r *MemoryRepository) CreateAchievement(ctx context.Context, userID string, achievement models.Achievement) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if _, exists := r.achievements[userID][achievement.BadgeID]; exists {
+        return ErrAlreadyExists
+    }
+    if r.achievements[userID] == nil {
+        r.achievements[userID] = make(map[string]models.Achievement)
+    }
+    r.achievements[userID][achievement.BadgeID] = achievement
+    return nil
+}
+
+// ListAchievements retrieves the user's unlocked badges, oldest first.
+func (r *MemoryRepository) ListAchievements(ctx context.Context, userID string) ([]models.Achievement, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    achievements := make([]models.Achievement, 0, len(r.achievements[userID]))
+    for _, achievement := range r.achievements[userID] {
+        achievements = append(achievements, achievement)
+    }
+    sort.Slice(achievements, func(i, j int) bool {
+        return achievements[i].UnlockedAt.Before(achievements[j].UnlockedAt)
+    })
+    return achievements, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+    "context"
+    "sort"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// CreateAssessmentAttempt stores a new attempt. Attempts are never updated once written.
+func (r *MemoryRepository) CreateAssessmentAttempt(ctx context.Context, attempt models.AssessmentAttempt) (string, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if attempt.ID == "" {
+        attempt.ID = newID()
+    } else if _, ok := r.assessmentAttempts[attempt.UserID][attempt.ID]; ok {
+        return "", ErrAlreadyExists
+    }
+    if r.assessmentAttempts[attempt.UserID] == nil {
+        r.assessmentAttempts[attempt.UserID] = make(map[string]models.AssessmentAttempt)
+    }
+    r.assessmentAttempts[attempt.UserID][attempt.ID] = copyAttempt(attempt)
+    return attempt.ID, nil
+}
+
+// GetAssessmentAttempt retrieves one of the user's assessment attempts.
+func (r *MemoryRepository) GetAssessmentAttempt(ctx context.Context, userID, attemptID string) (models.AssessmentAttempt, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    attempt, ok := r.assessmentAttempts[userID][attemptID]
+    if !ok {
+        return models.AssessmentAttempt{}, ErrNotFound
+    }
+    return copyAttempt(attempt), nil
+}
+
+// ListAssessmentAttempts retrieves the user's attempts for a type, or for every type, oldest first.
+func (r *MemoryRepository) ListAssessmentAttempts(ctx context.Context, userID, questionType string) ([]models.AssessmentAttempt, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    attempts := make([]models.AssessmentAttempt, 0)
+    for _, attempt := range r.assessmentAttempts[userID] {
+        if questionType == "" || attempt.Type == questionType {
+            attempts = append(attempts, copyAttempt(attempt))
+        }
+    }
+    sort.Slice(attempts, func(i, j int) bool {
+        return attempts[i].CompletedAt.Before(attempts[j].CompletedAt)
+    })
+    return attempts, nil
+}
+
+// copyAttempt returns an attempt whose slices do not alias the original's.
+func copyAttempt(attempt models.AssessmentAttempt) models.AssessmentAttempt {
+    attempt.Answers = append([]models.AssessmentAnswer(nil), attempt.Answers...)
+    attempt.Categories = append([]models.CategoryScore(nil), attempt.Categories...)
+    return attempt
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+    "context"
+    "sort"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// CreateAuthSession stores a new auth session and returns its generated ID.
+func (r *MemoryRepository) CreateAuthSession(ctx context.Context, session models.AuthSession) (string, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    session.ID = newID()
+    if r.authSessions[session.UserID] == nil {
+        r.authSessions[session.UserID] = make(map[string]models.AuthSession)
+    }
+    r.authSessions[session.UserID][session.ID] = session
+    return session.ID, nil
+}
+
+// GetAuthSession retrieves one of the user's auth sessions.
+func (r *MemoryRepository) GetAuthSession(ctx context.Context, userID, sessionID string) (models.AuthSession, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    session, ok := r.authSessions[userID][sessionID]
+    if !ok {
+        return models.AuthSession{}, ErrNotFound
+    }
+    return session, nil
+}
+
+// UpdateAuthSession applies update to an existing session while holding the write lock.
+func (r *MemoryRepository) UpdateAuthSession(ctx context.Context, userID, sessionID string, update func(session *models.AuthSession) error) (models.AuthSession, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    session, ok := r.authSessions[userID][sessionID]
+    if !ok {
+        return models.AuthSession{}, ErrNotFound
+    }
+    if err := update(&session); err != nil {
+        return models.AuthSession{}, err
+    }
+    r.authSessions[userID][sessionID] = session
+    return session, nil
+}
+
+// ListAuthSessions retrieves every session of the user, including revoked ones, oldest first.
+func (r *MemoryRepository) ListAuthSessions(ctx context.Context, userID string) ([]models.AuthSession, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    sessions := make([]models.AuthSession, 0, len(r.authSessions[userID]))
+    for _, session := range r.authSessions[userID] {
+        sessions = append(sessions, session)
+    }
+    sort.Slice(sessions, func(i, j int) bool {
+        return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+    })
+    return sessions, nil
+}
+
+// RevokeTokens adds entries to the revocation list, dropping entries that have expired.
+func (r *MemoryRepository) RevokeTokens(ctx context.Context, revocations []models.TokenRevocation) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    now := time.Now()
+    for id, revocation := range r.revocations {
+        if now.After(revocation.ExpiresAt) {
+            delete(r.revocations, id)
+        }
+    }
+    for _, revocation := range revocations {
+        r.revocations[revocation.ID] = revocation
+    }
+    return nil
+}
+
+// GetTokenRevocation retrieves the revocation list entry of an access token or session ID.
+func (r *MemoryRepository) GetTokenRevocation(ctx context.Context, id string) (models.TokenRevocation, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    revocation, ok := r.revocations[id]
+    if !ok {
+        return models.TokenRevocation{}, ErrNotFound
+    }
+    return revocation, nil
+}
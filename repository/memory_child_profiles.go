@@ -0,0 +1,64 @@
+package repository
+
+import (
+    "context"
+    "sort"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// CreateChildProfile stores a new child profile and returns its generated ID.
+func (r *MemoryRepository) CreateChildProfile(ctx context.Context, profile models.ChildProfile) (string, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    profile.ID = newID()
+    if r.childProfiles[profile.GuardianID] == nil {
+        r.childProfiles[profile.GuardianID] = make(map[string]models.ChildProfile)
+    }
+    r.childProfiles[profile.GuardianID][profile.ID] = profile
+    return profile.ID, nil
+}
+
+// GetChildProfile retrieves one of the guardian's child profiles.
+func (r *MemoryRepository) GetChildProfile(ctx context.Context, guardianID, profileID string) (models.ChildProfile, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    profile, ok := r.childProfiles[guardianID][profileID]
+    if !ok {
+        return models.ChildProfile{}, ErrNotFound
+    }
+    return profile, nil
+}
+
+// UpdateChildProfile applies update to an existing child profile while holding the write lock.
+func (r *MemoryRepository) UpdateChildProfile(ctx context.Context, guardianID, profileID string, update func(profile *models.ChildProfile) error) (models.ChildProfile, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    profile, ok := r.childProfiles[guardianID][profileID]
+    if !ok {
+        return models.ChildProfile{}, ErrNotFound
+    }
+    if err := update(&profile); err != nil {
+        return models.ChildProfile{}, err
+    }
+    r.childProfiles[guardianID][profileID] = profile
+    return profile, nil
+}
+
+// ListChildProfiles retrieves every child profile of the guardian, oldest first.
+func (r *MemoryRepository) ListChildProfiles(ctx context.Context, guardianID string) ([]models.ChildProfile, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    profiles := make([]models.ChildProfile, 0, len(r.childProfiles[guardianID]))
+    for _, profile := range r.childProfiles[guardianID] {
+        profiles = append(profiles, profile)
+    }
+    sort.Slice(profiles, func(i, j int) bool {
+        return profiles[i].CreatedAt.Before(profiles[j].CreatedAt)
+    })
+    return profiles, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+    "context"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// GetQuestionLocation looks up where a question is stored.
+func (r *MemoryRepository) GetQuestionLocation(ctx context.Context, questionID string) (models.QuestionLocation, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    location, ok := r.questionIndex[questionID]
+    if !ok {
+        return models.QuestionLocation{}, ErrNotFound
+    }
+    return location, nil
+}
+
+// SetQuestionLocation records where a question is stored.
+func (r *MemoryRepository) SetQuestionLocation(ctx context.Context, location models.QuestionLocation) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    r.questionIndex[location.QuestionID] = location
+    return nil
+}
+
+// DeleteQuestionLocation removes a question from the index.
+func (r *MemoryRepository) DeleteQuestionLocation(ctx context.Context, questionID string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    delete(r.questionIndex, questionID)
+    return nil
+}
+
+// RebuildQuestionIndex replaces the index with the locations of every stored question.
+func (r *MemoryRepository) RebuildQuestionIndex(ctx context.Context) (models.QuestionIndexRebuild, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    r.questionIndex = make(map[string]models.QuestionLocation)
+    for id, q := range r.assessmentQuestions {
+        r.questionIndex[id] = models.QuestionLocation{QuestionID: id, Kind: models.QuestionKindAssessment, Type: q.Type, Category: q.Category}
+    }
+    for id, q := range r.therapyQuestions {
+        r.questionIndex[id] = models.QuestionLocation{QuestionID: id, Kind: models.QuestionKindTherapy, Type: q.Type, Category: q.Category}
+    }
+    return models.QuestionIndexRebuild{IndexedQuestions: len(r.questionIndex), SkippedQuestionIDs: []string{}}, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+    "context"
+    "maps"
+    "sort"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// UpdateDailyProgress applies update to the user's progress entry for date and their streak
+// summary while holding the write lock.
+func (r *MemoryRepository) UpdateDailyProgress(ctx context.Context, userID string, date time.Time, update func(progress *models.DailyProgress, streak *models.StreakSummary) error) (models.DailyProgress, models.StreakSummary, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    docID := date.Format("20060102")
+    progress, ok := r.progress[userID][docID]
+    if !ok {
+        progress = models.DailyProgress{UserID: userID, Date: date}
+    }
+    progress = copyProgress(progress)
+    streak, ok := r.streaks[userID]
+    if !ok {
+        streak = models.StreakSummary{UserID: userID}
+    }
+    streak = copyStreak(streak)
+
+    if err := update(&progress, &streak); err != nil {
+        return models.DailyProgress{}, models.StreakSummary{}, err
+    }
+
+    if r.progress[userID] == nil {
+        r.progress[userID] = make(map[string]models.DailyProgress)
+    }
+    r.progress[userID][docID] = copyProgress(progress)
+    r.streaks[userID] = copyStreak(streak)
+    return progress, streak, nil
+}
+
+// GetStreakSummary retrieves the user's streak summary.
+func (r *MemoryRepository) GetStreakSummary(ctx context.Context, userID string) (models.StreakSummary, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    streak, ok := r.streaks[userID]
+    if !ok {
+        return models.StreakSummary{UserID: userID}, nil
+    }
+    return copyStreak(streak), nil
+}
+
+// ListDailyProgress retrieves the user's progress entries dated between startDate and endDate inclusive.
+func (r *MemoryRepository) ListDailyProgress(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.DailyProgress, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    progress := make([]models.DailyProgress, 0)
+    for _, p := range r.progress[userID] {
+        if !p.Date.Before(startDate) && !p.Date.After(endDate) {
+            progress = append(progress, copyProgress(p))
+        }
+    }
+    sort.Slice(progress, func(i, j int) bool {
+        return progress[i].Date.Before(progress[j].Date)
+    })
+    return progress, nil
+}
+
+// copyProgress returns a progress entry whose breakdowns do not alias the original's.
+func copyProgress(progress models.DailyProgress) models.DailyProgress {
+    progress.Types = append([]models.ActivityBreakdown(nil), progress.Types...)
+    progress.Categories = append([]models.ActivityBreakdown(nil), progress.Categories...)
+    return progress
+}
+
+// copyStreak returns a streak summary whose correct-answer totals do not alias the original's.
+func copyStreak(streak models.StreakSummary) models.StreakSummary {
+    streak.CorrectAnswers = maps.Clone(streak.CorrectAnswers)
+    return streak
+}
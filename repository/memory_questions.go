@@ -0,0 +1,223 @@
+package repository
+
+import (
+    "context"
+    "sort"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// ListScreeningQuestions retrieves screening questions, optionally filtered by ageGroup. Questions
+// are stored decoded, so none are ever skipped.
+func (r *MemoryRepository) ListScreeningQuestions(ctx context.Context, ageGroup string) ([]models.ScreeningQuestion, []string, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    questions := make([]models.ScreeningQuestion, 0)
+    for _, q := range r.screeningQuestions {
+        if ageGroup == "" || q.AgeGroup == ageGroup {
+            questions = append(questions, q)
+        }
+    }
+    sort.Slice(questions, func(i, j int) bool {
+        if questions[i].AgeGroup != questions[j].AgeGroup {
+            return questions[i].AgeGroup < questions[j].AgeGroup
+        }
+        return questions[i].ID < questions[j].ID
+    })
+    return questions, nil, nil
+}
+
+// GetScreeningQuestion retrieves a screening question by age group and ID.
+func (r *MemoryRepository) GetScreeningQuestion(ctx context.Context, ageGroup, questionID string) (models.ScreeningQuestion, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    q, ok := r.screeningQuestions[questionID]
+    if !ok || q.AgeGroup != ageGroup {
+        return models.ScreeningQuestion{}, ErrNotFound
+    }
+    return q, nil
+}
+
+// CreateScreeningQuestion adds a screening question and returns its generated ID.
+func (r *MemoryRepository) CreateScreeningQuestion(ctx context.Context, question models.ScreeningQuestion) (string, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    question.ID = newID()
+    r.screeningQuestions[question.ID] = question
+    return question.ID, nil
+}
+
+// UpdateScreeningQuestion replaces a screening question.
+func (r *MemoryRepository) UpdateScreeningQuestion(ctx context.Context, questionID string, question models.ScreeningQuestion) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    question.ID = questionID
+    r.screeningQuestions[questionID] = question
+    return nil
+}
+
+// DeleteScreeningQuestion removes a screening question.
+func (r *MemoryRepository) DeleteScreeningQuestion(ctx context.Context, ageGroup, questionID string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if q, ok := r.screeningQuestions[questionID]; ok && q.AgeGroup == ageGroup {
+        delete(r.screeningQuestions, questionID)
+    }
+    return nil
+}
+
+// ListAssessmentQuestions retrieves every assessment question of a type across its categories.
+func (r *MemoryRepository) ListAssessmentQuestions(ctx context.Context, questionType string) ([]models.AssessmentQuestion, []string, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    questions := make([]models.AssessmentQuestion, 0)
+    for _, q := range r.assessmentQuestions {
+        if q.Type == questionType {
+            questions = append(questions, q)
+        }
+    }
+    sort.Slice(questions, func(i, j int) bool {
+        if questions[i].Category != questions[j].Category {
+            return questions[i].Category < questions[j].Category
+        }
+        return questions[i].ID < questions[j].ID
+    })
+    return questions, nil, nil
+}
+
+// GetAssessmentQuestion retrieves an assessment question by type, category and ID.
+func (r *MemoryRepository) GetAssessmentQuestion(ctx context.Context, questionType, category, questionID string) (models.AssessmentQuestion, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    q, ok := r.assessmentQuestions[questionID]
+    if !ok || q.Type != questionType || q.Category != category {
+        return models.AssessmentQuestion{}, ErrNotFound
+    }
+    return q, nil
+}
+
+// CreateAssessmentQuestion adds an assessment question and returns its generated ID.
+func (r *MemoryRepository) CreateAssessmentQuestion(ctx context.Context, question models.AssessmentQuestion) (string, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    question.ID = newID()
+    r.assessmentQuestions[question.ID] = question
+    return question.ID, nil
+}
+
+// UpdateAssessmentQuestion replaces an assessment question.
+func (r *MemoryRepository) UpdateAssessmentQuestion(ctx context.Context, questionID string, question models.AssessmentQuestion) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    question.ID = questionID
+    r.assessmentQuestions[questionID] = question
+    return nil
+}
+
+// DeleteAssessmentQuestion removes an assessment question.
+func (r *MemoryRepository) DeleteAssessmentQuestion(ctx context.Context, questionType, category, questionID string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if q, ok := r.assessmentQuestions[questionID]; ok && q.Type == questionType && q.Category == category {
+        delete(r.assessmentQuestions, questionID)
+    }
+    return nil
+}
+
+// ListTherapyCategories retrieves the categories of a therapy type with the description of their first question.
+func (r *MemoryRepository) ListTherapyCategories(ctx context.Context, questionType string) ([]models.TherapyCategory, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    first := make(map[string]models.TherapyQuestion)
+    for _, q := range r.therapyQuestions {
+        if q.Type != questionType {
+            continue
+        }
+        if current, ok := first[q.Category]; !ok || q.ID < current.ID {
+            first[q.Category] = q
+        }
+    }
+
+    categoryList := make([]models.TherapyCategory, 0, len(first))
+    for category, q := range first {
+        categoryList = append(categoryList, models.TherapyCategory{
+            Category:    category,
+            Description: q.Description,
+        })
+    }
+    sort.Slice(categoryList, func(i, j int) bool {
+        return categoryList[i].Category < categoryList[j].Category
+    })
+    return categoryList, nil
+}
+
+// ListTherapyQuestions retrieves therapy questions by type and category.
+func (r *MemoryRepository) ListTherapyQuestions(ctx context.Context, questionType, category string) ([]models.TherapyQuestion, []string, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    questions := make([]models.TherapyQuestion, 0)
+    for _, q := range r.therapyQuestions {
+        if q.Type == questionType && q.Category == category {
+            questions = append(questions, q)
+        }
+    }
+    sort.Slice(questions, func(i, j int) bool {
+        return questions[i].ID < questions[j].ID
+    })
+    return questions, nil, nil
+}
+
+// GetTherapyQuestion retrieves a therapy question by type, category and ID.
+func (r *MemoryRepository) GetTherapyQuestion(ctx context.Context, questionType, category, questionID string) (models.TherapyQuestion, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    q, ok := r.therapyQuestions[questionID]
+    if !ok || q.Type != questionType || q.Category != category {
+        return models.TherapyQuestion{}, ErrNotFound
+    }
+    return q, nil
+}
+
+// CreateTherapyQuestion adds a therapy question and returns its generated ID.
+func (r *MemoryRepository) CreateTherapyQuestion(ctx context.Context, question models.TherapyQuestion) (string, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    question.ID = newID()
+    r.therapyQuestions[question.ID] = question
+    return question.ID, nil
+}
+
+// UpdateTherapyQuestion replaces a therapy question.
+func (r *MemoryRepository) UpdateTherapyQuestion(ctx context.Context, questionID string, question models.TherapyQuestion) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    question.ID = questionID
+    r.therapyQuestions[questionID] = question
+    return nil
+}
+
+// DeleteTherapyQuestion removes a therapy question.
+func (r *MemoryRepository) DeleteTherapyQuestion(ctx context.Context, questionType, category, questionID string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if q, ok := r.therapyQuestions[questionID]; ok && q.Type == questionType && q.Category == category {
+        delete(r.therapyQuestions, questionID)
+    }
+    return nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+    "context"
+    "sort"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// UpdateReviewState applies update to the user's schedule for a question while holding the write lock.
+func (r *MemoryRepository) UpdateReviewState(ctx context.Context, userID, questionID string, update func(state *models.ReviewState) error) (models.ReviewState, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    state, ok := r.reviews[userID][questionID]
+    if !ok {
+        state = models.ReviewState{QuestionID: questionID}
+    }
+
+    if err := update(&state); err != nil {
+        return models.ReviewState{}, err
+    }
+
+    if r.reviews[userID] == nil {
+        r.reviews[userID] = make(map[string]models.ReviewState)
+    }
+    r.reviews[userID][questionID] = state
+    return state, nil
+}
+
+// ListDueReviews retrieves the user's schedules due at or before dueBy, earliest first.
+func (r *MemoryRepository) ListDueReviews(ctx context.Context, userID string, dueBy time.Time) ([]models.ReviewState, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    states := make([]models.ReviewState, 0)
+    for _, state := range r.reviews[userID] {
+        if !state.DueAt.After(dueBy) {
+            states = append(states, state)
+        }
+    }
+    sort.Slice(states, func(i, j int) bool {
+        if !states[i].DueAt.Equal(states[j].DueAt) {
+            return states[i].DueAt.Before(states[j].DueAt)
+        }
+        return states[i].QuestionID < states[j].QuestionID
+    })
+    return states, nil
+}
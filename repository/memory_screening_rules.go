@@ -0,0 +1,35 @@
+package repository
+
+import (
+    "context"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// GetScreeningRule retrieves the scoring rule of an age group.
+func (r *MemoryRepository) GetScreeningRule(ctx context.Context, ageGroup string) (models.ScreeningRule, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    rule, ok := r.screeningRules[ageGroup]
+    if !ok {
+        return models.ScreeningRule{}, ErrNotFound
+    }
+    return copyScreeningRule(rule), nil
+}
+
+// SaveScreeningRule replaces the scoring rule of the rule's age group.
+func (r *MemoryRepository) SaveScreeningRule(ctx context.Context, rule models.ScreeningRule) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    r.screeningRules[rule.AgeGroup] = copyScreeningRule(rule)
+    return nil
+}
+
+// copyScreeningRule returns a rule whose slices do not alias the original's.
+func copyScreeningRule(rule models.ScreeningRule) models.ScreeningRule {
+    rule.Domains = append([]models.ScreeningDomainRule(nil), rule.Domains...)
+    rule.CriticalItems = append([]string(nil), rule.CriticalItems...)
+    return rule
+}
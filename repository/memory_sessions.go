@@ -0,0 +1,75 @@
+package repository
+
+import (
+    "context"
+    "sort"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// CreateAssessmentSession stores a new assessment session and returns its generated ID.
+func (r *MemoryRepository) CreateAssessmentSession(ctx context.Context, session models.AssessmentSession) (string, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    session.ID = newID()
+    if r.assessmentSessions[session.UserID] == nil {
+        r.assessmentSessions[session.UserID] = make(map[string]models.AssessmentSession)
+    }
+    r.assessmentSessions[session.UserID][session.ID] = copySession(session)
+    return session.ID, nil
+}
+
+// GetAssessmentSession retrieves one of the user's assessment sessions.
+func (r *MemoryRepository) GetAssessmentSession(ctx context.Context, userID, sessionID string) (models.AssessmentSession, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    session, ok := r.assessmentSessions[userID][sessionID]
+    if !ok {
+        return models.AssessmentSession{}, ErrNotFound
+    }
+    return copySession(session), nil
+}
+
+// UpdateAssessmentSession applies update to an existing session while holding the write lock.
+func (r *MemoryRepository) UpdateAssessmentSession(ctx context.Context, userID, sessionID string, update func(session *models.AssessmentSession) error) (models.AssessmentSession, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    stored, ok := r.assessmentSessions[userID][sessionID]
+    if !ok {
+        return models.AssessmentSession{}, ErrNotFound
+    }
+
+    session := copySession(stored)
+    if err := update(&session); err != nil {
+        return models.AssessmentSession{}, err
+    }
+    r.assessmentSessions[userID][sessionID] = copySession(session)
+    return session, nil
+}
+
+// ListAssessmentSessions retrieves the user's sessions for a type, oldest first.
+func (r *MemoryRepository) ListAssessmentSessions(ctx context.Context, userID, questionType string) ([]models.AssessmentSession, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    sessions := make([]models.AssessmentSession, 0)
+    for _, session := range r.assessmentSessions[userID] {
+        if session.Type == questionType {
+            sessions = append(sessions, copySession(session))
+        }
+    }
+    sort.Slice(sessions, func(i, j int) bool {
+        return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+    })
+    return sessions, nil
+}
+
+// copySession returns a session whose slices do not alias the original's.
+func copySession(session models.AssessmentSession) models.AssessmentSession {
+    session.QuestionIDs = append([]string(nil), session.QuestionIDs...)
+    session.Answers = append([]models.AssessmentAnswer(nil), session.Answers...)
+    return session
+}
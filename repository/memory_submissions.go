@@ -0,0 +1,128 @@
+package repository
+
+import (
+    "context"
+    "sort"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// SaveAssessmentSubmission stores a scored assessment answer, replacing any earlier answer to the same question.
+func (r *MemoryRepository) SaveAssessmentSubmission(ctx context.Context, userID string, record models.AssessmentSubmissionRecord) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    key := userKey(userID, record.Type)
+    if r.assessmentSubmissions[key] == nil {
+        r.assessmentSubmissions[key] = make(map[string]models.AssessmentSubmissionRecord)
+    }
+    record.Timestamp = time.Now()
+    r.assessmentSubmissions[key][record.QuestionID] = record
+    return nil
+}
+
+// ListAssessmentSubmissions retrieves a user's assessment answers for a type.
+func (r *MemoryRepository) ListAssessmentSubmissions(ctx context.Context, userID, questionType string) ([]models.AssessmentSubmissionRecord, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    stored := r.assessmentSubmissions[userKey(userID, questionType)]
+    records := make([]models.AssessmentSubmissionRecord, 0, len(stored))
+    for _, record := range stored {
+        records = append(records, record)
+    }
+    sort.Slice(records, func(i, j int) bool {
+        return records[i].QuestionID < records[j].QuestionID
+    })
+    return records, nil
+}
+
+// SaveTherapySubmission stores a scored therapy answer, replacing any earlier answer to the same
+// question, and appends it to the category's answer history.
+func (r *MemoryRepository) SaveTherapySubmission(ctx context.Context, userID string, record models.TherapySubmissionRecord) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    key := userKey(userID, record.Type, record.Category)
+    if r.therapySubmissions[key] == nil {
+        r.therapySubmissions[key] = make(map[string]models.TherapySubmissionRecord)
+    }
+    record.Timestamp = time.Now()
+    r.therapySubmissions[key][record.QuestionID] = record
+    r.therapyHistory[key] = append(r.therapyHistory[key], record)
+    return nil
+}
+
+// ListTherapyAnswerHistory retrieves up to limit of a user's most recent therapy answers for a
+// type and category, oldest first.
+func (r *MemoryRepository) ListTherapyAnswerHistory(ctx context.Context, userID, questionType, category string, limit int) ([]models.TherapySubmissionRecord, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    history := r.therapyHistory[userKey(userID, questionType, category)]
+    if len(history) > limit {
+        history = history[len(history)-limit:]
+    }
+    return append([]models.TherapySubmissionRecord{}, history...), nil
+}
+
+// ListTherapySubmissions retrieves a user's therapy answers for a type and category.
+func (r *MemoryRepository) ListTherapySubmissions(ctx context.Context, userID, questionType, category string) ([]models.TherapySubmissionRecord, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    stored := r.therapySubmissions[userKey(userID, questionType, category)]
+    records := make([]models.TherapySubmissionRecord, 0, len(stored))
+    for _, record := range stored {
+        records = append(records, record)
+    }
+    sort.Slice(records, func(i, j int) bool {
+        return records[i].QuestionID < records[j].QuestionID
+    })
+    return records, nil
+}
+
+// SaveScreeningResult stores a new screening result for the user.
+func (r *MemoryRepository) SaveScreeningResult(ctx context.Context, userID string, result models.ScreeningResult) (string, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    result.ID = newID()
+    result.QuestionIDs = append([]string(nil), result.QuestionIDs...)
+    result.Answers = append([]bool(nil), result.Answers...)
+    result.Domains = append([]models.ScreeningDomainScore(nil), result.Domains...)
+    result.CriticalItems = append([]string(nil), result.CriticalItems...)
+    r.screeningResults[userID] = append(r.screeningResults[userID], result)
+    return result.ID, nil
+}
+
+// GetScreeningResult retrieves the user's latest screening result.
+func (r *MemoryRepository) GetScreeningResult(ctx context.Context, userID string) (models.ScreeningResult, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    results := r.screeningResults[userID]
+    if len(results) == 0 {
+        return models.ScreeningResult{}, ErrNotFound
+    }
+    latest := results[0]
+    for _, result := range results[1:] {
+        if !result.Timestamp.Before(latest.Timestamp) {
+            latest = result
+        }
+    }
+    return latest, nil
+}
+
+// ListScreeningResults retrieves every screening result of the user, oldest first.
+func (r *MemoryRepository) ListScreeningResults(ctx context.Context, userID string) ([]models.ScreeningResult, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    results := append([]models.ScreeningResult(nil), r.screeningResults[userID]...)
+    sort.SliceStable(results, func(i, j int) bool {
+        return results[i].Timestamp.Before(results[j].Timestamp)
+    })
+    return results, nil
+}
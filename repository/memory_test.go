@@ -0,0 +1,312 @@
+package repository
+
+import (
+    "context"
+    "errors"
+    "slices"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestMemoryTherapyQuestions(t *testing.T) {
+    r := NewMemoryRepository()
+    ctx := context.Background()
+    letters := models.TherapyQuestion{Type: "visual", Category: "letters", Description: "Letters", CorrectAnswer: "b"}
+    words := models.TherapyQuestion{Type: "visual", Category: "words", Description: "Words", CorrectAnswer: "bed"}
+    letterID, err := r.CreateTherapyQuestion(ctx, letters)
+    if err != nil {
+        t.Fatalf("CreateTherapyQuestion() error = %v", err)
+    }
+    if _, err := r.CreateTherapyQuestion(ctx, words); err != nil {
+        t.Fatalf("CreateTherapyQuestion() error = %v", err)
+    }
+
+    got, err := r.GetTherapyQuestion(ctx, "visual", "letters", letterID)
+    if err != nil || got.ID != letterID || got.CorrectAnswer != "b" {
+        t.Fatalf("GetTherapyQuestion() = %+v, %v, want question %s", got, err, letterID)
+    }
+    if _, err := r.GetTherapyQuestion(ctx, "visual", "words", letterID); !errors.Is(err, ErrNotFound) {
+        t.Errorf("GetTherapyQuestion() in another category error = %v, want ErrNotFound", err)
+    }
+
+    letters.CorrectAnswer = "d"
+    if err := r.UpdateTherapyQuestion(ctx, letterID, letters); err != nil {
+        t.Fatalf("UpdateTherapyQuestion() error = %v", err)
+    }
+    questions, skipped, err := r.ListTherapyQuestions(ctx, "visual", "letters")
+    if err != nil || len(skipped) != 0 {
+        t.Fatalf("ListTherapyQuestions() error = %v, skipped %v", err, skipped)
+    }
+    if len(questions) != 1 || questions[0].CorrectAnswer != "d" {
+        t.Errorf("ListTherapyQuestions() = %+v, want the updated letters question", questions)
+    }
+
+    categories, err := r.ListTherapyCategories(ctx, "visual")
+    if err != nil {
+        t.Fatalf("ListTherapyCategories() error = %v", err)
+    }
+    want := []models.TherapyCategory{{Category: "letters", Description: "Letters"}, {Category: "words", Description: "Words"}}
+    if !slices.Equal(categories, want) {
+        t.Errorf("ListTherapyCategories() = %+v, want %+v", categories, want)
+    }
+
+    if err := r.DeleteTherapyQuestion(ctx, "visual", "letters", letterID); err != nil {
+        t.Fatalf("DeleteTherapyQuestion() error = %v", err)
+    }
+    if _, err := r.GetTherapyQuestion(ctx, "visual", "letters", letterID); !errors.Is(err, ErrNotFound) {
+        t.Errorf("GetTherapyQuestion() after delete error = %v, want ErrNotFound", err)
+    }
+}
+
+func TestMemoryQuestionIndex(t *testing.T) {
+    r := NewMemoryRepository()
+    ctx := context.Background()
+    assessmentID, _ := r.CreateAssessmentQuestion(ctx, models.AssessmentQuestion{Type: "auditory", Category: "phoneme"})
+    therapyID, _ := r.CreateTherapyQuestion(ctx, models.TherapyQuestion{Type: "visual", Category: "letters"})
+
+    if _, err := r.GetQuestionLocation(ctx, therapyID); !errors.Is(err, ErrNotFound) {
+        t.Fatalf("GetQuestionLocation() before indexing error = %v, want ErrNotFound", err)
+    }
+    if err := r.SetQuestionLocation(ctx, models.QuestionLocation{QuestionID: "stale", Kind: models.QuestionKindTherapy}); err != nil {
+        t.Fatalf("SetQuestionLocation() error = %v", err)
+    }
+
+    rebuild, err := r.RebuildQuestionIndex(ctx)
+    if err != nil {
+        t.Fatalf("RebuildQuestionIndex() error = %v", err)
+    }
+    if rebuild.IndexedQuestions != 2 || len(rebuild.SkippedQuestionIDs) != 0 {
+        t.Errorf("RebuildQuestionIndex() = %+v, want 2 questions indexed and none skipped", rebuild)
+    }
+    location, err := r.GetQuestionLocation(ctx, assessmentID)
+    if err != nil || location.Kind != models.QuestionKindAssessment || location.Type != "auditory" || location.Category != "phoneme" {
+        t.Errorf("GetQuestionLocation() = %+v, %v, want the auditory phoneme assessment question", location, err)
+    }
+    if _, err := r.GetQuestionLocation(ctx, "stale"); !errors.Is(err, ErrNotFound) {
+        t.Errorf("GetQuestionLocation() of a stale entry after rebuild error = %v, want ErrNotFound", err)
+    }
+
+    if err := r.DeleteQuestionLocation(ctx, therapyID); err != nil {
+        t.Fatalf("DeleteQuestionLocation() error = %v", err)
+    }
+    if _, err := r.GetQuestionLocation(ctx, therapyID); !errors.Is(err, ErrNotFound) {
+        t.Errorf("GetQuestionLocation() after delete error = %v, want ErrNotFound", err)
+    }
+}
+
+func TestMemoryUpdateDailyProgress(t *testing.T) {
+    r := NewMemoryRepository()
+    ctx := context.Background()
+    date := time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+
+    var wg sync.WaitGroup
+    for i := 0; i < 50; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            _, _, err := r.UpdateDailyProgress(ctx, "user", date, func(progress *models.DailyProgress, streak *models.StreakSummary) error {
+                progress.TherapyCount++
+                streak.CurrentStreak++
+                return nil
+            })
+            if err != nil {
+                t.Errorf("UpdateDailyProgress() error = %v", err)
+            }
+        }()
+    }
+    wg.Wait()
+
+    progress, err := r.ListDailyProgress(ctx, "user", date, date)
+    if err != nil {
+        t.Fatalf("ListDailyProgress() error = %v", err)
+    }
+    if len(progress) != 1 || progress[0].TherapyCount != 50 {
+        t.Fatalf("ListDailyProgress() = %+v, want one day with 50 sessions", progress)
+    }
+    streak, err := r.GetStreakSummary(ctx, "user")
+    if err != nil || streak.CurrentStreak != 50 {
+        t.Fatalf("GetStreakSummary() = %+v, %v, want a streak of 50", streak, err)
+    }
+
+    // A failed update leaves the stored documents unchanged.
+    failure := errors.New("update failed")
+    _, _, err = r.UpdateDailyProgress(ctx, "user", date, func(progress *models.DailyProgress, streak *models.StreakSummary) error {
+        progress.TherapyCount = 0
+        streak.CurrentStreak = 0
+        return failure
+    })
+    if !errors.Is(err, failure) {
+        t.Fatalf("UpdateDailyProgress() error = %v, want %v", err, failure)
+    }
+    if progress, _ := r.ListDailyProgress(ctx, "user", date, date); progress[0].TherapyCount != 50 {
+        t.Errorf("therapy count after a failed update = %d, want 50", progress[0].TherapyCount)
+    }
+
+    // Returned documents do not alias the stored breakdowns.
+    updated, _, err := r.UpdateDailyProgress(ctx, "user", date, func(progress *models.DailyProgress, streak *models.StreakSummary) error {
+        progress.Types = append(progress.Types, models.ActivityBreakdown{Type: "visual", Sessions: 1})
+        return nil
+    })
+    if err != nil {
+        t.Fatalf("UpdateDailyProgress() error = %v", err)
+    }
+    updated.Types[0].Sessions = 99
+    if progress, _ := r.ListDailyProgress(ctx, "user", date, date); progress[0].Types[0].Sessions != 1 {
+        t.Errorf("stored sessions = %d after changing the returned copy, want 1", progress[0].Types[0].Sessions)
+    }
+
+    empty, err := r.GetStreakSummary(ctx, "other")
+    if err != nil || empty.UserID != "other" || empty.CurrentStreak != 0 {
+        t.Errorf("GetStreakSummary() for a new user = %+v, %v, want a zero summary", empty, err)
+    }
+}
+
+func TestMemoryListDueReviews(t *testing.T) {
+    r := NewMemoryRepository()
+    ctx := context.Background()
+    today := time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+    due := map[string]time.Time{
+        "c": today,
+        "a": today,
+        "b": today.AddDate(0, 0, -2),
+        "d": today.AddDate(0, 0, 1),
+    }
+    for questionID, dueAt := range due {
+        _, err := r.UpdateReviewState(ctx, "user", questionID, func(state *models.ReviewState) error {
+            if state.QuestionID != questionID {
+                t.Errorf("new review state has question %q, want %q", state.QuestionID, questionID)
+            }
+            state.DueAt = dueAt
+            return nil
+        })
+        if err != nil {
+            t.Fatalf("UpdateReviewState() error = %v", err)
+        }
+    }
+
+    states, err := r.ListDueReviews(ctx, "user", today)
+    if err != nil {
+        t.Fatalf("ListDueReviews() error = %v", err)
+    }
+    var got []string
+    for _, state := range states {
+        got = append(got, state.QuestionID)
+    }
+    want := []string{"b", "a", "c"}
+    if !slices.Equal(got, want) {
+        t.Errorf("ListDueReviews() = %v, want %v", got, want)
+    }
+}
+
+func TestMemoryCreateOnce(t *testing.T) {
+    r := NewMemoryRepository()
+    ctx := context.Background()
+
+    attempt := models.AssessmentAttempt{ID: "session", UserID: "user", Type: "visual", Answers: []models.AssessmentAnswer{{QuestionID: "q"}}}
+    if _, err := r.CreateAssessmentAttempt(ctx, attempt); err != nil {
+        t.Fatalf("CreateAssessmentAttempt() error = %v", err)
+    }
+    if _, err := r.CreateAssessmentAttempt(ctx, attempt); !errors.Is(err, ErrAlreadyExists) {
+        t.Errorf("CreateAssessmentAttempt() again error = %v, want ErrAlreadyExists", err)
+    }
+    attempt.Answers[0].QuestionID = "changed"
+    stored, err := r.GetAssessmentAttempt(ctx, "user", "session")
+    if err != nil || stored.Answers[0].QuestionID != "q" {
+        t.Errorf("GetAssessmentAttempt() = %+v, %v, want the attempt as it was created", stored, err)
+    }
+    if attempts, _ := r.ListAssessmentAttempts(ctx, "user", "auditory"); len(attempts) != 0 {
+        t.Errorf("ListAssessmentAttempts() for another type = %+v, want none", attempts)
+    }
+
+    achievement := models.Achievement{BadgeID: "first_assessment"}
+    if err := r.CreateAchievement(ctx, "user", achievement); err != nil {
+        t.Fatalf("CreateAchievement() error = %v", err)
+    }
+    if err := r.CreateAchievement(ctx, "user", achievement); !errors.Is(err, ErrAlreadyExists) {
+        t.Errorf("CreateAchievement() again error = %v, want ErrAlreadyExists", err)
+    }
+    if err := r.CreateAchievement(ctx, "other", achievement); err != nil {
+        t.Errorf("CreateAchievement() for another user error = %v", err)
+    }
+}
+
+func TestMemoryUpdateAssessmentSession(t *testing.T) {
+    r := NewMemoryRepository()
+    ctx := context.Background()
+    if _, err := r.UpdateAssessmentSession(ctx, "user", "missing", func(*models.AssessmentSession) error { return nil }); !errors.Is(err, ErrNotFound) {
+        t.Fatalf("UpdateAssessmentSession() of a missing session error = %v, want ErrNotFound", err)
+    }
+
+    id, err := r.CreateAssessmentSession(ctx, models.AssessmentSession{UserID: "user", Type: "visual", Status: models.SessionStatusInProgress})
+    if err != nil {
+        t.Fatalf("CreateAssessmentSession() error = %v", err)
+    }
+    failure := errors.New("update failed")
+    _, err = r.UpdateAssessmentSession(ctx, "user", id, func(session *models.AssessmentSession) error {
+        session.Status = models.SessionStatusFinished
+        session.Answers = append(session.Answers, models.AssessmentAnswer{QuestionID: "q"})
+        return failure
+    })
+    if !errors.Is(err, failure) {
+        t.Fatalf("UpdateAssessmentSession() error = %v, want %v", err, failure)
+    }
+    session, err := r.GetAssessmentSession(ctx, "user", id)
+    if err != nil || session.Status != models.SessionStatusInProgress || len(session.Answers) != 0 {
+        t.Errorf("session after a failed update = %+v, %v, want it unchanged", session, err)
+    }
+}
+
+func TestMemoryTokenRevocations(t *testing.T) {
+    r := NewMemoryRepository()
+    ctx := context.Background()
+    now := time.Now()
+    err := r.RevokeTokens(ctx, []models.TokenRevocation{
+        {ID: "expired", ExpiresAt: now.Add(-time.Minute)},
+        {ID: "active", ExpiresAt: now.Add(time.Hour)},
+    })
+    if err != nil {
+        t.Fatalf("RevokeTokens() error = %v", err)
+    }
+    if _, err := r.GetTokenRevocation(ctx, "active"); err != nil {
+        t.Errorf("GetTokenRevocation(active) error = %v", err)
+    }
+
+    // Expired entries are dropped on the next write.
+    if err := r.RevokeTokens(ctx, []models.TokenRevocation{{ID: "other", ExpiresAt: now.Add(time.Hour)}}); err != nil {
+        t.Fatalf("RevokeTokens() error = %v", err)
+    }
+    if _, err := r.GetTokenRevocation(ctx, "expired"); !errors.Is(err, ErrNotFound) {
+        t.Errorf("GetTokenRevocation(expired) error = %v, want ErrNotFound", err)
+    }
+}
+
+func TestMemoryTherapyAnswerHistory(t *testing.T) {
+    r := NewMemoryRepository()
+    ctx := context.Background()
+    for i, correct := range []int{0, 1, 1, 1} {
+        record := models.TherapySubmissionRecord{QuestionID: "q1", Type: "visual", Category: "letters", CorrectAnswers: correct, Score: float64(i)}
+        if err := r.SaveTherapySubmission(ctx, "user", record); err != nil {
+            t.Fatalf("SaveTherapySubmission() error = %v", err)
+        }
+    }
+
+    // The latest answer replaces earlier ones, while the history keeps every answer.
+    latest, err := r.ListTherapySubmissions(ctx, "user", "visual", "letters")
+    if err != nil || len(latest) != 1 || latest[0].Score != 3 {
+        t.Fatalf("ListTherapySubmissions() = %+v, %v, want only the latest answer", latest, err)
+    }
+    history, err := r.ListTherapyAnswerHistory(ctx, "user", "visual", "letters", 3)
+    if err != nil {
+        t.Fatalf("ListTherapyAnswerHistory() error = %v", err)
+    }
+    var scores []float64
+    for _, record := range history {
+        scores = append(scores, record.Score)
+    }
+    if want := []float64{1, 2, 3}; !slices.Equal(scores, want) {
+        t.Errorf("history scores = %v, want the 3 most recent %v", scores, want)
+    }
+}
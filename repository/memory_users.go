@@ -0,0 +1,112 @@
+package repository
+
+import (
+    "context"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// GetUser retrieves a user.
+func (r *MemoryRepository) GetUser(ctx context.Context, userID string) (models.User, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    user, ok := r.users[userID]
+    if !ok {
+        return models.User{}, ErrNotFound
+    }
+    return user, nil
+}
+
+// SaveUser merges the user's account fields, keeping the stored admin flag, roles, therapists,
+// learning profile, settings, daily goal and creation time.
+func (r *MemoryRepository) SaveUser(ctx context.Context, user models.User) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if existing, ok := r.users[user.ID]; ok {
+        user.IsAdmin = existing.IsAdmin
+        user.Roles = existing.Roles
+        user.TherapistIDs = existing.TherapistIDs
+        user.LearningProfile = existing.LearningProfile
+        user.Settings = existing.Settings
+        user.DailyGoal = existing.DailyGoal
+        if user.CreatedAt.IsZero() {
+            user.CreatedAt = existing.CreatedAt
+        }
+    }
+    r.users[user.ID] = user
+    return nil
+}
+
+// SaveLearningProfile replaces the learning-style profile stored on the user, creating the user if needed.
+func (r *MemoryRepository) SaveLearningProfile(ctx context.Context, userID string, profile models.LearningProfile) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    user := r.users[userID]
+    user.ID = userID
+    user.LearningProfile = &profile
+    r.users[userID] = user
+    return nil
+}
+
+// SaveUserSettings replaces the settings stored on the user, creating the user if needed.
+func (r *MemoryRepository) SaveUserSettings(ctx context.Context, userID string, settings models.UserSettings) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    user := r.users[userID]
+    user.ID = userID
+    user.Settings = settings
+    r.users[userID] = user
+    return nil
+}
+
+// SaveDailyGoal replaces the daily therapy goal stored on the user, creating the user if needed.
+func (r *MemoryRepository) SaveDailyGoal(ctx context.Context, userID string, goal models.DailyGoal) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    user := r.users[userID]
+    user.ID = userID
+    user.DailyGoal = &goal
+    r.users[userID] = user
+    return nil
+}
+
+// SaveUserRoles replaces the roles stored on the user, creating the user if needed.
+func (r *MemoryRepository) SaveUserRoles(ctx context.Context, userID string, roles []string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    user := r.users[userID]
+    user.ID = userID
+    user.Roles = append([]string(nil), roles...)
+    r.users[userID] = user
+    return nil
+}
+
+// SaveUserTherapists replaces the therapists assigned to the user, creating the user if needed.
+func (r *MemoryRepository) SaveUserTherapists(ctx context.Context, userID string, therapistIDs []string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    user := r.users[userID]
+    user.ID = userID
+    user.TherapistIDs = append([]string(nil), therapistIDs...)
+    r.users[userID] = user
+    return nil
+}
+
+// SetAdmin grants or revokes admin privileges for a user, creating the user if needed.
+// Firestore deployments manage the isAdmin flag directly on the user document instead.
+func (r *MemoryRepository) SetAdmin(userID string, isAdmin bool) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    user := r.users[userID]
+    user.ID = userID
+    user.IsAdmin = isAdmin
+    r.users[userID] = user
+}
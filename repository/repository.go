@@ -0,0 +1,189 @@
+package repository
+
+import (
+    "context"
+    "errors"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// ErrNotFound is returned when a requested document does not exist.
+var ErrNotFound = errors.New("document not found")
+
+// ErrAlreadyExists is returned when creating a document whose ID is already taken.
+var ErrAlreadyExists = errors.New("document already exists")
+
+// QuestionRepository stores screening, assessment and therapy questions. List calls skip stored
+// documents that cannot be decoded and return their IDs alongside the questions.
+type QuestionRepository interface {
+    ListScreeningQuestions(ctx context.Context, ageGroup string) ([]models.ScreeningQuestion, []string, error)
+    GetScreeningQuestion(ctx context.Context, ageGroup, questionID string) (models.ScreeningQuestion, error)
+    CreateScreeningQuestion(ctx context.Context, question models.ScreeningQuestion) (string, error)
+    UpdateScreeningQuestion(ctx context.Context, questionID string, question models.ScreeningQuestion) error
+    DeleteScreeningQuestion(ctx context.Context, ageGroup, questionID string) error
+
+    ListAssessmentQuestions(ctx context.Context, questionType string) ([]models.AssessmentQuestion, []string, error)
+    GetAssessmentQuestion(ctx context.Context, questionType, category, questionID string) (models.AssessmentQuestion, error)
+    CreateAssessmentQuestion(ctx context.Context, question models.AssessmentQuestion) (string, error)
+    UpdateAssessmentQuestion(ctx context.Context, questionID string, question models.AssessmentQuestion) error
+    DeleteAssessmentQuestion(ctx context.Context, questionType, category, questionID string) error
+
+    ListTherapyCategories(ctx context.Context, questionType string) ([]models.TherapyCategory, error)
+    ListTherapyQuestions(ctx context.Context, questionType, category string) ([]models.TherapyQuestion, []string, error)
+    GetTherapyQuestion(ctx context.Context, questionType, category, questionID string) (models.TherapyQuestion, error)
+    CreateTherapyQuestion(ctx context.Context, question models.TherapyQuestion) (string, error)
+    UpdateTherapyQuestion(ctx context.Context, questionID string, question models.TherapyQuestion) error
+    DeleteTherapyQuestion(ctx context.Context, questionType, category, questionID string) error
+}
+
+// ScreeningRuleRepository stores the scoring rule of each screening age group.
+type ScreeningRuleRepository interface {
+    // GetScreeningRule returns ErrNotFound when no rule has been stored for the age group.
+    GetScreeningRule(ctx context.Context, ageGroup string) (models.ScreeningRule, error)
+    SaveScreeningRule(ctx context.Context, rule models.ScreeningRule) error
+}
+
+// QuestionIndexRepository maps question IDs to the type and category they are stored under.
+type QuestionIndexRepository interface {
+    GetQuestionLocation(ctx context.Context, questionID string) (models.QuestionLocation, error)
+    SetQuestionLocation(ctx context.Context, location models.QuestionLocation) error
+    DeleteQuestionLocation(ctx context.Context, questionID string) error
+    // RebuildQuestionIndex replaces the index with the locations of every stored assessment
+    // and therapy question, leaving out documents that cannot be decoded.
+    RebuildQuestionIndex(ctx context.Context) (models.QuestionIndexRebuild, error)
+}
+
+// SubmissionRepository stores users' scored answers and screening results.
+type SubmissionRepository interface {
+    SaveAssessmentSubmission(ctx context.Context, userID string, record models.AssessmentSubmissionRecord) error
+    ListAssessmentSubmissions(ctx context.Context, userID, questionType string) ([]models.AssessmentSubmissionRecord, error)
+    // SaveTherapySubmission stores the latest answer to a question and appends it to the user's
+    // answer history of the question's type and category.
+    SaveTherapySubmission(ctx context.Context, userID string, record models.TherapySubmissionRecord) error
+    ListTherapySubmissions(ctx context.Context, userID, questionType, category string) ([]models.TherapySubmissionRecord, error)
+    // ListTherapyAnswerHistory retrieves up to limit of the user's most recent therapy answers for
+    // a type and category, oldest first. Repeated answers to a question are all kept.
+    ListTherapyAnswerHistory(ctx context.Context, userID, questionType, category string, limit int) ([]models.TherapySubmissionRecord, error)
+    // SaveScreeningResult stores a new dated screening result and returns its ID.
+    SaveScreeningResult(ctx context.Context, userID string, result models.ScreeningResult) (string, error)
+    // GetScreeningResult returns the user's latest screening result, or ErrNotFound if the user
+    // has not completed a screening.
+    GetScreeningResult(ctx context.Context, userID string) (models.ScreeningResult, error)
+    // ListScreeningResults retrieves every screening result of the user, oldest first.
+    ListScreeningResults(ctx context.Context, userID string) ([]models.ScreeningResult, error)
+}
+
+// SessionRepository stores users' assessment sessions.
+type SessionRepository interface {
+    CreateAssessmentSession(ctx context.Context, session models.AssessmentSession) (string, error)
+    GetAssessmentSession(ctx context.Context, userID, sessionID string) (models.AssessmentSession, error)
+    // UpdateAssessmentSession atomically applies update to an existing session.
+    // It returns ErrNotFound when the session does not exist.
+    UpdateAssessmentSession(ctx context.Context, userID, sessionID string, update func(session *models.AssessmentSession) error) (models.AssessmentSession, error)
+    // ListAssessmentSessions retrieves the user's sessions for a type, oldest first.
+    ListAssessmentSessions(ctx context.Context, userID, questionType string) ([]models.AssessmentSession, error)
+}
+
+// AttemptRepository stores users' immutable assessment attempts.
+type AttemptRepository interface {
+    // CreateAssessmentAttempt stores a new attempt and returns its ID. When attempt.ID is set it is
+    // used as the document ID, and ErrAlreadyExists is returned if that attempt already exists.
+    CreateAssessmentAttempt(ctx context.Context, attempt models.AssessmentAttempt) (string, error)
+    GetAssessmentAttempt(ctx context.Context, userID, attemptID string) (models.AssessmentAttempt, error)
+    // ListAssessmentAttempts retrieves the user's attempts for a type, or for every type when
+    // questionType is empty, oldest first.
+    ListAssessmentAttempts(ctx context.Context, userID, questionType string) ([]models.AssessmentAttempt, error)
+}
+
+// ProgressRepository stores users' daily therapy progress.
+type ProgressRepository interface {
+    // UpdateDailyProgress atomically applies update to the progress document for date and the
+    // user's streak summary. Zero-valued documents for the user are passed when none exist yet.
+    UpdateDailyProgress(ctx context.Context, userID string, date time.Time, update func(progress *models.DailyProgress, streak *models.StreakSummary) error) (models.DailyProgress, models.StreakSummary, error)
+    // GetStreakSummary returns a zero-valued summary for the user when none exists yet.
+    GetStreakSummary(ctx context.Context, userID string) (models.StreakSummary, error)
+    ListDailyProgress(ctx context.Context, userID string, startDate, endDate time.Time) ([]models.DailyProgress, error)
+}
+
+// ReviewRepository stores users' spaced-repetition schedules for therapy questions.
+type ReviewRepository interface {
+    // UpdateReviewState atomically applies update to the user's schedule for a question.
+    // A zero-valued ReviewState for the question is passed when none exists yet.
+    UpdateReviewState(ctx context.Context, userID, questionID string, update func(state *models.ReviewState) error) (models.ReviewState, error)
+    // ListDueReviews returns the user's schedules due at or before dueBy, earliest first.
+    ListDueReviews(ctx context.Context, userID string, dueBy time.Time) ([]models.ReviewState, error)
+}
+
+// AchievementRepository stores the badges users have unlocked.
+type AchievementRepository interface {
+    // CreateAchievement stores a newly unlocked badge, returning ErrAlreadyExists when the user
+    // has already unlocked it.
+    CreateAchievement(ctx context.Context, userID string, achievement models.Achievement) error
+    // ListAchievements retrieves the user's unlocked badges, oldest first.
+    ListAchievements(ctx context.Context, userID string) ([]models.Achievement, error)
+}
+
+// AuthSessionRepository stores the signed-in devices of each user.
+type AuthSessionRepository interface {
+    CreateAuthSession(ctx context.Context, session models.AuthSession) (string, error)
+    GetAuthSession(ctx context.Context, userID, sessionID string) (models.AuthSession, error)
+    // UpdateAuthSession atomically applies update to an existing session.
+    // It returns ErrNotFound when the session does not exist.
+    UpdateAuthSession(ctx context.Context, userID, sessionID string, update func(session *models.AuthSession) error) (models.AuthSession, error)
+    // ListAuthSessions retrieves every session of the user, including revoked ones, oldest first.
+    ListAuthSessions(ctx context.Context, userID string) ([]models.AuthSession, error)
+}
+
+// ChildProfileRepository stores the child profiles of each guardian account.
+type ChildProfileRepository interface {
+    CreateChildProfile(ctx context.Context, profile models.ChildProfile) (string, error)
+    // GetChildProfile returns ErrNotFound when the profile is not one of the guardian's children.
+    GetChildProfile(ctx context.Context, guardianID, profileID string) (models.ChildProfile, error)
+    // UpdateChildProfile atomically applies update to an existing profile.
+    // It returns ErrNotFound when the profile does not exist.
+    UpdateChildProfile(ctx context.Context, guardianID, profileID string, update func(profile *models.ChildProfile) error) (models.ChildProfile, error)
+    // ListChildProfiles retrieves every profile of the guardian, oldest first.
+    ListChildProfiles(ctx context.Context, guardianID string) ([]models.ChildProfile, error)
+}
+
+// TokenRevocationRepository stores the identifiers of revoked access tokens and sessions.
+type TokenRevocationRepository interface {
+    // RevokeTokens adds entries to the revocation list, replacing entries with the same ID.
+    RevokeTokens(ctx context.Context, revocations []models.TokenRevocation) error
+    // GetTokenRevocation returns ErrNotFound when the identifier has not been revoked.
+    GetTokenRevocation(ctx context.Context, id string) (models.TokenRevocation, error)
+}
+
+// UserRepository stores user accounts.
+type UserRepository interface {
+    GetUser(ctx context.Context, userID string) (models.User, error)
+    SaveUser(ctx context.Context, user models.User) error
+    // SaveLearningProfile replaces the learning-style profile stored on the user.
+    SaveLearningProfile(ctx context.Context, userID string, profile models.LearningProfile) error
+    // SaveUserSettings replaces the settings stored on the user.
+    SaveUserSettings(ctx context.Context, userID string, settings models.UserSettings) error
+    // SaveDailyGoal replaces the daily therapy goal stored on the user.
+    SaveDailyGoal(ctx context.Context, userID string, goal models.DailyGoal) error
+    // SaveUserRoles replaces the roles stored on the user.
+    SaveUserRoles(ctx context.Context, userID string, roles []string) error
+    // SaveUserTherapists replaces the therapists assigned to the user.
+    SaveUserTherapists(ctx context.Context, userID string, therapistIDs []string) error
+}
+
+// Repository groups every storage concern used by the services package.
+type Repository interface {
+    QuestionRepository
+    ScreeningRuleRepository
+    QuestionIndexRepository
+    SubmissionRepository
+    SessionRepository
+    AttemptRepository
+    ProgressRepository
+    ReviewRepository
+    AchievementRepository
+    AuthSessionRepository
+    ChildProfileRepository
+    TokenRevocationRepository
+    UserRepository
+}
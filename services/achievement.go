@@ -0,0 +1,298 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// Metrics a badge rule can measure.
+const (
+    badgeMetricAssessmentAttempts = "assessmentAttempts"
+    badgeMetricLongestStreak      = "longestStreak"
+    badgeMetricCorrectAnswers     = "correctAnswers"
+    badgeMetricPerfectCategories  = "perfectCategories"
+)
+
+// badgeRule declares a badge that unlocks once Metric, restricted to Type when set, reaches Target.
+type badgeRule struct {
+    ID          string
+    Name        string
+    Description string
+    Metric      string
+    Type        string
+    Target      int
+}
+
+// badgeRules lists every badge users can unlock, in display order.
+var badgeRules = []badgeRule{
+    {
+        ID:          "first_assessment",
+        Name:        "First Steps",
+        Description: "Complete your first assessment",
+        Metric:      badgeMetricAssessmentAttempts,
+        Target:      1,
+    },
+    {
+        ID:          "streak_7",
+        Name:        "Week Warrior",
+        Description: "Reach your daily goal 7 days in a row",
+        Metric:      badgeMetricLongestStreak,
+        Target:      7,
+    },
+    {
+        ID:          "auditory_correct_100",
+        Name:        "Sharp Ears",
+        Description: "Answer 100 auditory therapy questions correctly",
+        Metric:      badgeMetricCorrectAnswers,
+        Type:        "auditory",
+        Target:      100,
+    },
+    {
+        ID:          "perfect_category",
+        Name:        "Perfect Score",
+        Description: "Answer every question of a category correctly in one assessment or therapy session",
+        Metric:      badgeMetricPerfectCategories,
+        Target:      1,
+    },
+}
+
+// achievementStats is the user activity badge rules are measured against.
+type achievementStats struct {
+    assessmentAttempts int
+    longestStreak      int
+    correctAnswers     map[string]int
+    perfectCategories  int
+}
+
+// measure returns the amount of rule's metric the user has reached.
+func (s achievementStats) measure(rule badgeRule) int {
+    switch rule.Metric {
+    case badgeMetricAssessmentAttempts:
+        return s.assessmentAttempts
+    case badgeMetricLongestStreak:
+        return s.longestStreak
+    case badgeMetricCorrectAnswers:
+        if rule.Type != "" {
+            return s.correctAnswers[rule.Type]
+        }
+        total := 0
+        for _, correct := range s.correctAnswers {
+            total += correct
+        }
+        return total
+    case badgeMetricPerfectCategories:
+        return s.perfectCategories
+    default:
+        return 0
+    }
+}
+
+// collectAchievementStats gathers the user's assessment attempts, streak and therapy totals.
+// Only complete attempts count as assessments taken; perfect categories are counted over every
+// assessment attempt and the therapy results of the submission being evaluated. Therapy totals
+// come from the streak summary, or from the daily progress for summaries that predate them.
+func collectAchievementStats(ctx context.Context, userID string, therapyResults []models.TherapyResult) (achievementStats, error) {
+    var stats achievementStats
+
+    attempts, err := repo.ListAssessmentAttempts(ctx, userID, "")
+    if err != nil {
+        return achievementStats{}, fmt.Errorf("failed to retrieve assessment attempts: %w", err)
+    }
+    for _, attempt := range attempts {
+        if !attempt.Partial {
+            stats.assessmentAttempts++
+        }
+    }
+    perfect, err := perfectAssessmentCategories(ctx, attempts)
+    if err != nil {
+        return achievementStats{}, err
+    }
+    stats.perfectCategories += perfect
+
+    streak, err := repo.GetStreakSummary(ctx, userID)
+    if err != nil {
+        return achievementStats{}, fmt.Errorf("failed to retrieve streak summary: %w", err)
+    }
+    stats.longestStreak = streak.LongestStreak
+    stats.correctAnswers = streak.CorrectAnswers
+    if stats.correctAnswers == nil {
+        if stats.correctAnswers, err = therapyCorrectTotals(ctx, userID); err != nil {
+            return achievementStats{}, err
+        }
+    }
+
+    perfect, err = perfectTherapyCategories(ctx, therapyResults)
+    if err != nil {
+        return achievementStats{}, err
+    }
+    stats.perfectCategories += perfect
+    return stats, nil
+}
+
+// perfectAssessmentCategories counts the categories of the attempts whose every question was
+// answered correctly. A session attempt's category totals cover every question of the session;
+// partial attempts are measured against the category's current question list.
+func perfectAssessmentCategories(ctx context.Context, attempts []models.AssessmentAttempt) (int, error) {
+    typeTotals := make(map[string]map[string]int)
+    perfect := 0
+    for _, attempt := range attempts {
+        totals := make(map[string]int, len(attempt.Categories))
+        for _, category := range attempt.Categories {
+            totals[category.Category] = category.TotalQuestions
+        }
+        if attempt.Partial {
+            if _, ok := typeTotals[attempt.Type]; !ok {
+                questions, skipped, err := repo.ListAssessmentQuestions(ctx, attempt.Type)
+                if err != nil {
+                    return 0, fmt.Errorf("failed to retrieve assessment questions: %w", err)
+                }
+                logSkippedQuestions(skipped, "assessment", "type "+attempt.Type)
+                counts := make(map[string]int)
+                for _, question := range questions {
+                    counts[question.Category]++
+                }
+                typeTotals[attempt.Type] = counts
+            }
+            totals = typeTotals[attempt.Type]
+        }
+
+        correct := make(map[string]map[string]bool)
+        for _, answer := range attempt.Answers {
+            if !answer.Correct {
+                continue
+            }
+            if correct[answer.Category] == nil {
+                correct[answer.Category] = make(map[string]bool)
+            }
+            correct[answer.Category][answer.QuestionID] = true
+        }
+        for _, category := range attempt.Categories {
+            if isPerfectCategory(len(correct[category.Category]), totals[category.Category]) {
+                perfect++
+            }
+        }
+    }
+    return perfect, nil
+}
+
+// perfectTherapyCategories counts the categories of a therapy submission in which every question
+// of the category was answered correctly.
+func perfectTherapyCategories(ctx context.Context, results []models.TherapyResult) (int, error) {
+    type categoryKey struct{ questionType, category string }
+    correct := make(map[categoryKey]map[string]bool)
+    for _, result := range results {
+        key := categoryKey{result.Type, result.Category}
+        if correct[key] == nil {
+            correct[key] = make(map[string]bool)
+        }
+        for _, answer := range result.Answers {
+            if answer.Correct {
+                correct[key][answer.QuestionID] = true
+            }
+        }
+    }
+
+    perfect := 0
+    for key, questionIDs := range correct {
+        questions, skipped, err := repo.ListTherapyQuestions(ctx, key.questionType, key.category)
+        if err != nil {
+            return 0, fmt.Errorf("failed to retrieve therapy questions: %w", err)
+        }
+        logSkippedQuestions(skipped, "therapy", "type "+key.questionType+", category "+key.category)
+        if isPerfectCategory(len(questionIDs), len(questions)) {
+            perfect++
+        }
+    }
+    return perfect, nil
+}
+
+// isPerfectCategory reports whether correct distinct answers cover a category of total questions.
+func isPerfectCategory(correct, total int) bool {
+    return total > 0 && correct >= total
+}
+
+// EvaluateAchievements checks every badge rule against the user's activity after a therapy or
+// assessment submission, stores the badges that are newly unlocked and returns them. therapyResults
+// holds the scored results of a therapy submission and is empty for assessments.
+func EvaluateAchievements(ctx context.Context, userID string, therapyResults []models.TherapyResult) ([]models.Achievement, error) {
+    unlocked, err := repo.ListAchievements(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve achievements: %w", err)
+    }
+    alreadyUnlocked := make(map[string]bool, len(unlocked))
+    for _, achievement := range unlocked {
+        alreadyUnlocked[achievement.BadgeID] = true
+    }
+
+    stats, err := collectAchievementStats(ctx, userID, therapyResults)
+    if err != nil {
+        return nil, err
+    }
+
+    now := time.Now().UTC()
+    newlyUnlocked := []models.Achievement{}
+    for _, rule := range badgeRules {
+        if alreadyUnlocked[rule.ID] || stats.measure(rule) < rule.Target {
+            continue
+        }
+        achievement := models.Achievement{
+            BadgeID:     rule.ID,
+            Name:        rule.Name,
+            Description: rule.Description,
+            UnlockedAt:  now,
+        }
+        err := repo.CreateAchievement(ctx, userID, achievement)
+        if errors.Is(err, repository.ErrAlreadyExists) {
+            continue
+        }
+        if err != nil {
+            return newlyUnlocked, fmt.Errorf("failed to store achievement %s: %w", rule.ID, err)
+        }
+        newlyUnlocked = append(newlyUnlocked, achievement)
+        log.Printf("Unlocked achievement %s for userID: %s", rule.ID, userID)
+    }
+    return newlyUnlocked, nil
+}
+
+// ListAchievements reports every badge with the user's progress toward it and when it was unlocked.
+func ListAchievements(ctx context.Context, userID string) ([]models.BadgeStatus, error) {
+    unlocked, err := repo.ListAchievements(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve achievements: %w", err)
+    }
+    unlockedAt := make(map[string]time.Time, len(unlocked))
+    for _, achievement := range unlocked {
+        unlockedAt[achievement.BadgeID] = achievement.UnlockedAt
+    }
+
+    stats, err := collectAchievementStats(ctx, userID, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    badges := make([]models.BadgeStatus, 0, len(badgeRules))
+    for _, rule := range badgeRules {
+        badge := models.BadgeStatus{
+            BadgeID:     rule.ID,
+            Name:        rule.Name,
+            Description: rule.Description,
+            Progress:    min(stats.measure(rule), rule.Target),
+            Target:      rule.Target,
+        }
+        if at, ok := unlockedAt[rule.ID]; ok {
+            badge.Unlocked = true
+            badge.UnlockedAt = &at
+            badge.Progress = rule.Target
+        }
+        badges = append(badges, badge)
+    }
+
+    log.Printf("Retrieved %d achievements for userID: %s, unlocked: %d", len(badges), userID, len(unlocked))
+    return badges, nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+    "context"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestEvaluateAchievementsPerfectTherapyCategory(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    first := saveTherapyQuestion(t, "visual", "letter_recognition", "b")
+    second := saveTherapyQuestion(t, "visual", "letter_recognition", "d")
+
+    // One correct answer, even repeated, does not cover a category of two questions.
+    results := submitTherapyAnswers(t, "user", first, first)
+    unlocked, err := EvaluateAchievements(ctx, "user", results)
+    if err != nil {
+        t.Fatalf("EvaluateAchievements() error = %v", err)
+    }
+    if got := badgeIDs(unlocked); slices.Contains(got, "perfect_category") {
+        t.Fatalf("unlocked %v after one of two questions, want no perfect_category", got)
+    }
+
+    results = submitTherapyAnswers(t, "user", first, second)
+    unlocked, err = EvaluateAchievements(ctx, "user", results)
+    if err != nil {
+        t.Fatalf("EvaluateAchievements() error = %v", err)
+    }
+    if got := badgeIDs(unlocked); !slices.Equal(got, []string{"perfect_category"}) {
+        t.Fatalf("unlocked %v after every question, want [perfect_category]", got)
+    }
+
+    unlocked, err = EvaluateAchievements(ctx, "user", results)
+    if err != nil {
+        t.Fatalf("EvaluateAchievements() error = %v", err)
+    }
+    if len(unlocked) != 0 {
+        t.Errorf("unlocked %v again, want no new badges", badgeIDs(unlocked))
+    }
+}
+
+func TestEvaluateAchievementsAssessmentSession(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    first := saveAssessmentQuestion(t, "visual", "letter_recognition", "b")
+    saveAssessmentQuestion(t, "visual", "letter_recognition", "d")
+
+    session, _, err := StartAssessmentSession(ctx, "user", "visual")
+    if err != nil {
+        t.Fatalf("StartAssessmentSession() error = %v", err)
+    }
+    _, _, err = SubmitAssessmentSessionAnswers(ctx, "user", session.ID, []models.AssessmentSubmission{{QuestionID: first.ID, Answer: "b"}})
+    if err != nil {
+        t.Fatalf("SubmitAssessmentSessionAnswers() error = %v", err)
+    }
+    if _, err := FinishAssessmentSession(ctx, "user", session.ID); err != nil {
+        t.Fatalf("FinishAssessmentSession() error = %v", err)
+    }
+
+    // The unanswered question keeps the category from being perfect.
+    unlocked, err := EvaluateAchievements(ctx, "user", nil)
+    if err != nil {
+        t.Fatalf("EvaluateAchievements() error = %v", err)
+    }
+    if got := badgeIDs(unlocked); !slices.Equal(got, []string{"first_assessment"}) {
+        t.Errorf("unlocked %v, want [first_assessment]", got)
+    }
+}
+
+func TestEvaluateAchievementsAssessmentSubmission(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    first := saveAssessmentQuestion(t, "auditory", "phoneme", "b")
+    second := saveAssessmentQuestion(t, "auditory", "phoneme", "d")
+
+    submit := func(submissions ...models.AssessmentSubmission) []string {
+        t.Helper()
+        if _, err := SubmitAssessmentAnswers(ctx, "user", "auditory", submissions); err != nil {
+            t.Fatalf("SubmitAssessmentAnswers() error = %v", err)
+        }
+        unlocked, err := EvaluateAchievements(ctx, "user", nil)
+        if err != nil {
+            t.Fatalf("EvaluateAchievements() error = %v", err)
+        }
+        return badgeIDs(unlocked)
+    }
+
+    // Answer batches outside a session are partial attempts and do not count as an assessment.
+    if got := submit(models.AssessmentSubmission{QuestionID: first.ID, Answer: "b"}); len(got) != 0 {
+        t.Errorf("unlocked %v after one of two questions, want none", got)
+    }
+    got := submit(
+        models.AssessmentSubmission{QuestionID: first.ID, Answer: "b"},
+        models.AssessmentSubmission{QuestionID: second.ID, Answer: "d"},
+    )
+    if !slices.Equal(got, []string{"perfect_category"}) {
+        t.Errorf("unlocked %v after every question, want [perfect_category]", got)
+    }
+}
+
+func TestListAchievements(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    _, _, err := r.UpdateDailyProgress(ctx, "user", localDate(time.Now(), time.UTC), func(progress *models.DailyProgress, streak *models.StreakSummary) error {
+        streak.CurrentStreak = 3
+        streak.LongestStreak = 3
+        progress.Types = []models.ActivityBreakdown{{Type: "auditory", CorrectAnswers: 150}}
+        return nil
+    })
+    if err != nil {
+        t.Fatalf("UpdateDailyProgress() error = %v", err)
+    }
+
+    badges, err := ListAchievements(ctx, "user")
+    if err != nil {
+        t.Fatalf("ListAchievements() error = %v", err)
+    }
+    progress := make(map[string]int, len(badges))
+    for _, badge := range badges {
+        if badge.Unlocked {
+            t.Errorf("badge %s is unlocked before being evaluated", badge.BadgeID)
+        }
+        progress[badge.BadgeID] = badge.Progress
+    }
+    want := map[string]int{"first_assessment": 0, "streak_7": 3, "auditory_correct_100": 100, "perfect_category": 0}
+    for badgeID, wantProgress := range want {
+        if progress[badgeID] != wantProgress {
+            t.Errorf("badge %s progress = %d, want %d", badgeID, progress[badgeID], wantProgress)
+        }
+    }
+}
+
+// submitTherapyAnswers answers each question correctly and returns the scored results.
+func submitTherapyAnswers(t *testing.T, userID string, questions ...models.TherapyQuestion) []models.TherapyResult {
+    t.Helper()
+    results := make([]models.TherapyResult, 0, len(questions))
+    for _, question := range questions {
+        submission := models.TherapySubmission{QuestionID: question.ID, Answer: question.CorrectAnswer}
+        result, err := SaveTherapyResult(context.Background(), userID, submission, "", time.UTC)
+        if err != nil {
+            t.Fatalf("SaveTherapyResult() error = %v", err)
+        }
+        results = append(results, result)
+    }
+    return results
+}
+
+// badgeIDs returns the badge IDs of achievements in order.
+func badgeIDs(achievements []models.Achievement) []string {
+    ids := make([]string, len(achievements))
+    for i, achievement := range achievements {
+        ids[i] = achievement.BadgeID
+    }
+    return ids
+}
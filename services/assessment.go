@@ -2,90 +2,22 @@ package services
 
 import (
     "context"
+    "errors"
     "fmt"
     "log"
+    "time"
 
-    "cloud.google.com/go/firestore"
     "github.com/dzuura/neurodyx-be/models"
-    "google.golang.org/grpc/codes"
-    "google.golang.org/grpc/status"
+    "github.com/dzuura/neurodyx-be/repository"
 )
 
 // GetAssessmentQuestions retrieves assessment questions by type.
 func GetAssessmentQuestions(ctx context.Context, questionType string, userID string) ([]models.AssessmentQuestion, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
+    questions, skipped, err := repo.ListAssessmentQuestions(ctx, questionType)
     if err != nil {
-        return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    questions := make([]models.AssessmentQuestion, 0)
-
-    categoriesIter := firestoreClient.Collection("assessmentQuestions").Doc(questionType).Collections(ctx)
-    categories, err := categoriesIter.GetAll()
-    if err != nil {
-        return nil, fmt.Errorf("failed to retrieve categories for type %s: %w", questionType, err)
-    }
-
-    for _, category := range categories {
-        categoryName := category.ID
-        docs, err := category.Documents(ctx).GetAll()
-        if err != nil {
-            log.Printf("Failed to retrieve questions for type %s, category %s: %v", questionType, categoryName, err)
-            continue
-        }
-
-        for _, doc := range docs {
-            var q models.AssessmentQuestion
-            data := doc.Data()
-            q.ID = doc.Ref.ID
-            q.Type = data["type"].(string)
-            q.Category = data["category"].(string)
-            if content, ok := data["content"].(string); ok {
-                q.Content = content
-            }
-            if imageURL, ok := data["imageURL"].(string); ok {
-                q.ImageURL = imageURL
-            }
-            if soundURL, ok := data["soundURL"].(string); ok {
-                q.SoundURL = soundURL
-            }
-            if options, ok := data["options"].([]interface{}); ok {
-                q.Options = make([]string, len(options))
-                for i, opt := range options {
-                    q.Options[i] = opt.(string)
-                }
-            }
-            if leftItems, ok := data["leftItems"].([]interface{}); ok {
-                q.LeftItems = make([]string, len(leftItems))
-                for i, item := range leftItems {
-                    q.LeftItems[i] = item.(string)
-                }
-            }
-            if rightItems, ok := data["rightItems"].([]interface{}); ok {
-                q.RightItems = make([]string, len(rightItems))
-                for i, item := range rightItems {
-                    q.RightItems[i] = item.(string)
-                }
-            }
-            if correctAnswer, ok := data["correctAnswer"].(string); ok {
-                q.CorrectAnswer = correctAnswer
-            }
-            if correctSeq, ok := data["correctSequence"].([]interface{}); ok {
-                q.CorrectSequence = make([]string, len(correctSeq))
-                for i, seq := range correctSeq {
-                    q.CorrectSequence[i] = seq.(string)
-                }
-            }
-            if correctPairs, ok := data["correctPairs"].(map[string]interface{}); ok {
-                q.CorrectPairs = make(map[string]string)
-                for k, v := range correctPairs {
-                    q.CorrectPairs[k] = v.(string)
-                }
-            }
-            questions = append(questions, q)
-        }
+        return nil, err
     }
+    logSkippedQuestions(skipped, "assessment", "type "+questionType)
 
     log.Printf("Retrieved %d assessment questions for type: %s", len(questions), questionType)
     return questions, nil
@@ -93,141 +25,56 @@ func GetAssessmentQuestions(ctx context.Context, questionType string, userID str
 
 // GetAssessmentQuestionByID retrieves an assessment question by its ID, type, and category.
 func GetAssessmentQuestionByID(ctx context.Context, questionType, category, questionID string) (models.AssessmentQuestion, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return models.AssessmentQuestion{}, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    doc, err := firestoreClient.Collection("assessmentQuestions").Doc(questionType).Collection(category).Doc(questionID).Get(ctx)
+    q, err := repo.GetAssessmentQuestion(ctx, questionType, category, questionID)
     if err != nil {
         return models.AssessmentQuestion{}, fmt.Errorf("failed to retrieve question: %w", err)
     }
 
-    var q models.AssessmentQuestion
-    data := doc.Data()
-    q.ID = doc.Ref.ID
-    q.Type = data["type"].(string)
-    q.Category = data["category"].(string)
-    if content, ok := data["content"].(string); ok {
-        q.Content = content
-    }
-    if imageURL, ok := data["imageURL"].(string); ok {
-        q.ImageURL = imageURL
-    }
-    if soundURL, ok := data["soundURL"].(string); ok {
-        q.SoundURL = soundURL
-    }
-    if options, ok := data["options"].([]interface{}); ok {
-        q.Options = make([]string, len(options))
-        for i, opt := range options {
-            q.Options[i] = opt.(string)
-        }
-    }
-    if leftItems, ok := data["leftItems"].([]interface{}); ok {
-        q.LeftItems = make([]string, len(leftItems))
-        for i, item := range leftItems {
-            q.LeftItems[i] = item.(string)
-        }
-    }
-    if rightItems, ok := data["rightItems"].([]interface{}); ok {
-        q.RightItems = make([]string, len(rightItems))
-        for i, item := range rightItems {
-            q.RightItems[i] = item.(string)
-        }
-    }
-    if correctAnswer, ok := data["correctAnswer"].(string); ok {
-        q.CorrectAnswer = correctAnswer
-    }
-    if correctSeq, ok := data["correctSequence"].([]interface{}); ok {
-        q.CorrectSequence = make([]string, len(correctSeq))
-        for i, seq := range correctSeq {
-            q.CorrectSequence[i] = seq.(string)
-        }
-    }
-    if correctPairs, ok := data["correctPairs"].(map[string]interface{}); ok {
-        q.CorrectPairs = make(map[string]string)
-        for k, v := range correctPairs {
-            q.CorrectPairs[k] = v.(string)
-        }
-    }
-
     log.Printf("Retrieved assessment question with ID: %s, type: %s, category: %s", questionID, questionType, category)
     return q, nil
 }
 
-// SaveAssessmentQuestion saves a new assessment question to Firestore.
+// SaveAssessmentQuestion saves a new assessment question.
 func SaveAssessmentQuestion(ctx context.Context, question models.AssessmentQuestion, userID string) (string, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
+    format, err := checkAnswerKey(question.AnswerFormat, assessmentAnswerKey(question))
     if err != nil {
-        return "", fmt.Errorf("failed to connect to Firestore: %w", err)
+        return "", err
     }
-    defer firestoreClient.Close()
-
-    docRef, _, err := firestoreClient.Collection("assessmentQuestions").Doc(question.Type).Collection(question.Category).Add(ctx, map[string]interface{}{
-        "type":            question.Type,
-        "category":        question.Category,
-        "content":         question.Content,
-        "imageURL":        question.ImageURL,
-        "soundURL":        question.SoundURL,
-        "options":         question.Options,
-        "leftItems":       question.LeftItems,
-        "rightItems":      question.RightItems,
-        "correctAnswer":   question.CorrectAnswer,
-        "correctSequence": question.CorrectSequence,
-        "correctPairs":    question.CorrectPairs,
-        "timestamp":       firestore.ServerTimestamp,
-    })
+    question.AnswerFormat = format
+
+    questionID, err := repo.CreateAssessmentQuestion(ctx, question)
     if err != nil {
         return "", fmt.Errorf("failed to save assessment question: %w", err)
     }
+    indexQuestion(ctx, models.QuestionKindAssessment, questionID, question.Type, question.Category)
 
-    log.Printf("Saved assessment question with ID: %s, type: %s, category: %s", docRef.ID, question.Type, question.Category)
-    return docRef.ID, nil
+    log.Printf("Saved assessment question with ID: %s, type: %s, category: %s", questionID, question.Type, question.Category)
+    return questionID, nil
 }
 
-// UpdateAssessmentQuestion updates an existing assessment question in Firestore.
+// UpdateAssessmentQuestion updates an existing assessment question.
 func UpdateAssessmentQuestion(ctx context.Context, questionID string, question models.AssessmentQuestion, userID string) error {
-    firestoreClient, err := GetFirestoreClient(ctx)
+    format, err := checkAnswerKey(question.AnswerFormat, assessmentAnswerKey(question))
     if err != nil {
-        return fmt.Errorf("failed to connect to Firestore: %w", err)
+        return err
     }
-    defer firestoreClient.Close()
-
-    _, err = firestoreClient.Collection("assessmentQuestions").Doc(question.Type).Collection(question.Category).Doc(questionID).Set(ctx, map[string]interface{}{
-        "type":            question.Type,
-        "category":        question.Category,
-        "content":         question.Content,
-        "imageURL":        question.ImageURL,
-        "soundURL":        question.SoundURL,
-        "options":         question.Options,
-        "leftItems":       question.LeftItems,
-        "rightItems":      question.RightItems,
-        "correctAnswer":   question.CorrectAnswer,
-        "correctSequence": question.CorrectSequence,
-        "correctPairs":    question.CorrectPairs,
-        "timestamp":       firestore.ServerTimestamp,
-    }, firestore.MergeAll)
-    if err != nil {
+    question.AnswerFormat = format
+
+    if err := repo.UpdateAssessmentQuestion(ctx, questionID, question); err != nil {
         return fmt.Errorf("failed to update assessment question: %w", err)
     }
+    indexQuestion(ctx, models.QuestionKindAssessment, questionID, question.Type, question.Category)
 
     log.Printf("Updated assessment question with ID: %s, type: %s, category: %s", questionID, question.Type, question.Category)
     return nil
 }
 
-// DeleteAssessmentQuestion deletes an assessment question from Firestore.
+// DeleteAssessmentQuestion deletes an assessment question.
 func DeleteAssessmentQuestion(ctx context.Context, questionID, questionType, category, userID string) error {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    _, err = firestoreClient.Collection("assessmentQuestions").Doc(questionType).Collection(category).Doc(questionID).Delete(ctx)
-    if err != nil {
+    if err := repo.DeleteAssessmentQuestion(ctx, questionType, category, questionID); err != nil {
         return fmt.Errorf("failed to delete assessment question: %w", err)
     }
+    unindexQuestion(ctx, questionID)
 
     log.Printf("Deleted assessment question with ID: %s, type: %s, category: %s", questionID, questionType, category)
     return nil
@@ -235,159 +82,125 @@ func DeleteAssessmentQuestion(ctx context.Context, questionID, questionType, cat
 
 // SaveAssessmentResult saves the user's assessment result with flexible answer validation.
 func SaveAssessmentResult(ctx context.Context, userID string, submission models.AssessmentSubmission, firebaseToken string) (models.AssessmentResult, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
+    questionType, answer, err := saveAssessmentAnswer(ctx, userID, submission, time.Now().UTC())
     if err != nil {
-        return models.AssessmentResult{}, fmt.Errorf("failed to connect to Firestore: %w", err)
+        return models.AssessmentResult{}, err
     }
-    defer firestoreClient.Close()
 
-    var question models.AssessmentQuestion
-    types := []string{"visual", "auditory", "kinesthetic", "tactile"}
-    for _, t := range types {
-        categoriesIter := firestoreClient.Collection("assessmentQuestions").Doc(t).Collections(ctx)
-        categories, err := categoriesIter.GetAll()
-        if err != nil {
-            continue
-        }
-        for _, category := range categories {
-            doc, err := category.Doc(submission.QuestionID).Get(ctx)
-            if err == nil && doc.Exists() {
-                data := doc.Data()
-                question.ID = doc.Ref.ID
-                question.Type = data["type"].(string)
-                question.Category = data["category"].(string)
-                if options, ok := data["options"].([]interface{}); ok {
-                    question.Options = make([]string, len(options))
-                    for i, opt := range options {
-                        question.Options[i] = opt.(string)
-                    }
-                }
-                if leftItems, ok := data["leftItems"].([]interface{}); ok {
-                    question.LeftItems = make([]string, len(leftItems))
-                    for i, item := range leftItems {
-                        question.LeftItems[i] = item.(string)
-                    }
-                }
-                if rightItems, ok := data["rightItems"].([]interface{}); ok {
-                    question.RightItems = make([]string, len(rightItems))
-                    for i, item := range rightItems {
-                        question.RightItems[i] = item.(string)
-                    }
-                }
-                if correctAnswer, ok := data["correctAnswer"].(string); ok {
-                    question.CorrectAnswer = correctAnswer
-                }
-                if correctSeq, ok := data["correctSequence"].([]interface{}); ok {
-                    question.CorrectSequence = make([]string, len(correctSeq))
-                    for i, seq := range correctSeq {
-                        question.CorrectSequence[i] = seq.(string)
-                    }
-                }
-                if correctPairs, ok := data["correctPairs"].(map[string]interface{}); ok {
-                    question.CorrectPairs = make(map[string]string)
-                    for k, v := range correctPairs {
-                        question.CorrectPairs[k] = v.(string)
-                    }
-                }
-                break
-            }
-        }
-        if question.ID != "" {
-            break
-        }
+    result := models.AssessmentResult{
+        Type:           questionType,
+        Score:          answer.Score,
+        TotalQuestions: 1,
+        Answers:        []models.AnswerFeedback{answerFeedback(answer)},
     }
+    if answer.Correct {
+        result.CorrectAnswers = 1
+    }
+    return result, nil
+}
 
-    if question.ID == "" {
-        return models.AssessmentResult{}, fmt.Errorf("question with ID %s not found", submission.QuestionID)
+// SubmitAssessmentAnswers scores a batch of answers and records the batch as a partial assessment
+// attempt for each question type it covers. Partial attempts are kept in the attempt history but do
+// not feed the learning profile; only finished sessions do.
+func SubmitAssessmentAnswers(ctx context.Context, userID, questionType string, submissions []models.AssessmentSubmission) (models.AssessmentResult, error) {
+    now := time.Now().UTC()
+    result := models.AssessmentResult{
+        Type:           questionType,
+        TotalQuestions: len(submissions),
+        Status:         "completed",
+        Answers:        make([]models.AnswerFeedback, 0, len(submissions)),
     }
 
-    isCorrect := false
-    switch question.Category {
-    case "word_repetition", "word_recognition_by_touch", "complete_the_word_by_touch":
-        answerStr, ok := submission.Answer.(string)
-        if ok {
-            isCorrect = ValidateStringMatch(answerStr, question.CorrectAnswer)
-        }
-    case "letter_matching":
-        answerSeq, ok := submission.Answer.([]interface{})
-        if ok {
-            seq := make([]string, len(answerSeq))
-            for i, v := range answerSeq {
-                seq[i], _ = v.(string)
-            }
-            isCorrect = ValidateSequence(seq, question.CorrectSequence)
+    answersByType := make(map[string][]models.AssessmentAnswer)
+    for _, sub := range submissions {
+        answerType, answer, err := saveAssessmentAnswer(ctx, userID, sub, now)
+        if err != nil {
+            log.Printf("Error submitting answer for question %s: %v", sub.QuestionID, err)
+            continue
         }
-    case "number_letter_similarity":
-        answerPairsRaw, ok := submission.Answer.([]interface{})
-        if ok {
-            answerPairs := make([]map[string]string, len(answerPairsRaw))
-            for i, pairRaw := range answerPairsRaw {
-                pairMap, pairOk := pairRaw.(map[string]interface{})
-                if !pairOk {
-                    log.Printf("Invalid pair format for questionID: %s", submission.QuestionID)
-                    break
-                }
-                left, leftOk := pairMap["left"].(string)
-                right, rightOk := pairMap["right"].(string)
-                if !leftOk || !rightOk {
-                    log.Printf("Invalid left or right value for questionID: %s", submission.QuestionID)
-                    break
-                }
-                answerPairs[i] = map[string]string{
-                    "left":  left,
-                    "right": right,
-                }
-            }
-            isCorrect = ValidatePairs(answerPairs, question.CorrectPairs)
-        } else {
-            log.Printf("Expected []interface{} for number_letter_similarity, got %T for questionID: %s", submission.Answer, submission.QuestionID)
+        answersByType[answerType] = append(answersByType[answerType], answer)
+        if answer.Correct {
+            result.CorrectAnswers++
         }
-    default:
-        answerStr, ok := submission.Answer.(string)
-        if ok && len(question.Options) > 0 {
-            for _, opt := range question.Options {
-                if opt == answerStr {
-                    isCorrect = true
-                    break
-                }
-            }
+        result.Score += answer.Score
+        result.Answers = append(result.Answers, answerFeedback(answer))
+    }
+
+    for answerType, answers := range answersByType {
+        attempt := newAssessmentAttempt(userID, answerType, "", answers, nil, len(answers), now)
+        attempt.Partial = true
+        if _, err := repo.CreateAssessmentAttempt(ctx, attempt); err != nil {
+            return models.AssessmentResult{}, fmt.Errorf("failed to record assessment attempt: %w", err)
         }
     }
 
-    result := models.AssessmentResult{
-        Type:           question.Type,
-        CorrectAnswers: 1,
-        TotalQuestions: 1,
+    log.Printf("Successfully processed %d submissions for userID: %s, type: %s, correct: %d", len(submissions), userID, questionType, result.CorrectAnswers)
+    return result, nil
+}
+
+// saveAssessmentAnswer scores a submitted answer and stores it as the user's latest answer to the
+// question. It returns the question's type along with the scored answer.
+func saveAssessmentAnswer(ctx context.Context, userID string, submission models.AssessmentSubmission, answeredAt time.Time) (string, models.AssessmentAnswer, error) {
+    question, err := FindAssessmentQuestion(ctx, submission.QuestionID)
+    if errors.Is(err, repository.ErrNotFound) {
+        return "", models.AssessmentAnswer{}, fmt.Errorf("question with ID %s not found", submission.QuestionID)
     }
-    if !isCorrect {
-        result.CorrectAnswers = 0
+    if err != nil {
+        return "", models.AssessmentAnswer{}, fmt.Errorf("failed to retrieve question: %w", err)
     }
 
-    _, err = firestoreClient.Collection("users").Doc(userID).Collection("assessments").Doc(question.Type).Collection("submissions").Doc(submission.QuestionID).Set(ctx, map[string]interface{}{
-        "type":           question.Type,
-        "category":       question.Category,
-        "questionID":     submission.QuestionID,
-        "correctAnswers": result.CorrectAnswers,
-        "answer":         submission.Answer,
-        "status":         "completed",
-        "timestamp":      firestore.ServerTimestamp,
-    }, firestore.MergeAll)
+    score, err := validateAnswer(question.AnswerFormat, assessmentAnswerKey(question), submission.Answer)
     if err != nil {
-        return models.AssessmentResult{}, fmt.Errorf("failed to save assessment result: %w", err)
+        return "", models.AssessmentAnswer{}, fmt.Errorf("failed to validate answer for question %s: %w", submission.QuestionID, err)
     }
 
-    log.Printf("Saved assessment result for userID: %s, questionID: %s, type: %s, isCorrect: %v", userID, submission.QuestionID, question.Type, isCorrect)
-    return result, nil
+    correctAnswers := 0
+    if score.Correct {
+        correctAnswers = 1
+    }
+    err = repo.SaveAssessmentSubmission(ctx, userID, models.AssessmentSubmissionRecord{
+        QuestionID:     submission.QuestionID,
+        Type:           question.Type,
+        Category:       question.Category,
+        CorrectAnswers: correctAnswers,
+        Score:          score.Score,
+        Items:          score.Items,
+        Match:          score.Match,
+        Answer:         submission.Answer,
+        Status:         "completed",
+    })
+    if err != nil {
+        return "", models.AssessmentAnswer{}, fmt.Errorf("failed to save assessment result: %w", err)
+    }
+
+    log.Printf("Saved assessment result for userID: %s, questionID: %s, type: %s, isCorrect: %v, score: %.2f", userID, submission.QuestionID, question.Type, score.Correct, score.Score)
+    return question.Type, models.AssessmentAnswer{
+        QuestionID: submission.QuestionID,
+        Category:   question.Category,
+        Answer:     submission.Answer,
+        Correct:    score.Correct,
+        Score:      score.Score,
+        Items:      score.Items,
+        Match:      score.Match,
+        AnsweredAt: answeredAt,
+    }, nil
 }
 
-// GetAssessmentResults retrieves all assessment results for a user.
-func GetAssessmentResults(ctx context.Context, userID string, firebaseToken string) ([]models.AssessmentResult, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
+// answerFeedback reports a scored answer back to the client.
+func answerFeedback(answer models.AssessmentAnswer) models.AnswerFeedback {
+    return models.AnswerFeedback{
+        QuestionID: answer.QuestionID,
+        AnswerScore: models.AnswerScore{
+            Correct: answer.Correct,
+            Score:   answer.Score,
+            Items:   answer.Items,
+            Match:   answer.Match,
+        },
     }
-    defer firestoreClient.Close()
+}
 
+// GetAssessmentResults retrieves all assessment results for a user.
+func GetAssessmentResults(ctx context.Context, userID string, firebaseToken string) ([]models.AssessmentResult, error) {
     totalQuestions := map[string]int{
         "visual":      0,
         "auditory":    0,
@@ -395,17 +208,12 @@ func GetAssessmentResults(ctx context.Context, userID string, firebaseToken stri
         "tactile":     0,
     }
     for _, t := range []string{"visual", "auditory", "kinesthetic", "tactile"} {
-        categoriesIter := firestoreClient.Collection("assessmentQuestions").Doc(t).Collections(ctx)
-        categories, err := categoriesIter.GetAll()
+        questions, skipped, err := repo.ListAssessmentQuestions(ctx, t)
         if err != nil {
             continue
         }
-        for _, category := range categories {
-            docs, err := category.Documents(ctx).GetAll()
-            if err == nil {
-                totalQuestions[t] += len(docs)
-            }
-        }
+        logSkippedQuestions(skipped, "assessment", "type "+t)
+        totalQuestions[t] = len(questions)
     }
 
     results := []models.AssessmentResult{
@@ -415,11 +223,23 @@ func GetAssessmentResults(ctx context.Context, userID string, firebaseToken stri
         {Type: "tactile", CorrectAnswers: 0, TotalQuestions: totalQuestions["tactile"], Status: "not started"},
     }
 
-    for _, result := range results {
+    for i, result := range results {
         typeName := result.Type
-        submissionDocs, err := firestoreClient.Collection("users").Doc(userID).Collection("assessments").Doc(typeName).Collection("submissions").Documents(ctx).GetAll()
+        sessions, err := repo.ListAssessmentSessions(ctx, userID, typeName)
+        if err != nil {
+            log.Printf("Failed to fetch assessment sessions for type %s: %v", typeName, err)
+        }
+        if finished := latestFinishedSession(sessions); finished != nil {
+            results[i].CorrectAnswers = finished.CorrectAnswers
+            results[i].Score = finished.Score
+            results[i].TotalQuestions = len(finished.QuestionIDs)
+            results[i].Status = "completed"
+            continue
+        }
+
+        submissions, err := repo.ListAssessmentSubmissions(ctx, userID, typeName)
         if err != nil {
-            if status.Code(err) == codes.NotFound {
+            if errors.Is(err, repository.ErrNotFound) {
                 log.Printf("No submissions found for type %s for userID: %s", typeName, userID)
                 continue
             }
@@ -428,26 +248,29 @@ func GetAssessmentResults(ctx context.Context, userID string, firebaseToken stri
         }
 
         correctAnswers := 0
-        for _, subDoc := range submissionDocs {
-            data := subDoc.Data()
-            if correct, ok := data["correctAnswers"].(int64); ok {
-                correctAnswers += int(correct)
-            } else if correct, ok := data["correctAnswers"].(int); ok {
-                correctAnswers += correct
-            }
+        score := 0.0
+        for _, sub := range submissions {
+            correctAnswers += sub.CorrectAnswers
+            score += sub.Score
         }
 
-        for i, r := range results {
-            if r.Type == typeName {
-                results[i].CorrectAnswers = correctAnswers
-                if len(submissionDocs) > 0 {
-                    results[i].Status = "completed"
-                }
-                break
-            }
+        results[i].CorrectAnswers = correctAnswers
+        results[i].Score = score
+        if len(submissions) > 0 || len(sessions) > 0 {
+            results[i].Status = "in progress"
         }
     }
 
     log.Printf("Retrieved %d assessment results for userID: %s", len(results), userID)
     return results, nil
-}
\ No newline at end of file
+}
+
+// latestFinishedSession returns the most recently started finished session, or nil if none is finished.
+func latestFinishedSession(sessions []models.AssessmentSession) *models.AssessmentSession {
+    for i := len(sessions) - 1; i >= 0; i-- {
+        if sessions[i].Status == models.SessionStatusFinished {
+            return &sessions[i]
+        }
+    }
+    return nil
+}
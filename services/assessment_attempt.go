@@ -0,0 +1,203 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "sort"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// ErrAttemptTypeMismatch is returned when diffing attempts of different question types.
+var ErrAttemptTypeMismatch = errors.New("attempts are for different question types")
+
+// newAssessmentAttempt builds an attempt from scored answers, totalling them overall and per category.
+// questionCategories maps every question in the attempt to its category, so unanswered questions
+// count towards their category's total; answered questions missing from it use the answer's category.
+func newAssessmentAttempt(userID, questionType, sessionID string, answers []models.AssessmentAnswer, questionCategories map[string]string, totalQuestions int, completedAt time.Time) models.AssessmentAttempt {
+    attempt := models.AssessmentAttempt{
+        UserID:         userID,
+        Type:           questionType,
+        SessionID:      sessionID,
+        Answers:        answers,
+        Categories:     []models.CategoryScore{},
+        TotalQuestions: totalQuestions,
+        CompletedAt:    completedAt,
+    }
+
+    categories := make(map[string]*models.CategoryScore)
+    categoryScore := func(name string) *models.CategoryScore {
+        category, ok := categories[name]
+        if !ok {
+            category = &models.CategoryScore{Category: name}
+            categories[name] = category
+        }
+        return category
+    }
+    for _, name := range questionCategories {
+        categoryScore(name).TotalQuestions++
+    }
+    for _, answer := range answers {
+        category := categoryScore(answer.Category)
+        if _, ok := questionCategories[answer.QuestionID]; !ok {
+            category.TotalQuestions++
+        }
+        category.Score += answer.Score
+        attempt.Score += answer.Score
+        if answer.Correct {
+            category.CorrectAnswers++
+            attempt.CorrectAnswers++
+        }
+    }
+    for _, category := range categories {
+        attempt.Categories = append(attempt.Categories, *category)
+    }
+    sort.Slice(attempt.Categories, func(i, j int) bool {
+        return attempt.Categories[i].Category < attempt.Categories[j].Category
+    })
+    return attempt
+}
+
+// ListAssessmentAttempts retrieves the user's assessment attempts, newest first. An empty
+// questionType lists attempts of every type.
+func ListAssessmentAttempts(ctx context.Context, userID, questionType string) ([]models.AssessmentAttempt, error) {
+    if questionType != "" && !isQuestionType(questionType) {
+        return nil, fmt.Errorf("%w: %s", ErrInvalidQuestionType, questionType)
+    }
+
+    attempts, err := repo.ListAssessmentAttempts(ctx, userID, questionType)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve assessment attempts: %w", err)
+    }
+    for i, j := 0, len(attempts)-1; i < j; i, j = i+1, j-1 {
+        attempts[i], attempts[j] = attempts[j], attempts[i]
+    }
+
+    log.Printf("Retrieved %d assessment attempts for userID: %s, type: %s", len(attempts), userID, questionType)
+    return attempts, nil
+}
+
+// GetAssessmentAttempt retrieves one of the user's assessment attempts.
+func GetAssessmentAttempt(ctx context.Context, userID, attemptID string) (models.AssessmentAttempt, error) {
+    attempt, err := repo.GetAssessmentAttempt(ctx, userID, attemptID)
+    if err != nil {
+        return models.AssessmentAttempt{}, fmt.Errorf("failed to retrieve assessment attempt %s: %w", attemptID, err)
+    }
+    return attempt, nil
+}
+
+// DiffAssessmentAttempts compares two of the user's attempts of the same type overall, per
+// category and per question.
+func DiffAssessmentAttempts(ctx context.Context, userID, fromID, toID string) (models.AttemptDiff, error) {
+    from, err := GetAssessmentAttempt(ctx, userID, fromID)
+    if err != nil {
+        return models.AttemptDiff{}, err
+    }
+    to, err := GetAssessmentAttempt(ctx, userID, toID)
+    if err != nil {
+        return models.AttemptDiff{}, err
+    }
+    if from.Type != to.Type {
+        return models.AttemptDiff{}, fmt.Errorf("%w: %s and %s", ErrAttemptTypeMismatch, from.Type, to.Type)
+    }
+
+    diff := models.AttemptDiff{
+        Type:       from.Type,
+        From:       from.ID,
+        To:         to.ID,
+        Overall:    scoreChange(from.CorrectAnswers, to.CorrectAnswers, from.Score, to.Score),
+        Categories: []models.CategoryChange{},
+        Questions:  []models.QuestionChange{},
+    }
+
+    fromCategories := make(map[string]models.CategoryScore, len(from.Categories))
+    for _, c := range from.Categories {
+        fromCategories[c.Category] = c
+    }
+    toCategories := make(map[string]models.CategoryScore, len(to.Categories))
+    for _, c := range to.Categories {
+        toCategories[c.Category] = c
+    }
+    for _, category := range unionKeys(fromCategories, toCategories) {
+        f, t := fromCategories[category], toCategories[category]
+        diff.Categories = append(diff.Categories, models.CategoryChange{
+            Category:    category,
+            ScoreChange: scoreChange(f.CorrectAnswers, t.CorrectAnswers, f.Score, t.Score),
+        })
+    }
+
+    fromAnswers := make(map[string]models.AssessmentAnswer, len(from.Answers))
+    for _, a := range from.Answers {
+        fromAnswers[a.QuestionID] = a
+    }
+    toAnswers := make(map[string]models.AssessmentAnswer, len(to.Answers))
+    for _, a := range to.Answers {
+        toAnswers[a.QuestionID] = a
+    }
+    for _, questionID := range unionKeys(fromAnswers, toAnswers) {
+        diff.Questions = append(diff.Questions, questionChange(questionID, fromAnswers, toAnswers))
+    }
+
+    log.Printf("Diffed assessment attempts %s and %s for userID: %s, score delta: %.2f", fromID, toID, userID, diff.Overall.ScoreDelta)
+    return diff, nil
+}
+
+// scoreChange compares correct-answer counts and scores between two attempts.
+func scoreChange(fromCorrect, toCorrect int, fromScore, toScore float64) models.ScoreChange {
+    return models.ScoreChange{
+        FromCorrect:  fromCorrect,
+        ToCorrect:    toCorrect,
+        CorrectDelta: toCorrect - fromCorrect,
+        FromScore:    fromScore,
+        ToScore:      toScore,
+        ScoreDelta:   toScore - fromScore,
+    }
+}
+
+// questionChange classifies how the answer to a question changed between two attempts.
+func questionChange(questionID string, from, to map[string]models.AssessmentAnswer) models.QuestionChange {
+    f, inFrom := from[questionID]
+    t, inTo := to[questionID]
+    change := models.QuestionChange{QuestionID: questionID}
+    switch {
+    case !inFrom:
+        change.Category = t.Category
+        change.Change = models.QuestionAdded
+        change.ToScore = &t.Score
+    case !inTo:
+        change.Category = f.Category
+        change.Change = models.QuestionRemoved
+        change.FromScore = &f.Score
+    default:
+        change.Category = t.Category
+        change.FromScore = &f.Score
+        change.ToScore = &t.Score
+        switch {
+        case t.Score > f.Score:
+            change.Change = models.QuestionImproved
+        case t.Score < f.Score:
+            change.Change = models.QuestionRegressed
+        default:
+            change.Change = models.QuestionUnchanged
+        }
+    }
+    return change
+}
+
+// unionKeys returns the sorted keys present in either map.
+func unionKeys[V any](a, b map[string]V) []string {
+    keys := make([]string, 0, len(a)+len(b))
+    for key := range a {
+        keys = append(keys, key)
+    }
+    for key := range b {
+        if _, ok := a[key]; !ok {
+            keys = append(keys, key)
+        }
+    }
+    sort.Strings(keys)
+    return keys
+}
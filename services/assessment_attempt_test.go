@@ -0,0 +1,142 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestFinishAssessmentSessionCategoryTotals(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    first := saveAssessmentQuestion(t, "visual", "letter_recognition", "b")
+    saveAssessmentQuestion(t, "visual", "letter_recognition", "d")
+    saveAssessmentQuestion(t, "visual", "word_recognition", "bed")
+
+    session, _, err := StartAssessmentSession(ctx, "user", "visual")
+    if err != nil {
+        t.Fatalf("StartAssessmentSession() error = %v", err)
+    }
+    _, _, err = SubmitAssessmentSessionAnswers(ctx, "user", session.ID, []models.AssessmentSubmission{{QuestionID: first.ID, Answer: "b"}})
+    if err != nil {
+        t.Fatalf("SubmitAssessmentSessionAnswers() error = %v", err)
+    }
+    if _, err := FinishAssessmentSession(ctx, "user", session.ID); err != nil {
+        t.Fatalf("FinishAssessmentSession() error = %v", err)
+    }
+
+    // Unanswered questions count towards their category's total.
+    attempt, err := GetAssessmentAttempt(ctx, "user", session.ID)
+    if err != nil {
+        t.Fatalf("GetAssessmentAttempt() error = %v", err)
+    }
+    want := []models.CategoryScore{
+        {Category: "letter_recognition", CorrectAnswers: 1, Score: 1, TotalQuestions: 2},
+        {Category: "word_recognition", TotalQuestions: 1},
+    }
+    if !slices.Equal(attempt.Categories, want) {
+        t.Errorf("attempt categories = %+v, want %+v", attempt.Categories, want)
+    }
+    if attempt.CorrectAnswers != 1 || attempt.TotalQuestions != 3 {
+        t.Errorf("attempt = %d of %d correct, want 1 of 3", attempt.CorrectAnswers, attempt.TotalQuestions)
+    }
+}
+
+func TestDiffAssessmentAttempts(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    now := time.Now().UTC()
+    create := func(id, questionType string, answers ...models.AssessmentAnswer) {
+        t.Helper()
+        attempt := newAssessmentAttempt("user", questionType, "", answers, nil, len(answers), now)
+        attempt.ID = id
+        if _, err := r.CreateAssessmentAttempt(ctx, attempt); err != nil {
+            t.Fatalf("CreateAssessmentAttempt() error = %v", err)
+        }
+    }
+    create("from", "visual",
+        models.AssessmentAnswer{QuestionID: "q1", Category: "letters", Score: 0},
+        models.AssessmentAnswer{QuestionID: "q2", Category: "letters", Correct: true, Score: 1},
+        models.AssessmentAnswer{QuestionID: "q3", Category: "words", Correct: true, Score: 1},
+    )
+    create("to", "visual",
+        models.AssessmentAnswer{QuestionID: "q1", Category: "letters", Correct: true, Score: 1},
+        models.AssessmentAnswer{QuestionID: "q2", Category: "letters", Score: 0.5},
+        models.AssessmentAnswer{QuestionID: "q4", Category: "shapes", Correct: true, Score: 1},
+    )
+    create("auditory", "auditory")
+
+    diff, err := DiffAssessmentAttempts(ctx, "user", "from", "to")
+    if err != nil {
+        t.Fatalf("DiffAssessmentAttempts() error = %v", err)
+    }
+    if diff.Overall.CorrectDelta != 0 || diff.Overall.ScoreDelta != 0.5 {
+        t.Errorf("overall = %+v, want correct delta 0 and score delta 0.5", diff.Overall)
+    }
+
+    categories := make(map[string]float64, len(diff.Categories))
+    for _, c := range diff.Categories {
+        categories[c.Category] = c.ScoreDelta
+    }
+    wantCategories := map[string]float64{"letters": 0.5, "shapes": 1, "words": -1}
+    if len(categories) != len(wantCategories) {
+        t.Errorf("categories = %v, want %v", categories, wantCategories)
+    }
+    for category, want := range wantCategories {
+        if got, ok := categories[category]; !ok || got != want {
+            t.Errorf("category %s score delta = %v, want %v", category, got, want)
+        }
+    }
+
+    var changes []string
+    for _, q := range diff.Questions {
+        changes = append(changes, q.QuestionID+":"+q.Change)
+    }
+    wantChanges := []string{
+        "q1:" + models.QuestionImproved,
+        "q2:" + models.QuestionRegressed,
+        "q3:" + models.QuestionRemoved,
+        "q4:" + models.QuestionAdded,
+    }
+    if !slices.Equal(changes, wantChanges) {
+        t.Errorf("question changes = %v, want %v", changes, wantChanges)
+    }
+
+    if _, err := DiffAssessmentAttempts(ctx, "user", "from", "auditory"); !errors.Is(err, ErrAttemptTypeMismatch) {
+        t.Errorf("diff across types error = %v, want ErrAttemptTypeMismatch", err)
+    }
+}
+
+func TestSubmitAssessmentAnswersRecordsPartialAttempt(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    question := saveAssessmentQuestion(t, "visual", "letter_recognition", "b")
+    saveAssessmentQuestion(t, "visual", "letter_recognition", "d")
+
+    _, err := SubmitAssessmentAnswers(ctx, "user", "visual", []models.AssessmentSubmission{{QuestionID: question.ID, Answer: "b"}})
+    if err != nil {
+        t.Fatalf("SubmitAssessmentAnswers() error = %v", err)
+    }
+    attempts, err := ListAssessmentAttempts(ctx, "user", "visual")
+    if err != nil {
+        t.Fatalf("ListAssessmentAttempts() error = %v", err)
+    }
+    if len(attempts) != 1 || !attempts[0].Partial {
+        t.Fatalf("attempts = %+v, want one partial attempt", attempts)
+    }
+
+    // A batch of one answer must not read as a perfect visual profile.
+    profile, err := ComputeLearningProfile(ctx, "user")
+    if err != nil {
+        t.Fatalf("ComputeLearningProfile() error = %v", err)
+    }
+    for _, modality := range profile.Modalities {
+        if modality.Assessed {
+            t.Errorf("modality %s is assessed from a partial attempt", modality.Type)
+        }
+    }
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "sort"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// Errors returned by assessment session operations.
+var (
+    ErrInvalidQuestionType  = errors.New("invalid question type")
+    ErrNoQuestions          = errors.New("no questions available")
+    ErrSessionFinished      = errors.New("assessment session is already finished")
+    ErrQuestionNotInSession = errors.New("question is not part of the assessment session")
+)
+
+// questionTypes lists the learning-style types questions are grouped by.
+var questionTypes = []string{"visual", "auditory", "kinesthetic", "tactile"}
+
+// isQuestionType reports whether t is a known question type.
+func isQuestionType(t string) bool {
+    for _, known := range questionTypes {
+        if t == known {
+            return true
+        }
+    }
+    return false
+}
+
+// StartAssessmentSession resumes the user's in-progress session for a type, or starts a new
+// session over the type's current questions. It reports whether an existing session was resumed.
+func StartAssessmentSession(ctx context.Context, userID, questionType string) (models.AssessmentSession, bool, error) {
+    if !isQuestionType(questionType) {
+        return models.AssessmentSession{}, false, fmt.Errorf("%w: %s", ErrInvalidQuestionType, questionType)
+    }
+
+    sessions, err := repo.ListAssessmentSessions(ctx, userID, questionType)
+    if err != nil {
+        return models.AssessmentSession{}, false, fmt.Errorf("failed to retrieve assessment sessions: %w", err)
+    }
+    for i := len(sessions) - 1; i >= 0; i-- {
+        if sessions[i].Status == models.SessionStatusInProgress {
+            log.Printf("Resumed assessment session %s for userID: %s, type: %s", sessions[i].ID, userID, questionType)
+            return sessions[i], true, nil
+        }
+    }
+
+    questions, skipped, err := repo.ListAssessmentQuestions(ctx, questionType)
+    if err != nil {
+        return models.AssessmentSession{}, false, fmt.Errorf("failed to retrieve assessment questions: %w", err)
+    }
+    logSkippedQuestions(skipped, "assessment", "type "+questionType)
+    if len(questions) == 0 {
+        return models.AssessmentSession{}, false, fmt.Errorf("%w for type %s", ErrNoQuestions, questionType)
+    }
+    questionIDs := make([]string, len(questions))
+    for i, q := range questions {
+        questionIDs[i] = q.ID
+    }
+    sort.Strings(questionIDs)
+
+    now := time.Now().UTC()
+    session := models.AssessmentSession{
+        UserID:      userID,
+        Type:        questionType,
+        Status:      models.SessionStatusInProgress,
+        QuestionIDs: questionIDs,
+        Answers:     []models.AssessmentAnswer{},
+        StartedAt:   now,
+        UpdatedAt:   now,
+    }
+    session.ID, err = repo.CreateAssessmentSession(ctx, session)
+    if err != nil {
+        return models.AssessmentSession{}, false, fmt.Errorf("failed to create assessment session: %w", err)
+    }
+
+    log.Printf("Started assessment session %s for userID: %s, type: %s, questions: %d", session.ID, userID, questionType, len(questionIDs))
+    return session, false, nil
+}
+
+// GetAssessmentSession retrieves one of the user's assessment sessions.
+func GetAssessmentSession(ctx context.Context, userID, sessionID string) (models.AssessmentSession, error) {
+    session, err := repo.GetAssessmentSession(ctx, userID, sessionID)
+    if err != nil {
+        return models.AssessmentSession{}, fmt.Errorf("failed to retrieve assessment session: %w", err)
+    }
+    return session, nil
+}
+
+// NextAssessmentQuestion returns the first unanswered question of a session together with the
+// number of unanswered questions. The question is nil once every question has been answered.
+// Questions deleted after the session started are skipped.
+func NextAssessmentQuestion(ctx context.Context, userID, sessionID string) (*models.AssessmentQuestion, int, error) {
+    session, err := GetAssessmentSession(ctx, userID, sessionID)
+    if err != nil {
+        return nil, 0, err
+    }
+    if session.Status == models.SessionStatusFinished {
+        return nil, 0, ErrSessionFinished
+    }
+
+    unanswered := unansweredQuestions(session)
+    for _, questionID := range unanswered {
+        q, err := FindAssessmentQuestion(ctx, questionID)
+        if errors.Is(err, repository.ErrNotFound) {
+            log.Printf("Skipping deleted question %s in assessment session %s", questionID, sessionID)
+            continue
+        }
+        if err != nil {
+            return nil, 0, fmt.Errorf("failed to retrieve question: %w", err)
+        }
+        return &q, len(unanswered), nil
+    }
+    return nil, len(unanswered), nil
+}
+
+// SubmitAssessmentSessionAnswers scores answers to questions of an in-progress session. Answering
+// a question again replaces its earlier answer.
+func SubmitAssessmentSessionAnswers(ctx context.Context, userID, sessionID string, submissions []models.AssessmentSubmission) (models.AssessmentSession, []models.AnswerFeedback, error) {
+    session, err := GetAssessmentSession(ctx, userID, sessionID)
+    if err != nil {
+        return models.AssessmentSession{}, nil, err
+    }
+    if session.Status == models.SessionStatusFinished {
+        return models.AssessmentSession{}, nil, ErrSessionFinished
+    }
+    for _, sub := range submissions {
+        if !containsString(session.QuestionIDs, sub.QuestionID) {
+            return models.AssessmentSession{}, nil, fmt.Errorf("%w: %s", ErrQuestionNotInSession, sub.QuestionID)
+        }
+    }
+
+    now := time.Now().UTC()
+    answers := make([]models.AssessmentAnswer, 0, len(submissions))
+    feedback := make([]models.AnswerFeedback, 0, len(submissions))
+    for _, sub := range submissions {
+        _, answer, err := saveAssessmentAnswer(ctx, userID, sub, now)
+        if err != nil {
+            log.Printf("Error submitting answer for question %s in session %s: %v", sub.QuestionID, sessionID, err)
+            continue
+        }
+        answers = append(answers, answer)
+        feedback = append(feedback, answerFeedback(answer))
+    }
+
+    session, err = repo.UpdateAssessmentSession(ctx, userID, sessionID, func(session *models.AssessmentSession) error {
+        if session.Status == models.SessionStatusFinished {
+            return ErrSessionFinished
+        }
+        for _, answer := range answers {
+            recordSessionAnswer(session, answer)
+        }
+        session.UpdatedAt = now
+        return nil
+    })
+    if err != nil {
+        return models.AssessmentSession{}, nil, fmt.Errorf("failed to update assessment session: %w", err)
+    }
+
+    log.Printf("Recorded %d answers in assessment session %s for userID: %s", len(answers), sessionID, userID)
+    return session, feedback, nil
+}
+
+// FinishAssessmentSession marks a session finished and records it as an assessment attempt.
+// Unanswered questions count as incorrect. Finishing an already finished session returns it
+// unchanged, recording its attempt if an earlier call failed to.
+func FinishAssessmentSession(ctx context.Context, userID, sessionID string) (models.AssessmentSession, error) {
+    session, err := repo.UpdateAssessmentSession(ctx, userID, sessionID, func(session *models.AssessmentSession) error {
+        if session.Status == models.SessionStatusFinished {
+            return nil
+        }
+        now := time.Now().UTC()
+        session.Status = models.SessionStatusFinished
+        session.UpdatedAt = now
+        session.FinishedAt = &now
+        return nil
+    })
+    if err != nil {
+        return models.AssessmentSession{}, fmt.Errorf("failed to finish assessment session: %w", err)
+    }
+
+    questionCategories, err := sessionQuestionCategories(ctx, session)
+    if err != nil {
+        return models.AssessmentSession{}, err
+    }
+    attempt := newAssessmentAttempt(userID, session.Type, session.ID, session.Answers, questionCategories, len(session.QuestionIDs), *session.FinishedAt)
+    attempt.ID = session.ID
+    if _, err := repo.CreateAssessmentAttempt(ctx, attempt); err != nil && !errors.Is(err, repository.ErrAlreadyExists) {
+        return models.AssessmentSession{}, fmt.Errorf("failed to record assessment attempt: %w", err)
+    }
+    refreshLearningProfile(ctx, userID)
+
+    log.Printf("Finished assessment session %s for userID: %s, correct: %d/%d", sessionID, userID, session.CorrectAnswers, len(session.QuestionIDs))
+    return session, nil
+}
+
+// sessionQuestionCategories maps the session's questions to their categories. Questions removed
+// since the session started are left out, so only their answers count towards a category.
+func sessionQuestionCategories(ctx context.Context, session models.AssessmentSession) (map[string]string, error) {
+    questions, skipped, err := repo.ListAssessmentQuestions(ctx, session.Type)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve assessment questions: %w", err)
+    }
+    logSkippedQuestions(skipped, "assessment", "type "+session.Type)
+
+    categories := make(map[string]string, len(questions))
+    for _, question := range questions {
+        categories[question.ID] = question.Category
+    }
+    questionCategories := make(map[string]string, len(session.QuestionIDs))
+    for _, id := range session.QuestionIDs {
+        if category, ok := categories[id]; ok {
+            questionCategories[id] = category
+        }
+    }
+    return questionCategories, nil
+}
+
+// recordSessionAnswer adds or replaces an answer in the session and recomputes its totals.
+func recordSessionAnswer(session *models.AssessmentSession, answer models.AssessmentAnswer) {
+    replaced := false
+    for i, existing := range session.Answers {
+        if existing.QuestionID == answer.QuestionID {
+            session.Answers[i] = answer
+            replaced = true
+            break
+        }
+    }
+    if !replaced {
+        session.Answers = append(session.Answers, answer)
+    }
+
+    session.CorrectAnswers = 0
+    session.Score = 0
+    for _, a := range session.Answers {
+        if a.Correct {
+            session.CorrectAnswers++
+        }
+        session.Score += a.Score
+    }
+}
+
+// unansweredQuestions returns the session's question IDs that have no answer yet, in session order.
+func unansweredQuestions(session models.AssessmentSession) []string {
+    answered := make(map[string]bool, len(session.Answers))
+    for _, a := range session.Answers {
+        answered[a.QuestionID] = true
+    }
+    unanswered := make([]string, 0, len(session.QuestionIDs))
+    for _, questionID := range session.QuestionIDs {
+        if !answered[questionID] {
+            unanswered = append(unanswered, questionID)
+        }
+    }
+    return unanswered
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "log"
+    "sort"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// Errors returned when rotating a refresh token.
+var (
+    ErrInvalidRefreshToken = errors.New("invalid refresh token")
+    ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+// Lifetimes of issued tokens. A session stays signed in for RefreshTokenTTL without being refreshed.
+const (
+    AccessTokenTTL  = 24 * time.Hour
+    RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// maxDeviceLength caps the device name stored with a session.
+const maxDeviceLength = 100
+
+// NewTokenID returns a random identifier for an access or refresh token.
+func NewTokenID() (string, error) {
+    b := make([]byte, 16)
+    if _, err := rand.Read(b); err != nil {
+        return "", fmt.Errorf("failed to generate token ID: %w", err)
+    }
+    return hex.EncodeToString(b), nil
+}
+
+// StartAuthSession creates a session for a newly signed-in device and returns it with the ID of
+// its first refresh token.
+func StartAuthSession(ctx context.Context, userID, device string) (models.AuthSession, error) {
+    tokenID, err := NewTokenID()
+    if err != nil {
+        return models.AuthSession{}, err
+    }
+    if len(device) > maxDeviceLength {
+        device = device[:maxDeviceLength]
+    }
+
+    now := time.Now().UTC()
+    session := models.AuthSession{
+        UserID:     userID,
+        Device:     device,
+        TokenID:    tokenID,
+        CreatedAt:  now,
+        LastUsedAt: now,
+        ExpiresAt:  now.Add(RefreshTokenTTL),
+    }
+    session.ID, err = repo.CreateAuthSession(ctx, session)
+    if err != nil {
+        return models.AuthSession{}, fmt.Errorf("failed to create auth session: %w", err)
+    }
+
+    log.Printf("Started auth session %s for userID: %s, device: %s", session.ID, userID, device)
+    return session, nil
+}
+
+// RotateAuthSession exchanges the session's current refresh token ID for a new one and extends
+// the session. A token ID that is no longer current means a rotated token was replayed, so the
+// session is revoked and ErrRefreshTokenReused is returned.
+func RotateAuthSession(ctx context.Context, userID, sessionID, tokenID string) (models.AuthSession, error) {
+    nextTokenID, err := NewTokenID()
+    if err != nil {
+        return models.AuthSession{}, err
+    }
+
+    reused := false
+    session, err := repo.UpdateAuthSession(ctx, userID, sessionID, func(session *models.AuthSession) error {
+        now := time.Now().UTC()
+        if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+            return ErrInvalidRefreshToken
+        }
+        if session.TokenID != tokenID {
+            reused = true
+            session.RevokedAt = &now
+            session.RevokedReason = models.AuthSessionRevokedReuse
+            return nil
+        }
+        session.TokenID = nextTokenID
+        session.LastUsedAt = now
+        session.ExpiresAt = now.Add(RefreshTokenTTL)
+        return nil
+    })
+    if err != nil {
+        return models.AuthSession{}, err
+    }
+    if reused {
+        log.Printf("Refresh token reuse detected for userID: %s, session %s revoked", userID, sessionID)
+        if err := revokeSessionTokens(ctx, userID, sessionID); err != nil {
+            return models.AuthSession{}, err
+        }
+        return models.AuthSession{}, ErrRefreshTokenReused
+    }
+    return session, nil
+}
+
+// ListAuthSessions retrieves the user's signed-in devices, most recently used first, marking the
+// session the request was made from.
+func ListAuthSessions(ctx context.Context, userID, currentSessionID string) ([]models.AuthSession, error) {
+    stored, err := repo.ListAuthSessions(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve auth sessions: %w", err)
+    }
+
+    now := time.Now().UTC()
+    sessions := make([]models.AuthSession, 0, len(stored))
+    for _, session := range stored {
+        if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+            continue
+        }
+        session.Current = session.ID == currentSessionID
+        sessions = append(sessions, session)
+    }
+    sort.SliceStable(sessions, func(i, j int) bool {
+        return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+    })
+
+    log.Printf("Retrieved %d active auth sessions for userID: %s", len(sessions), userID)
+    return sessions, nil
+}
+
+// RevokeAuthSession signs a device out by revoking its session, so its refresh token can no
+// longer be used, and puts the session on the revocation list so its access tokens are rejected.
+func RevokeAuthSession(ctx context.Context, userID, sessionID, reason string) error {
+    _, err := repo.UpdateAuthSession(ctx, userID, sessionID, func(session *models.AuthSession) error {
+        if session.RevokedAt != nil {
+            return nil
+        }
+        now := time.Now().UTC()
+        session.RevokedAt = &now
+        session.RevokedReason = reason
+        return nil
+    })
+    if err != nil {
+        return fmt.Errorf("failed to revoke auth session: %w", err)
+    }
+    if err := revokeSessionTokens(ctx, userID, sessionID); err != nil {
+        return err
+    }
+
+    log.Printf("Revoked auth session %s for userID: %s, reason: %s", sessionID, userID, reason)
+    return nil
+}
+
+// RevokeAllAuthSessions signs the user out of every device and returns the IDs of the sessions
+// that were still active.
+func RevokeAllAuthSessions(ctx context.Context, userID, reason string) ([]string, error) {
+    sessions, err := ListAuthSessions(ctx, userID, "")
+    if err != nil {
+        return nil, err
+    }
+
+    revoked := make([]string, 0, len(sessions))
+    for _, session := range sessions {
+        if err := RevokeAuthSession(ctx, userID, session.ID, reason); err != nil {
+            return revoked, err
+        }
+        revoked = append(revoked, session.ID)
+    }
+    return revoked, nil
+}
+
+// revokeSessionTokens puts a session on the revocation list until every access token issued to
+// it has expired.
+func revokeSessionTokens(ctx context.Context, userID, sessionID string) error {
+    now := time.Now().UTC()
+    err := repo.RevokeTokens(ctx, []models.TokenRevocation{{
+        ID:        sessionID,
+        UserID:    userID,
+        Kind:      models.TokenRevocationSession,
+        RevokedAt: now,
+        ExpiresAt: now.Add(AccessTokenTTL),
+    }})
+    if err != nil {
+        return fmt.Errorf("failed to revoke tokens of session %s: %w", sessionID, err)
+    }
+    return nil
+}
+
+// RevokeAccessToken puts one access token on the revocation list until it expires.
+func RevokeAccessToken(ctx context.Context, userID, tokenID string, expiresAt time.Time) error {
+    err := repo.RevokeTokens(ctx, []models.TokenRevocation{{
+        ID:        tokenID,
+        UserID:    userID,
+        Kind:      models.TokenRevocationAccessToken,
+        RevokedAt: time.Now().UTC(),
+        ExpiresAt: expiresAt,
+    }})
+    if err != nil {
+        return fmt.Errorf("failed to revoke access token: %w", err)
+    }
+    return nil
+}
+
+// Logout revokes the access token of the request and, when it belongs to one, its session.
+func Logout(ctx context.Context, userID, sessionID, tokenID string, expiresAt time.Time) error {
+    if tokenID != "" {
+        if err := RevokeAccessToken(ctx, userID, tokenID, expiresAt); err != nil {
+            return err
+        }
+    }
+    if sessionID == "" {
+        return nil
+    }
+    err := RevokeAuthSession(ctx, userID, sessionID, models.AuthSessionRevokedLogout)
+    if errors.Is(err, repository.ErrNotFound) {
+        return nil
+    }
+    return err
+}
+
+// IsTokenRevoked reports whether an access token ID or session ID is on the revocation list.
+func IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+    revocation, err := repo.GetTokenRevocation(ctx, id)
+    if errors.Is(err, repository.ErrNotFound) {
+        return false, nil
+    }
+    if err != nil {
+        return false, fmt.Errorf("failed to look up token revocation: %w", err)
+    }
+    return time.Now().Before(revocation.ExpiresAt), nil
+}
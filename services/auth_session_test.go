@@ -0,0 +1,163 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+func TestRotateAuthSession(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    session, err := StartAuthSession(ctx, "user", "phone")
+    if err != nil {
+        t.Fatalf("StartAuthSession() error = %v", err)
+    }
+    firstToken := session.TokenID
+
+    rotated, err := RotateAuthSession(ctx, "user", session.ID, firstToken)
+    if err != nil {
+        t.Fatalf("RotateAuthSession() error = %v", err)
+    }
+    if rotated.TokenID == firstToken || rotated.ID != session.ID {
+        t.Fatalf("rotated session %s has token %s, want session %s with a new token", rotated.ID, rotated.TokenID, session.ID)
+    }
+
+    secondToken := rotated.TokenID
+    rotated, err = RotateAuthSession(ctx, "user", session.ID, secondToken)
+    if err != nil {
+        t.Fatalf("RotateAuthSession() with the current token error = %v", err)
+    }
+
+    // Replaying a rotated token revokes the whole session.
+    if _, err := RotateAuthSession(ctx, "user", session.ID, firstToken); !errors.Is(err, ErrRefreshTokenReused) {
+        t.Fatalf("RotateAuthSession() with a replayed token error = %v, want ErrRefreshTokenReused", err)
+    }
+    stored, err := r.GetAuthSession(ctx, "user", session.ID)
+    if err != nil {
+        t.Fatalf("GetAuthSession() error = %v", err)
+    }
+    if stored.RevokedAt == nil || stored.RevokedReason != models.AuthSessionRevokedReuse {
+        t.Errorf("session after reuse = %+v, want revoked for reuse", stored)
+    }
+    revoked, err := IsTokenRevoked(ctx, session.ID)
+    if err != nil || !revoked {
+        t.Errorf("IsTokenRevoked(session) = %v, %v, want true", revoked, err)
+    }
+
+    // The legitimate holder's latest token no longer works either.
+    if _, err := RotateAuthSession(ctx, "user", session.ID, rotated.TokenID); !errors.Is(err, ErrInvalidRefreshToken) {
+        t.Errorf("RotateAuthSession() after reuse error = %v, want ErrInvalidRefreshToken", err)
+    }
+    if _, err := RotateAuthSession(ctx, "user", "missing", firstToken); !errors.Is(err, repository.ErrNotFound) {
+        t.Errorf("RotateAuthSession() for an unknown session error = %v, want ErrNotFound", err)
+    }
+}
+
+func TestRotateAuthSessionExpired(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    session, err := StartAuthSession(ctx, "user", "phone")
+    if err != nil {
+        t.Fatalf("StartAuthSession() error = %v", err)
+    }
+    _, err = r.UpdateAuthSession(ctx, "user", session.ID, func(session *models.AuthSession) error {
+        session.ExpiresAt = time.Now().UTC().Add(-time.Minute)
+        return nil
+    })
+    if err != nil {
+        t.Fatalf("UpdateAuthSession() error = %v", err)
+    }
+
+    if _, err := RotateAuthSession(ctx, "user", session.ID, session.TokenID); !errors.Is(err, ErrInvalidRefreshToken) {
+        t.Errorf("RotateAuthSession() on an expired session error = %v, want ErrInvalidRefreshToken", err)
+    }
+}
+
+func TestRevokeAuthSession(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    phone, err := StartAuthSession(ctx, "user", "phone")
+    if err != nil {
+        t.Fatalf("StartAuthSession() error = %v", err)
+    }
+    if _, err := StartAuthSession(ctx, "user", "tablet"); err != nil {
+        t.Fatalf("StartAuthSession() error = %v", err)
+    }
+    if err := RevokeAuthSession(ctx, "user", phone.ID, models.AuthSessionRevokedByUser); err != nil {
+        t.Fatalf("RevokeAuthSession() error = %v", err)
+    }
+
+    sessions, err := ListAuthSessions(ctx, "user", "")
+    if err != nil {
+        t.Fatalf("ListAuthSessions() error = %v", err)
+    }
+    if len(sessions) != 1 || sessions[0].Device != "tablet" {
+        t.Fatalf("active sessions = %+v, want only the tablet", sessions)
+    }
+    if _, err := RotateAuthSession(ctx, "user", phone.ID, phone.TokenID); !errors.Is(err, ErrInvalidRefreshToken) {
+        t.Errorf("RotateAuthSession() on a revoked session error = %v, want ErrInvalidRefreshToken", err)
+    }
+    if revoked, err := IsTokenRevoked(ctx, phone.ID); err != nil || !revoked {
+        t.Errorf("IsTokenRevoked(phone) = %v, %v, want true", revoked, err)
+    }
+}
+
+func TestRevokeAllAuthSessions(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    phone, err := StartAuthSession(ctx, "user", "phone")
+    if err != nil {
+        t.Fatalf("StartAuthSession() error = %v", err)
+    }
+    tablet, err := StartAuthSession(ctx, "user", "tablet")
+    if err != nil {
+        t.Fatalf("StartAuthSession() error = %v", err)
+    }
+    if err := RevokeAuthSession(ctx, "user", phone.ID, models.AuthSessionRevokedByUser); err != nil {
+        t.Fatalf("RevokeAuthSession() error = %v", err)
+    }
+
+    revokedIDs, err := RevokeAllAuthSessions(ctx, "user", models.AuthSessionRevokedLogout)
+    if err != nil {
+        t.Fatalf("RevokeAllAuthSessions() error = %v", err)
+    }
+    if len(revokedIDs) != 1 || revokedIDs[0] != tablet.ID {
+        t.Errorf("revoked sessions = %v, want [%s]", revokedIDs, tablet.ID)
+    }
+    if sessions, _ := ListAuthSessions(ctx, "user", ""); len(sessions) != 0 {
+        t.Errorf("active sessions after revoking all = %+v, want none", sessions)
+    }
+}
+
+func TestLogout(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    session, err := StartAuthSession(ctx, "user", "phone")
+    if err != nil {
+        t.Fatalf("StartAuthSession() error = %v", err)
+    }
+
+    if err := Logout(ctx, "user", session.ID, "access-token", time.Now().Add(time.Hour)); err != nil {
+        t.Fatalf("Logout() error = %v", err)
+    }
+    for _, id := range []string{"access-token", session.ID} {
+        if revoked, err := IsTokenRevoked(ctx, id); err != nil || !revoked {
+            t.Errorf("IsTokenRevoked(%s) = %v, %v, want true", id, revoked, err)
+        }
+    }
+
+    if err := RevokeAccessToken(ctx, "user", "expired-token", time.Now().Add(-time.Minute)); err != nil {
+        t.Fatalf("RevokeAccessToken() error = %v", err)
+    }
+    if revoked, err := IsTokenRevoked(ctx, "expired-token"); err != nil || revoked {
+        t.Errorf("IsTokenRevoked(expired-token) = %v, %v, want false once the token has expired", revoked, err)
+    }
+    if err := Logout(ctx, "user", "missing", "", time.Time{}); err != nil {
+        t.Errorf("Logout() of an unknown session error = %v, want nil", err)
+    }
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "slices"
+    "strings"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// Errors returned when managing child profiles.
+var (
+    ErrInvalidChildProfile = errors.New("invalid child profile")
+    ErrChildProfileLimit   = errors.New("child profile limit reached")
+)
+
+// Limits on the child profiles of a guardian account.
+const (
+    maxChildProfiles          = 10
+    maxChildProfileNameLength = 50
+)
+
+// checkChildProfile trims the profile's name and rejects an empty or overlong name or an unknown age group.
+func checkChildProfile(profile *models.ChildProfile) error {
+    profile.Name = strings.TrimSpace(profile.Name)
+    if profile.Name == "" || len(profile.Name) > maxChildProfileNameLength {
+        return fmt.Errorf("%w: name must be 1 to %d characters", ErrInvalidChildProfile, maxChildProfileNameLength)
+    }
+    if profile.AgeGroup != "" && !slices.Contains(screeningAgeGroups, profile.AgeGroup) {
+        return fmt.Errorf("%w: %s", ErrInvalidAgeGroup, profile.AgeGroup)
+    }
+    return nil
+}
+
+// CreateChildProfile adds a child profile to a guardian account. The child starts with the
+// guardian's timezone so their daily progress is counted in the same days.
+func CreateChildProfile(ctx context.Context, guardianID string, profile models.ChildProfile) (models.ChildProfile, error) {
+    if err := checkChildProfile(&profile); err != nil {
+        return models.ChildProfile{}, err
+    }
+    existing, err := repo.ListChildProfiles(ctx, guardianID)
+    if err != nil {
+        return models.ChildProfile{}, fmt.Errorf("failed to retrieve child profiles: %w", err)
+    }
+    if len(existing) >= maxChildProfiles {
+        return models.ChildProfile{}, fmt.Errorf("%w: at most %d profiles per account", ErrChildProfileLimit, maxChildProfiles)
+    }
+
+    now := time.Now().UTC()
+    profile.GuardianID = guardianID
+    profile.CreatedAt = now
+    profile.UpdatedAt = now
+    profile.ID, err = repo.CreateChildProfile(ctx, profile)
+    if err != nil {
+        return models.ChildProfile{}, fmt.Errorf("failed to create child profile: %w", err)
+    }
+
+    settings, err := GetUserSettings(ctx, guardianID)
+    if err != nil {
+        return models.ChildProfile{}, err
+    }
+    if settings.Timezone != "" {
+        if err := repo.SaveUserSettings(ctx, profile.ID, settings); err != nil {
+            return models.ChildProfile{}, fmt.Errorf("failed to save child profile settings: %w", err)
+        }
+    }
+
+    log.Printf("Created child profile %s for guardianID: %s", profile.ID, guardianID)
+    return profile, nil
+}
+
+// ListChildProfiles retrieves the child profiles of a guardian account, oldest first.
+func ListChildProfiles(ctx context.Context, guardianID string) ([]models.ChildProfile, error) {
+    profiles, err := repo.ListChildProfiles(ctx, guardianID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve child profiles: %w", err)
+    }
+    return profiles, nil
+}
+
+// UpdateChildProfile replaces the name and age group of one of the guardian's child profiles.
+func UpdateChildProfile(ctx context.Context, guardianID, profileID string, changes models.ChildProfile) (models.ChildProfile, error) {
+    if err := checkChildProfile(&changes); err != nil {
+        return models.ChildProfile{}, err
+    }
+    profile, err := repo.UpdateChildProfile(ctx, guardianID, profileID, func(profile *models.ChildProfile) error {
+        profile.Name = changes.Name
+        profile.AgeGroup = changes.AgeGroup
+        profile.UpdatedAt = time.Now().UTC()
+        return nil
+    })
+    if err != nil {
+        return models.ChildProfile{}, fmt.Errorf("failed to update child profile: %w", err)
+    }
+
+    log.Printf("Updated child profile %s for guardianID: %s", profileID, guardianID)
+    return profile, nil
+}
+
+// IsChildProfile reports whether profileID is one of the guardian's child profiles.
+func IsChildProfile(ctx context.Context, guardianID, profileID string) (bool, error) {
+    _, err := repo.GetChildProfile(ctx, guardianID, profileID)
+    if errors.Is(err, repository.ErrNotFound) {
+        return false, nil
+    }
+    if err != nil {
+        return false, fmt.Errorf("failed to retrieve child profile: %w", err)
+    }
+    return true, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "sort"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// correctRunToStepUp is the number of consecutive correct answers that raises the difficulty.
+const correctRunToStepUp = 3
+
+// adaptiveSetSize caps how many questions an adaptive set holds.
+const adaptiveSetSize = 10
+
+// difficultyHistorySize is how many of the user's most recent answers the target difficulty is
+// derived from.
+const difficultyHistorySize = 20
+
+// checkDifficulty defaults an unset difficulty and rejects one outside the allowed range.
+func checkDifficulty(difficulty int) (int, error) {
+    if difficulty == 0 {
+        return models.DefaultDifficulty, nil
+    }
+    if difficulty < models.MinDifficulty || difficulty > models.MaxDifficulty {
+        return 0, fmt.Errorf("%w: difficulty must be between %d and %d", ErrInvalidQuestion, models.MinDifficulty, models.MaxDifficulty)
+    }
+    return difficulty, nil
+}
+
+// questionDifficulty returns a question's difficulty, treating questions stored without one as
+// the default level.
+func questionDifficulty(q models.TherapyQuestion) int {
+    if q.Difficulty == 0 {
+        return models.DefaultDifficulty
+    }
+    return q.Difficulty
+}
+
+// AdaptTherapyQuestions selects the questions of a category to serve the user next. The target
+// difficulty starts from the last answered question's level, steps up after a run of correct
+// answers and steps down after a mistake, counting every answer in the user's history, repeats
+// included. Questions closest to the target come first, with questions not yet answered correctly
+// ahead of mastered ones.
+func AdaptTherapyQuestions(ctx context.Context, userID, questionType, category string, questions []models.TherapyQuestion) ([]models.TherapyQuestion, int, error) {
+    history, err := repo.ListTherapyAnswerHistory(ctx, userID, questionType, category, difficultyHistorySize)
+    if err != nil {
+        return nil, 0, fmt.Errorf("failed to fetch answer history: %w", err)
+    }
+    submissions, err := repo.ListTherapySubmissions(ctx, userID, questionType, category)
+    if err != nil && !errors.Is(err, repository.ErrNotFound) {
+        return nil, 0, fmt.Errorf("failed to fetch submissions: %w", err)
+    }
+
+    difficulties := make(map[string]int, len(questions))
+    for _, q := range questions {
+        difficulties[q.ID] = questionDifficulty(q)
+    }
+    target := targetDifficulty(history, difficulties)
+
+    mastered := make(map[string]bool, len(submissions))
+    for _, sub := range submissions {
+        mastered[sub.QuestionID] = sub.CorrectAnswers > 0
+    }
+
+    adapted := append([]models.TherapyQuestion{}, questions...)
+    sort.SliceStable(adapted, func(i, j int) bool {
+        di, dj := difficultyGap(difficulties[adapted[i].ID], target), difficultyGap(difficulties[adapted[j].ID], target)
+        if di != dj {
+            return di < dj
+        }
+        if mastered[adapted[i].ID] != mastered[adapted[j].ID] {
+            return !mastered[adapted[i].ID]
+        }
+        return adapted[i].ID < adapted[j].ID
+    })
+    if len(adapted) > adaptiveSetSize {
+        adapted = adapted[:adaptiveSetSize]
+    }
+
+    log.Printf("Adapted %d therapy questions for userID: %s, type: %s, category: %s, target difficulty: %d", len(adapted), userID, questionType, category, target)
+    return adapted, target, nil
+}
+
+// targetDifficulty derives the next difficulty level from the user's answer history, oldest first.
+// Users without answers start at the easiest level.
+func targetDifficulty(history []models.TherapySubmissionRecord, difficulties map[string]int) int {
+    if len(history) == 0 {
+        return models.MinDifficulty
+    }
+
+    last := history[len(history)-1]
+    target, ok := difficulties[last.QuestionID]
+    if !ok {
+        target = models.DefaultDifficulty
+    }
+
+    if last.CorrectAnswers == 0 {
+        return max(target-1, models.MinDifficulty)
+    }
+    run := 0
+    for i := len(history) - 1; i >= 0 && history[i].CorrectAnswers > 0; i-- {
+        run++
+    }
+    if run >= correctRunToStepUp {
+        return min(target+1, models.MaxDifficulty)
+    }
+    return target
+}
+
+// difficultyGap returns how far a difficulty is from the target.
+func difficultyGap(difficulty, target int) int {
+    if difficulty > target {
+        return difficulty - target
+    }
+    return target - difficulty
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestTargetDifficulty(t *testing.T) {
+    difficulties := map[string]int{"easy": 1, "medium": 3, "hard": 5}
+    answer := func(questionID string, correct bool) models.TherapySubmissionRecord {
+        record := models.TherapySubmissionRecord{QuestionID: questionID}
+        if correct {
+            record.CorrectAnswers = 1
+        }
+        return record
+    }
+
+    tests := []struct {
+        name    string
+        history []models.TherapySubmissionRecord
+        want    int
+    }{
+        {"no answers", nil, models.MinDifficulty},
+        {"last answer correct", []models.TherapySubmissionRecord{answer("medium", true)}, 3},
+        {"mistake steps down", []models.TherapySubmissionRecord{answer("hard", true), answer("medium", false)}, 2},
+        {"mistake at easiest level", []models.TherapySubmissionRecord{answer("easy", false)}, models.MinDifficulty},
+        {"correct run steps up", []models.TherapySubmissionRecord{answer("easy", false), answer("medium", true), answer("medium", true), answer("medium", true)}, 4},
+        {"run broken by mistake", []models.TherapySubmissionRecord{answer("medium", true), answer("medium", false), answer("medium", true), answer("medium", true)}, 3},
+        {"run at hardest level", []models.TherapySubmissionRecord{answer("hard", true), answer("hard", true), answer("hard", true)}, models.MaxDifficulty},
+        {"deleted question", []models.TherapySubmissionRecord{answer("deleted", true)}, models.DefaultDifficulty},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := targetDifficulty(tt.history, difficulties); got != tt.want {
+                t.Errorf("targetDifficulty() = %d, want %d", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestAdaptTherapyQuestionsCountsRepeatedAnswers(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    question := saveTherapyQuestion(t, "visual", "letter_recognition", "b")
+
+    // Answering the same question correctly three times in a row is a correct run.
+    for i := 0; i < correctRunToStepUp; i++ {
+        submission := models.TherapySubmission{QuestionID: question.ID, Answer: "b"}
+        if _, err := SaveTherapyResult(ctx, "user", submission, "", time.UTC); err != nil {
+            t.Fatalf("SaveTherapyResult() error = %v", err)
+        }
+    }
+    question.Difficulty = models.DefaultDifficulty
+
+    _, target, err := AdaptTherapyQuestions(ctx, "user", "visual", "letter_recognition", []models.TherapyQuestion{question})
+    if err != nil {
+        t.Fatalf("AdaptTherapyQuestions() error = %v", err)
+    }
+    if want := models.DefaultDifficulty + 1; target != want {
+        t.Errorf("target difficulty = %d, want %d", target, want)
+    }
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "math"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// ErrInvalidDailyGoal is returned for a daily goal with an unknown metric or an out-of-range target.
+var ErrInvalidDailyGoal = errors.New("invalid daily goal")
+
+// defaultDailyGoal applies to users who have not been given a goal of their own.
+var defaultDailyGoal = models.DailyGoal{Metric: models.GoalMetricSessions, Target: 5}
+
+// maxDailyGoalTargets caps the target a daily goal can set for each metric.
+var maxDailyGoalTargets = map[string]int{
+    models.GoalMetricSessions:       50,
+    models.GoalMetricMinutes:        480,
+    models.GoalMetricCorrectAnswers: 1000,
+}
+
+// GetDailyGoal retrieves the user's daily goal, returning the default goal for users without one.
+func GetDailyGoal(ctx context.Context, userID string) (models.DailyGoal, error) {
+    user, err := repo.GetUser(ctx, userID)
+    if errors.Is(err, repository.ErrNotFound) {
+        return defaultDailyGoal, nil
+    }
+    if err != nil {
+        return models.DailyGoal{}, fmt.Errorf("failed to retrieve user data: %w", err)
+    }
+    if user.DailyGoal == nil || user.DailyGoal.Target <= 0 {
+        return defaultDailyGoal, nil
+    }
+    return *user.DailyGoal, nil
+}
+
+// UpdateDailyGoal validates and stores the daily goal of userID on behalf of setBy, who is the
+// user themselves, a user granted user-goals:manage or one of the learner's assigned therapists.
+// It applies from the next progress update.
+func UpdateDailyGoal(ctx context.Context, userID, setBy string, goal models.DailyGoal) (models.DailyGoal, error) {
+    maxTarget, ok := maxDailyGoalTargets[goal.Metric]
+    if !ok {
+        return models.DailyGoal{}, fmt.Errorf("%w: unknown metric %q", ErrInvalidDailyGoal, goal.Metric)
+    }
+    if goal.Target < 1 || goal.Target > maxTarget {
+        return models.DailyGoal{}, fmt.Errorf("%w: target for %s must be between 1 and %d", ErrInvalidDailyGoal, goal.Metric, maxTarget)
+    }
+    goal.SetBy = setBy
+    goal.UpdatedAt = time.Now().UTC()
+
+    if err := repo.SaveDailyGoal(ctx, userID, goal); err != nil {
+        return models.DailyGoal{}, fmt.Errorf("failed to save daily goal: %w", err)
+    }
+
+    log.Printf("Updated daily goal for userID: %s, metric: %s, target: %d, setBy: %s", userID, goal.Metric, goal.Target, setBy)
+    return goal, nil
+}
+
+// goalAmount returns how much of progress counts toward a goal measured in metric.
+func goalAmount(progress models.DailyProgress, metric string) int {
+    switch metric {
+    case models.GoalMetricMinutes:
+        return progress.SecondsSpent / 60
+    case models.GoalMetricCorrectAnswers:
+        return progress.CorrectAnswers
+    default:
+        return progress.TherapyCount
+    }
+}
+
+// measureProgress fills in the achieved amount and completion percentage of progress. The goal
+// stored with the day is used when present; days recorded before goals were stored use goal.
+func measureProgress(progress *models.DailyProgress, goal models.DailyGoal) {
+    if progress.Goal.Target <= 0 {
+        progress.Goal = goal
+    }
+    progress.Achieved = goalAmount(*progress, progress.Goal.Metric)
+    percent := float64(progress.Achieved) / float64(progress.Goal.Target) * 100
+    progress.CompletionPercent = math.Round(math.Min(percent, 100)*100) / 100
+}
+
+// goalMet reports whether a measured day reached its goal.
+func goalMet(progress models.DailyProgress) bool {
+    return progress.StreakAchieved || progress.Achieved >= progress.Goal.Target
+}
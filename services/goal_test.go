@@ -0,0 +1,64 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestUpdateDailyGoal(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    goal, err := GetDailyGoal(ctx, "user")
+    if err != nil || goal != defaultDailyGoal {
+        t.Fatalf("GetDailyGoal() for a new user = %+v, %v, want the default goal", goal, err)
+    }
+
+    if _, err := UpdateDailyGoal(ctx, "user", "therapist", models.DailyGoal{Metric: models.GoalMetricMinutes, Target: 20}); err != nil {
+        t.Fatalf("UpdateDailyGoal() error = %v", err)
+    }
+    goal, err = GetDailyGoal(ctx, "user")
+    if err != nil || goal.Metric != models.GoalMetricMinutes || goal.Target != 20 || goal.SetBy != "therapist" {
+        t.Errorf("GetDailyGoal() = %+v, %v, want 20 minutes set by the therapist", goal, err)
+    }
+}
+
+func TestUpdateDailyGoalValidation(t *testing.T) {
+    useMemoryRepository(t)
+    tests := []models.DailyGoal{
+        {Metric: "pages", Target: 1},
+        {Metric: models.GoalMetricSessions, Target: 0},
+        {Metric: models.GoalMetricMinutes, Target: maxDailyGoalTargets[models.GoalMetricMinutes] + 1},
+    }
+    for _, goal := range tests {
+        if _, err := UpdateDailyGoal(context.Background(), "user", "user", goal); !errors.Is(err, ErrInvalidDailyGoal) {
+            t.Errorf("UpdateDailyGoal(%+v) error = %v, want ErrInvalidDailyGoal", goal, err)
+        }
+    }
+}
+
+func TestMeasureProgress(t *testing.T) {
+    tests := []struct {
+        name        string
+        progress    models.DailyProgress
+        wantTarget  int
+        wantAmount  int
+        wantPercent float64
+    }{
+        {"default goal", models.DailyProgress{TherapyCount: 2}, 5, 2, 40},
+        {"stored goal", models.DailyProgress{CorrectAnswers: 2, Goal: models.DailyGoal{Metric: models.GoalMetricCorrectAnswers, Target: 3}}, 3, 2, 66.67},
+        {"minutes", models.DailyProgress{SecondsSpent: 150, Goal: models.DailyGoal{Metric: models.GoalMetricMinutes, Target: 2}}, 2, 2, 100},
+        {"capped at 100", models.DailyProgress{TherapyCount: 12}, 5, 12, 100},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            progress := tt.progress
+            measureProgress(&progress, defaultDailyGoal)
+            if progress.Goal.Target != tt.wantTarget || progress.Achieved != tt.wantAmount || progress.CompletionPercent != tt.wantPercent {
+                t.Errorf("measureProgress() = target %d, achieved %d, %.2f%%, want %d, %d, %.2f%%", progress.Goal.Target, progress.Achieved, progress.CompletionPercent, tt.wantTarget, tt.wantAmount, tt.wantPercent)
+            }
+        })
+    }
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+    "fmt"
+    "strings"
+    "unicode"
+    "unicode/utf8"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "golang.org/x/text/cases"
+    "golang.org/x/text/runes"
+    "golang.org/x/text/transform"
+    "golang.org/x/text/unicode/norm"
+)
+
+// maxAllowedEditDistance caps the edit-distance tolerance a question may declare.
+const maxAllowedEditDistance = 3
+
+// defaultNormalizers are applied when a question does not configure its own.
+var defaultNormalizers = []string{models.NormalizeUnicode, models.NormalizeCase, models.NormalizePunctuation}
+
+// normalizers maps each normalizer name to the function that applies it.
+var normalizers = map[string]func(string) string{
+    models.NormalizeCase:        foldCase,
+    models.NormalizeUnicode:     stripDiacritics,
+    models.NormalizePunctuation: stripPunctuation,
+}
+
+// checkMatchOptions reports an error if the options name an unknown normalizer or an
+// out-of-range tolerance.
+func checkMatchOptions(opts *models.MatchOptions) error {
+    if opts == nil {
+        return nil
+    }
+    for _, name := range opts.Normalizers {
+        if _, ok := normalizers[name]; !ok && name != models.NormalizeNone {
+            return fmt.Errorf("unknown normalizer %q", name)
+        }
+    }
+    if opts.MaxEditDistance < 0 || opts.MaxEditDistance > maxAllowedEditDistance {
+        return fmt.Errorf("maxEditDistance must be between 0 and %d", maxAllowedEditDistance)
+    }
+    return nil
+}
+
+// NormalizeAnswer applies the configured normalizers to a string answer and collapses whitespace.
+func NormalizeAnswer(answer string, opts *models.MatchOptions) string {
+    names := defaultNormalizers
+    if opts != nil && len(opts.Normalizers) > 0 {
+        names = opts.Normalizers
+    }
+    for _, name := range names {
+        if normalize, ok := normalizers[name]; ok {
+            answer = normalize(answer)
+        }
+    }
+    return strings.Join(strings.Fields(answer), " ")
+}
+
+// MatchString compares a string answer with the correct answer, reporting the strongest
+// match quality the options allow.
+func MatchString(answer, correctAnswer string, opts *models.MatchOptions) models.MatchResult {
+    normalized := NormalizeAnswer(answer, opts)
+    result := models.MatchResult{Quality: models.MatchNone, Normalized: normalized}
+    if answer == correctAnswer {
+        result.Quality = models.MatchExact
+        return result
+    }
+
+    expected := NormalizeAnswer(correctAnswer, opts)
+    result.Distance = editDistance(normalized, expected)
+    switch {
+    case result.Distance == 0:
+        result.Quality = models.MatchNormalized
+    case opts != nil && normalized != "" && result.Distance <= fuzzyTolerance(opts, expected):
+        result.Quality = models.MatchFuzzy
+    case opts != nil && opts.Phonetic && normalized != "" && phoneticKey(normalized) == phoneticKey(expected):
+        result.Quality = models.MatchPhonetic
+    }
+    return result
+}
+
+// fuzzyTolerance returns the edit distance accepted for expected, which stays below the length of
+// expected so an answer cannot match by replacing every letter.
+func fuzzyTolerance(opts *models.MatchOptions, expected string) int {
+    return min(opts.MaxEditDistance, utf8.RuneCountInString(expected)-1)
+}
+
+// foldCase applies Unicode case folding.
+func foldCase(s string) string {
+    return cases.Fold().String(s)
+}
+
+// stripDiacritics decomposes the string and drops combining marks, so "é" matches "e".
+func stripDiacritics(s string) string {
+    t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+    result, _, err := transform.String(t, s)
+    if err != nil {
+        return norm.NFC.String(s)
+    }
+    return result
+}
+
+// stripPunctuation removes punctuation and symbols.
+func stripPunctuation(s string) string {
+    return strings.Map(func(r rune) rune {
+        if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+            return -1
+        }
+        return r
+    }, s)
+}
+
+// editDistance returns the Levenshtein distance between a and b, counted in runes.
+func editDistance(a, b string) int {
+    ra, rb := []rune(a), []rune(b)
+    prev := make([]int, len(rb)+1)
+    curr := make([]int, len(rb)+1)
+    for j := range prev {
+        prev[j] = j
+    }
+    for i := 1; i <= len(ra); i++ {
+        curr[0] = i
+        for j := 1; j <= len(rb); j++ {
+            cost := 1
+            if ra[i-1] == rb[j-1] {
+                cost = 0
+            }
+            curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+        }
+        prev, curr = curr, prev
+    }
+    return prev[len(rb)]
+}
+
+// soundexCodes groups letters that sound alike, following the Soundex classes.
+var soundexCodes = map[rune]byte{
+    'b': '1', 'f': '1', 'p': '1', 'v': '1',
+    'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+    'd': '3', 't': '3',
+    'l': '4',
+    'm': '5', 'n': '5',
+    'r': '6',
+}
+
+// phoneticKey returns a Soundex-style key for each word of an already normalized string.
+func phoneticKey(s string) string {
+    words := strings.Fields(s)
+    keys := make([]string, len(words))
+    for i, word := range words {
+        var key strings.Builder
+        var last byte
+        for j, r := range strings.ToLower(word) {
+            code := soundexCodes[r]
+            if j == 0 {
+                key.WriteRune(r)
+                last = code
+                continue
+            }
+            if code != 0 && code != last {
+                key.WriteByte(code)
+            }
+            if r != 'h' && r != 'w' {
+                last = code
+            }
+        }
+        keys[i] = key.String()
+    }
+    return strings.Join(keys, " ")
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "sort"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// Normalized score thresholds used to classify modalities and categories.
+const (
+    strengthThreshold = 0.75
+    weaknessThreshold = 0.5
+    riskThreshold     = 0.4
+)
+
+// scoreLevel classifies a normalized score as a strength, weakness or average.
+func scoreLevel(score float64) string {
+    switch {
+    case score >= strengthThreshold:
+        return models.LevelStrength
+    case score < weaknessThreshold:
+        return models.LevelWeakness
+    default:
+        return models.LevelAverage
+    }
+}
+
+// normalizedScore divides a score by the number of questions it covers, returning 0 when there are none.
+func normalizedScore(score float64, totalQuestions int) float64 {
+    if totalQuestions == 0 {
+        return 0
+    }
+    return score / float64(totalQuestions)
+}
+
+// ComputeLearningProfile derives the user's learning-style profile from their latest complete
+// assessment attempt of each type and stores it on the user. Partial attempts are ignored.
+func ComputeLearningProfile(ctx context.Context, userID string) (models.LearningProfile, error) {
+    attempts, err := repo.ListAssessmentAttempts(ctx, userID, "")
+    if err != nil {
+        return models.LearningProfile{}, fmt.Errorf("failed to retrieve assessment attempts: %w", err)
+    }
+
+    latest := make(map[string]models.AssessmentAttempt)
+    for _, attempt := range attempts {
+        if !attempt.Partial {
+            latest[attempt.Type] = attempt
+        }
+    }
+    profile := buildLearningProfile(latest, time.Now().UTC())
+
+    if err := repo.SaveLearningProfile(ctx, userID, profile); err != nil {
+        return models.LearningProfile{}, fmt.Errorf("failed to save learning profile: %w", err)
+    }
+
+    log.Printf("Computed learning profile for userID: %s, dominant modality: %s, risk areas: %d", userID, profile.DominantModality, len(profile.RiskAreas))
+    return profile, nil
+}
+
+// GetLearningProfile returns the learning profile stored on the user, computing it if none is stored yet.
+func GetLearningProfile(ctx context.Context, userID string) (models.LearningProfile, error) {
+    user, err := repo.GetUser(ctx, userID)
+    if err == nil && user.LearningProfile != nil {
+        return *user.LearningProfile, nil
+    }
+    if err != nil && !errors.Is(err, repository.ErrNotFound) {
+        return models.LearningProfile{}, fmt.Errorf("failed to retrieve user: %w", err)
+    }
+    return ComputeLearningProfile(ctx, userID)
+}
+
+// refreshLearningProfile recomputes the user's profile after new assessment attempts, logging
+// rather than failing when it cannot.
+func refreshLearningProfile(ctx context.Context, userID string) {
+    if _, err := ComputeLearningProfile(ctx, userID); err != nil {
+        log.Printf("Failed to refresh learning profile for userID %s: %v", userID, err)
+    }
+}
+
+// buildLearningProfile scores each question type and its categories from the latest attempt per type.
+func buildLearningProfile(latest map[string]models.AssessmentAttempt, computedAt time.Time) models.LearningProfile {
+    profile := models.LearningProfile{
+        Modalities: make([]models.ModalityProfile, 0, len(questionTypes)),
+        Categories: []models.CategoryProfile{},
+        Strengths:  []string{},
+        Weaknesses: []string{},
+        RiskAreas:  []models.CategoryProfile{},
+        Complete:   true,
+        ComputedAt: computedAt,
+    }
+
+    bestScore := -1.0
+    for _, questionType := range questionTypes {
+        attempt, ok := latest[questionType]
+        if !ok || attempt.TotalQuestions == 0 {
+            profile.Modalities = append(profile.Modalities, models.ModalityProfile{Type: questionType})
+            profile.Complete = false
+            continue
+        }
+
+        modality := models.ModalityProfile{
+            Type:           questionType,
+            Assessed:       true,
+            CorrectAnswers: attempt.CorrectAnswers,
+            TotalQuestions: attempt.TotalQuestions,
+            Score:          normalizedScore(attempt.Score, attempt.TotalQuestions),
+        }
+        modality.Level = scoreLevel(modality.Score)
+        profile.Modalities = append(profile.Modalities, modality)
+
+        switch modality.Level {
+        case models.LevelStrength:
+            profile.Strengths = append(profile.Strengths, questionType)
+        case models.LevelWeakness:
+            profile.Weaknesses = append(profile.Weaknesses, questionType)
+        }
+        if modality.Score > bestScore {
+            bestScore = modality.Score
+            profile.DominantModality = questionType
+        }
+
+        for _, c := range attempt.Categories {
+            category := models.CategoryProfile{
+                Type:           questionType,
+                Category:       c.Category,
+                CorrectAnswers: c.CorrectAnswers,
+                TotalQuestions: c.TotalQuestions,
+                Score:          normalizedScore(c.Score, c.TotalQuestions),
+            }
+            category.Level = scoreLevel(category.Score)
+            profile.Categories = append(profile.Categories, category)
+            if category.Score < riskThreshold {
+                profile.RiskAreas = append(profile.RiskAreas, category)
+            }
+        }
+    }
+
+    sort.SliceStable(profile.RiskAreas, func(i, j int) bool {
+        return profile.RiskAreas[i].Score < profile.RiskAreas[j].Score
+    })
+    return profile
+}
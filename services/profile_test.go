@@ -0,0 +1,127 @@
+package services
+
+import (
+    "context"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestBuildLearningProfile(t *testing.T) {
+    latest := map[string]models.AssessmentAttempt{
+        "visual": {
+            Type:           "visual",
+            CorrectAnswers: 4,
+            Score:          4,
+            TotalQuestions: 5,
+            Categories: []models.CategoryScore{
+                {Category: "letter_recognition", CorrectAnswers: 3, Score: 3, TotalQuestions: 3},
+                {Category: "word_recognition", CorrectAnswers: 1, Score: 1, TotalQuestions: 2},
+            },
+        },
+        "auditory": {
+            Type:           "auditory",
+            CorrectAnswers: 1,
+            Score:          1.5,
+            TotalQuestions: 5,
+            Categories: []models.CategoryScore{
+                {Category: "phoneme", CorrectAnswers: 1, Score: 1, TotalQuestions: 4},
+                {Category: "rhyme", Score: 0.5, TotalQuestions: 1},
+            },
+        },
+        "tactile": {Type: "tactile"},
+    }
+
+    profile := buildLearningProfile(latest, time.Now().UTC())
+
+    if profile.Complete {
+        t.Error("profile is complete with kinesthetic and tactile unassessed")
+    }
+    if profile.DominantModality != "visual" {
+        t.Errorf("dominant modality = %q, want visual", profile.DominantModality)
+    }
+    if !slices.Equal(profile.Strengths, []string{"visual"}) || !slices.Equal(profile.Weaknesses, []string{"auditory"}) {
+        t.Errorf("strengths = %v, weaknesses = %v, want [visual] and [auditory]", profile.Strengths, profile.Weaknesses)
+    }
+
+    levels := make(map[string]string, len(profile.Modalities))
+    for _, modality := range profile.Modalities {
+        levels[modality.Type] = modality.Level
+        if modality.Assessed != (modality.Level != "") {
+            t.Errorf("modality %s assessed = %v with level %q", modality.Type, modality.Assessed, modality.Level)
+        }
+    }
+    wantLevels := map[string]string{"visual": models.LevelStrength, "auditory": models.LevelWeakness, "kinesthetic": "", "tactile": ""}
+    for questionType, want := range wantLevels {
+        if levels[questionType] != want {
+            t.Errorf("modality %s level = %q, want %q", questionType, levels[questionType], want)
+        }
+    }
+
+    // Risk areas are the categories scoring below 0.4, weakest first.
+    var risks []string
+    for _, risk := range profile.RiskAreas {
+        risks = append(risks, risk.Type+"/"+risk.Category)
+    }
+    if want := []string{"auditory/phoneme"}; !slices.Equal(risks, want) {
+        t.Errorf("risk areas = %v, want %v", risks, want)
+    }
+    if len(profile.Categories) != 4 {
+        t.Errorf("categories = %d, want 4", len(profile.Categories))
+    }
+}
+
+func TestBuildLearningProfileRiskOrder(t *testing.T) {
+    latest := map[string]models.AssessmentAttempt{
+        "kinesthetic": {
+            Type:           "kinesthetic",
+            Score:          0.6,
+            TotalQuestions: 6,
+            Categories: []models.CategoryScore{
+                {Category: "tracing", Score: 0.6, TotalQuestions: 2},
+                {Category: "sorting", TotalQuestions: 4},
+            },
+        },
+    }
+
+    profile := buildLearningProfile(latest, time.Now().UTC())
+
+    var risks []string
+    for _, risk := range profile.RiskAreas {
+        risks = append(risks, risk.Category)
+    }
+    if want := []string{"sorting", "tracing"}; !slices.Equal(risks, want) {
+        t.Errorf("risk areas = %v, want %v", risks, want)
+    }
+}
+
+func TestComputeLearningProfileUsesLatestAttempt(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    now := time.Now().UTC()
+    for i, score := range []float64{1, 0} {
+        answers := []models.AssessmentAnswer{{QuestionID: "q1", Category: "letters", Correct: score == 1, Score: score}}
+        attempt := newAssessmentAttempt("user", "visual", "session", answers, nil, 1, now.Add(time.Duration(i)*time.Minute))
+        if _, err := r.CreateAssessmentAttempt(ctx, attempt); err != nil {
+            t.Fatalf("CreateAssessmentAttempt() error = %v", err)
+        }
+    }
+
+    profile, err := ComputeLearningProfile(ctx, "user")
+    if err != nil {
+        t.Fatalf("ComputeLearningProfile() error = %v", err)
+    }
+    if profile.Modalities[0].Type != "visual" || profile.Modalities[0].Score != 0 {
+        t.Errorf("visual modality = %+v, want the latest attempt's score 0", profile.Modalities[0])
+    }
+
+    stored, err := GetLearningProfile(ctx, "user")
+    if err != nil {
+        t.Fatalf("GetLearningProfile() error = %v", err)
+    }
+    if !stored.ComputedAt.Equal(profile.ComputedAt) {
+        t.Errorf("stored profile computed at %v, want %v", stored.ComputedAt, profile.ComputedAt)
+    }
+}
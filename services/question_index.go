@@ -0,0 +1,92 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// indexQuestion records a question's location. Failures are only logged; the question cannot be
+// looked up by ID until the index is rebuilt.
+func indexQuestion(ctx context.Context, kind, questionID, questionType, category string) {
+    err := repo.SetQuestionLocation(ctx, models.QuestionLocation{
+        QuestionID: questionID,
+        Kind:       kind,
+        Type:       questionType,
+        Category:   category,
+    })
+    if err != nil {
+        log.Printf("Failed to index %s question %s, rebuild the question index to repair it: %v", kind, questionID, err)
+    }
+}
+
+// unindexQuestion removes a question's location from the index.
+func unindexQuestion(ctx context.Context, questionID string) {
+    if err := repo.DeleteQuestionLocation(ctx, questionID); err != nil {
+        log.Printf("Failed to remove question %s from index: %v", questionID, err)
+    }
+}
+
+// lookupQuestionLocation resolves a question's location from the index. Questions that are not
+// indexed as the given kind are reported as not found.
+func lookupQuestionLocation(ctx context.Context, kind, questionID string) (models.QuestionLocation, error) {
+    location, err := repo.GetQuestionLocation(ctx, questionID)
+    if err != nil {
+        return models.QuestionLocation{}, err
+    }
+    if location.Kind != kind {
+        return models.QuestionLocation{}, repository.ErrNotFound
+    }
+    return location, nil
+}
+
+// FindAssessmentQuestion locates an assessment question by ID using the question index. Questions
+// missing from the index are not found until the index is rebuilt.
+func FindAssessmentQuestion(ctx context.Context, questionID string) (models.AssessmentQuestion, error) {
+    location, err := lookupQuestionLocation(ctx, models.QuestionKindAssessment, questionID)
+    if err != nil {
+        return models.AssessmentQuestion{}, err
+    }
+    q, err := repo.GetAssessmentQuestion(ctx, location.Type, location.Category, questionID)
+    if errors.Is(err, repository.ErrNotFound) {
+        unindexQuestion(ctx, questionID)
+    }
+    return q, err
+}
+
+// FindTherapyQuestion locates a therapy question by ID using the question index. Questions
+// missing from the index are not found until the index is rebuilt.
+func FindTherapyQuestion(ctx context.Context, questionID string) (models.TherapyQuestion, error) {
+    location, err := lookupQuestionLocation(ctx, models.QuestionKindTherapy, questionID)
+    if err != nil {
+        return models.TherapyQuestion{}, err
+    }
+    q, err := repo.GetTherapyQuestion(ctx, location.Type, location.Category, questionID)
+    if errors.Is(err, repository.ErrNotFound) {
+        unindexQuestion(ctx, questionID)
+    }
+    return q, err
+}
+
+// RebuildQuestionIndex regenerates the question index from the stored questions, reporting the
+// malformed question documents it left out.
+func RebuildQuestionIndex(ctx context.Context) (models.QuestionIndexRebuild, error) {
+    rebuild, err := repo.RebuildQuestionIndex(ctx)
+    if err != nil {
+        return models.QuestionIndexRebuild{}, fmt.Errorf("failed to rebuild question index: %w", err)
+    }
+
+    log.Printf("Rebuilt question index with %d questions, skipped %d malformed questions: %v", rebuild.IndexedQuestions, len(rebuild.SkippedQuestionIDs), rebuild.SkippedQuestionIDs)
+    return rebuild, nil
+}
+
+// logSkippedQuestions reports the malformed question documents a list call skipped.
+func logSkippedQuestions(skipped []string, kind, scope string) {
+    if len(skipped) > 0 {
+        log.Printf("Skipped %d malformed %s questions for %s: %v", len(skipped), kind, scope, skipped)
+    }
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+func TestFindTherapyQuestion(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    indexed := saveTherapyQuestion(t, "visual", "letter_recognition", "b")
+    if q, err := FindTherapyQuestion(ctx, indexed.ID); err != nil || q.Category != "letter_recognition" {
+        t.Fatalf("FindTherapyQuestion() = %+v, %v, want the indexed question", q, err)
+    }
+    if _, err := FindAssessmentQuestion(ctx, indexed.ID); !errors.Is(err, repository.ErrNotFound) {
+        t.Errorf("FindAssessmentQuestion() of a therapy question error = %v, want ErrNotFound", err)
+    }
+
+    // Questions written around the services are not found until the index is rebuilt.
+    unindexedID, err := r.CreateTherapyQuestion(ctx, models.TherapyQuestion{Type: "visual", Category: "word_recognition", CorrectAnswer: "bed"})
+    if err != nil {
+        t.Fatalf("CreateTherapyQuestion() error = %v", err)
+    }
+    if _, err := FindTherapyQuestion(ctx, unindexedID); !errors.Is(err, repository.ErrNotFound) {
+        t.Fatalf("FindTherapyQuestion() of an unindexed question error = %v, want ErrNotFound", err)
+    }
+    if _, err := RebuildQuestionIndex(ctx); err != nil {
+        t.Fatalf("RebuildQuestionIndex() error = %v", err)
+    }
+    if _, err := FindTherapyQuestion(ctx, unindexedID); err != nil {
+        t.Errorf("FindTherapyQuestion() after rebuilding the index error = %v", err)
+    }
+
+    // A stale entry is dropped once its question turns out to be gone.
+    if err := r.DeleteTherapyQuestion(ctx, indexed.Type, indexed.Category, indexed.ID); err != nil {
+        t.Fatalf("DeleteTherapyQuestion() error = %v", err)
+    }
+    if _, err := FindTherapyQuestion(ctx, indexed.ID); !errors.Is(err, repository.ErrNotFound) {
+        t.Errorf("FindTherapyQuestion() of a deleted question error = %v, want ErrNotFound", err)
+    }
+    if _, err := r.GetQuestionLocation(ctx, indexed.ID); !errors.Is(err, repository.ErrNotFound) {
+        t.Errorf("GetQuestionLocation() of a deleted question error = %v, want ErrNotFound", err)
+    }
+}
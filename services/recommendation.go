@@ -0,0 +1,179 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "math"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// Weights of the assessment and therapy signals in a recommendation's priority.
+const (
+    assessmentWeight = 0.6
+    therapyWeight    = 0.4
+)
+
+// unknownNeed is the need assumed for a signal the user has no data for yet.
+const unknownNeed = 0.5
+
+// accuracyWindow is how many of a category's most recent therapy answers its accuracy covers.
+const accuracyWindow = 20
+
+// riskBoosts raises the priority of assessment weaknesses for users screened at higher risk.
+var riskBoosts = map[string]float64{
+    "moderate": 0.1,
+    "high":     0.2,
+}
+
+// dailyPlanSizes sets how many categories a day's plan holds per screening risk level.
+var dailyPlanSizes = map[string]int{
+    "low":      3,
+    "moderate": 4,
+    "high":     5,
+}
+
+// defaultDailyPlanSize is used when the user has not completed a screening.
+const defaultDailyPlanSize = 3
+
+// RecommendTherapy ranks therapy categories for the user by combining their learning profile,
+// screening risk level and therapy accuracy, and returns the top-ranked categories as a daily plan
+// alongside the questions due for review today in loc.
+func RecommendTherapy(ctx context.Context, userID string, loc *time.Location) (models.TherapyPlan, error) {
+    profile, err := GetLearningProfile(ctx, userID)
+    if err != nil {
+        return models.TherapyPlan{}, err
+    }
+
+    riskLevel := ""
+    screening, err := repo.GetScreeningResult(ctx, userID)
+    if err == nil {
+        riskLevel = screening.RiskLevel
+    } else if !errors.Is(err, repository.ErrNotFound) {
+        return models.TherapyPlan{}, fmt.Errorf("failed to retrieve screening result: %w", err)
+    }
+
+    candidates := []models.TherapyRecommendation{}
+    for _, questionType := range questionTypes {
+        categories, err := repo.ListTherapyCategories(ctx, questionType)
+        if err != nil {
+            return models.TherapyPlan{}, fmt.Errorf("failed to retrieve therapy categories for type %s: %w", questionType, err)
+        }
+        for _, category := range categories {
+            submissions, err := repo.ListTherapySubmissions(ctx, userID, questionType, category.Category)
+            if err != nil && !errors.Is(err, repository.ErrNotFound) {
+                return models.TherapyPlan{}, fmt.Errorf("failed to fetch submissions: %w", err)
+            }
+            candidates = append(candidates, recommendCategory(profile, riskLevel, questionType, category, submissions))
+        }
+    }
+
+    sort.SliceStable(candidates, func(i, j int) bool {
+        return candidates[i].Priority > candidates[j].Priority
+    })
+    size, ok := dailyPlanSizes[riskLevel]
+    if !ok {
+        size = defaultDailyPlanSize
+    }
+    if len(candidates) > size {
+        candidates = candidates[:size]
+    }
+    for i := range candidates {
+        candidates[i].Rank = i + 1
+    }
+
+    reviews, err := GetDueReviews(ctx, userID, dailyReviewLimit, loc)
+    if err != nil {
+        return models.TherapyPlan{}, err
+    }
+
+    log.Printf("Recommended %d therapy categories and %d reviews for userID: %s, riskLevel: %s", len(candidates), len(reviews), userID, riskLevel)
+    return models.TherapyPlan{
+        RiskLevel:   riskLevel,
+        Items:       candidates,
+        Reviews:     reviews,
+        GeneratedAt: time.Now().UTC(),
+    }, nil
+}
+
+// recommendCategory scores a therapy category and explains the score. Lower assessment scores
+// and lower therapy accuracy both raise the priority.
+func recommendCategory(profile models.LearningProfile, riskLevel, questionType string, category models.TherapyCategory, submissions []models.TherapySubmissionRecord) models.TherapyRecommendation {
+    var reasons []string
+
+    assessmentNeed := unknownNeed
+    if score, scope, ok := assessmentScore(profile, questionType, category.Category); ok {
+        assessmentNeed = 1 - score
+        reasons = append(reasons, fmt.Sprintf("assessment score for %s is %.0f%% (%s)", scope, score*100, scoreLevel(score)))
+    } else {
+        reasons = append(reasons, fmt.Sprintf("%s has not been assessed yet", questionType))
+    }
+
+    therapyNeed := unknownNeed
+    if accuracy, ok := recentAccuracy(submissions); !ok {
+        reasons = append(reasons, "not practiced yet")
+    } else {
+        therapyNeed = 1 - accuracy
+        reasons = append(reasons, fmt.Sprintf("therapy accuracy is %.0f%%", accuracy*100))
+    }
+
+    priority := assessmentWeight*assessmentNeed + therapyWeight*therapyNeed
+    if boost, ok := riskBoosts[riskLevel]; ok && assessmentNeed > 1-weaknessThreshold {
+        priority += boost
+        reasons = append(reasons, fmt.Sprintf("screening risk level is %s", riskLevel))
+    }
+
+    reason := strings.Join(reasons, "; ")
+    return models.TherapyRecommendation{
+        Type:        questionType,
+        Category:    category.Category,
+        Description: category.Description,
+        Priority:    math.Round(priority*100) / 100,
+        Reason:      strings.ToUpper(reason[:1]) + reason[1:],
+    }
+}
+
+// recentAccuracy returns the share of correct answers among the most recent therapy submissions,
+// reporting false when there are none.
+func recentAccuracy(submissions []models.TherapySubmissionRecord) (float64, bool) {
+    if len(submissions) == 0 {
+        return 0, false
+    }
+    recent := append([]models.TherapySubmissionRecord{}, submissions...)
+    sort.SliceStable(recent, func(i, j int) bool {
+        return recent[i].Timestamp.After(recent[j].Timestamp)
+    })
+    if len(recent) > accuracyWindow {
+        recent = recent[:accuracyWindow]
+    }
+
+    correct := 0
+    for _, sub := range recent {
+        if sub.CorrectAnswers > 0 {
+            correct++
+        }
+    }
+    return float64(correct) / float64(len(recent)), true
+}
+
+// assessmentScore returns the user's normalized assessment score for a category, falling back to
+// the score of its type, together with the scope the score covers.
+func assessmentScore(profile models.LearningProfile, questionType, category string) (float64, string, bool) {
+    for _, c := range profile.Categories {
+        if c.Type == questionType && c.Category == category {
+            return c.Score, questionType + "/" + category, true
+        }
+    }
+    for _, m := range profile.Modalities {
+        if m.Type == questionType && m.Assessed {
+            return m.Score, questionType, true
+        }
+    }
+    return 0, "", false
+}
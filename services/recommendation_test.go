@@ -0,0 +1,98 @@
+package services
+
+import (
+    "context"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestRecentAccuracy(t *testing.T) {
+    now := time.Now()
+    submissions := func(correct ...bool) []models.TherapySubmissionRecord {
+        records := make([]models.TherapySubmissionRecord, len(correct))
+        for i, c := range correct {
+            records[i] = models.TherapySubmissionRecord{Timestamp: now.Add(time.Duration(i) * time.Minute)}
+            if c {
+                records[i].CorrectAnswers = 1
+            }
+        }
+        return records
+    }
+
+    if _, ok := recentAccuracy(nil); ok {
+        t.Error("recentAccuracy(nil) reported an accuracy")
+    }
+    // Accuracy covers the answers given, not every question of the category.
+    if got, _ := recentAccuracy(submissions(true, true)); got != 1 {
+        t.Errorf("accuracy of two correct answers = %v, want 1", got)
+    }
+    if got, _ := recentAccuracy(submissions(true, false, false, true)); got != 0.5 {
+        t.Errorf("accuracy of two of four correct = %v, want 0.5", got)
+    }
+
+    // Only the most recent answers count.
+    history := make([]bool, accuracyWindow+5)
+    for i := 5; i < len(history); i++ {
+        history[i] = true
+    }
+    if got, _ := recentAccuracy(submissions(history...)); got != 1 {
+        t.Errorf("accuracy with old mistakes outside the window = %v, want 1", got)
+    }
+}
+
+func TestRecommendTherapyRanking(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    mastered := saveTherapyQuestion(t, "visual", "letter_recognition", "b")
+    struggling := saveTherapyQuestion(t, "auditory", "phoneme", "p")
+    saveTherapyQuestion(t, "kinesthetic", "tracing", "a")
+    for i := 0; i < 10; i++ {
+        saveTherapyQuestion(t, "visual", "letter_recognition", "d")
+    }
+
+    submit := func(questionID, answer string) {
+        t.Helper()
+        submission := models.TherapySubmission{QuestionID: questionID, Answer: answer}
+        if _, err := SaveTherapyResult(ctx, "user", submission, "", time.UTC); err != nil {
+            t.Fatalf("SaveTherapyResult() error = %v", err)
+        }
+    }
+    submit(mastered.ID, "b")
+    submit(struggling.ID, "q")
+
+    plan, err := RecommendTherapy(ctx, "user", time.UTC)
+    if err != nil {
+        t.Fatalf("RecommendTherapy() error = %v", err)
+    }
+
+    // Without a profile the assessment need is 0.5 everywhere, so therapy accuracy decides the order.
+    var got []string
+    for _, item := range plan.Items {
+        got = append(got, item.Category)
+    }
+    if want := []string{"phoneme", "tracing", "letter_recognition"}; !slices.Equal(got, want) {
+        t.Fatalf("ranked categories = %v, want %v", got, want)
+    }
+    wantPriorities := []float64{0.7, 0.5, 0.3}
+    for i, item := range plan.Items {
+        if item.Rank != i+1 || item.Priority != wantPriorities[i] {
+            t.Errorf("item %s rank = %d, priority = %v, want %d and %v", item.Category, item.Rank, item.Priority, i+1, wantPriorities[i])
+        }
+    }
+}
+
+func TestRecommendCategoryRiskBoost(t *testing.T) {
+    profile := models.LearningProfile{
+        Categories: []models.CategoryProfile{{Type: "auditory", Category: "phoneme", Score: 0.2}},
+    }
+    category := models.TherapyCategory{Category: "phoneme"}
+
+    low := recommendCategory(profile, "low", "auditory", category, nil)
+    high := recommendCategory(profile, "high", "auditory", category, nil)
+    if low.Priority != 0.68 || high.Priority != 0.88 {
+        t.Errorf("priorities = %v at low risk and %v at high risk, want 0.68 and 0.88", low.Priority, high.Priority)
+    }
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// repo is the storage backend used by every service function.
+var repo repository.Repository
+
+// SetRepository selects the storage backend used by the services package.
+func SetRepository(r repository.Repository) {
+    repo = r
+}
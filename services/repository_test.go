@@ -0,0 +1,55 @@
+package services
+
+import (
+    "context"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// useMemoryRepository points the services at an empty in-memory repository for one test.
+func useMemoryRepository(t *testing.T) *repository.MemoryRepository {
+    t.Helper()
+    previous := repo
+    r := repository.NewMemoryRepository()
+    SetRepository(r)
+    t.Cleanup(func() { SetRepository(previous) })
+    return r
+}
+
+// saveTherapyQuestion stores a single-choice therapy question and returns it with its ID.
+func saveTherapyQuestion(t *testing.T, questionType, category, correctAnswer string) models.TherapyQuestion {
+    t.Helper()
+    question := models.TherapyQuestion{
+        Type:          questionType,
+        Category:      category,
+        Options:       []string{correctAnswer, correctAnswer + "x"},
+        CorrectAnswer: correctAnswer,
+    }
+    id, err := SaveTherapyQuestion(context.Background(), question, "admin")
+    if err != nil {
+        t.Fatalf("SaveTherapyQuestion() error = %v", err)
+    }
+    question.ID = id
+    question.AnswerFormat = models.AnswerFormatSingleChoice
+    return question
+}
+
+// saveAssessmentQuestion stores a single-choice assessment question and returns it with its ID.
+func saveAssessmentQuestion(t *testing.T, questionType, category, correctAnswer string) models.AssessmentQuestion {
+    t.Helper()
+    question := models.AssessmentQuestion{
+        Type:          questionType,
+        Category:      category,
+        Options:       []string{correctAnswer, correctAnswer + "x"},
+        CorrectAnswer: correctAnswer,
+    }
+    id, err := SaveAssessmentQuestion(context.Background(), question, "admin")
+    if err != nil {
+        t.Fatalf("SaveAssessmentQuestion() error = %v", err)
+    }
+    question.ID = id
+    question.AnswerFormat = models.AnswerFormatSingleChoice
+    return question
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "math"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// SM-2 parameters.
+const (
+    initialEaseFactor = 2.5
+    minEaseFactor     = 1.3
+    maxReviewQuality  = 5
+    passingQuality    = 3
+)
+
+// dailyReviewLimit caps how many due reviews are mixed into a day's therapy plan.
+const dailyReviewLimit = 5
+
+// reviewQuality grades an answer on the SM-2 scale of 0 to 5 from its score.
+func reviewQuality(score float64) int {
+    return int(math.Round(math.Max(0, math.Min(score, 1)) * maxReviewQuality))
+}
+
+// scheduleReview applies one SM-2 review of the given quality to a schedule. Failed reviews
+// restart the repetitions and are due again the next day. Days are counted in loc.
+func scheduleReview(state *models.ReviewState, quality int, reviewedAt time.Time, loc *time.Location) {
+    if state.EaseFactor == 0 {
+        state.EaseFactor = initialEaseFactor
+    }
+
+    if quality < passingQuality {
+        state.Repetitions = 0
+        state.IntervalDays = 1
+    } else {
+        state.Repetitions++
+        switch state.Repetitions {
+        case 1:
+            state.IntervalDays = 1
+        case 2:
+            state.IntervalDays = 6
+        default:
+            state.IntervalDays = int(math.Round(float64(state.IntervalDays) * state.EaseFactor))
+        }
+    }
+
+    lapse := float64(maxReviewQuality - quality)
+    state.EaseFactor = math.Max(minEaseFactor, state.EaseFactor+0.1-lapse*(0.08+lapse*0.02))
+    state.LastQuality = quality
+    state.LastReviewedAt = reviewedAt
+
+    state.DueAt = localDate(reviewedAt, loc).AddDate(0, 0, state.IntervalDays)
+}
+
+// recordReview updates the user's schedule for a therapy question after an answer, logging rather
+// than failing when it cannot.
+func recordReview(ctx context.Context, userID string, question models.TherapyQuestion, score float64, loc *time.Location) {
+    quality := reviewQuality(score)
+    state, err := repo.UpdateReviewState(ctx, userID, question.ID, func(state *models.ReviewState) error {
+        state.Type = question.Type
+        state.Category = question.Category
+        scheduleReview(state, quality, time.Now().UTC(), loc)
+        return nil
+    })
+    if err != nil {
+        log.Printf("Failed to update review schedule for userID %s, questionID %s: %v", userID, question.ID, err)
+        return
+    }
+
+    log.Printf("Scheduled review for userID: %s, questionID: %s, quality: %d, interval: %d days", userID, question.ID, quality, state.IntervalDays)
+}
+
+// GetDueReviews returns the therapy questions due for review today in loc, earliest due first. A
+// limit of 0 returns every due question. Questions deleted since they were scheduled are skipped.
+func GetDueReviews(ctx context.Context, userID string, limit int, loc *time.Location) ([]models.DueReview, error) {
+    today := localDate(time.Now(), loc)
+
+    states, err := repo.ListDueReviews(ctx, userID, today)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve due reviews: %w", err)
+    }
+
+    reviews := make([]models.DueReview, 0, len(states))
+    for _, state := range states {
+        if limit > 0 && len(reviews) == limit {
+            break
+        }
+        question, err := FindTherapyQuestion(ctx, state.QuestionID)
+        if errors.Is(err, repository.ErrNotFound) {
+            log.Printf("Skipping review of deleted therapy question %s for userID: %s", state.QuestionID, userID)
+            continue
+        }
+        if err != nil {
+            return nil, fmt.Errorf("failed to retrieve therapy question: %w", err)
+        }
+        reviews = append(reviews, models.DueReview{Question: question, Review: state})
+    }
+
+    log.Printf("Retrieved %d due reviews for userID: %s", len(reviews), userID)
+    return reviews, nil
+}
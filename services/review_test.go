@@ -0,0 +1,160 @@
+package services
+
+import (
+    "context"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestReviewQuality(t *testing.T) {
+    tests := []struct {
+        score float64
+        want  int
+    }{
+        {-0.5, 0},
+        {0, 0},
+        {0.5, 3},
+        {0.59, 3},
+        {1, 5},
+        {1.5, 5},
+    }
+    for _, tt := range tests {
+        if got := reviewQuality(tt.score); got != tt.want {
+            t.Errorf("reviewQuality(%v) = %d, want %d", tt.score, got, tt.want)
+        }
+    }
+}
+
+func TestScheduleReview(t *testing.T) {
+    reviewedAt := time.Date(2025, 4, 10, 8, 0, 0, 0, time.UTC)
+    steps := []struct {
+        quality      int
+        wantInterval int
+        wantReps     int
+        wantEase     float64
+    }{
+        {5, 1, 1, 2.6},
+        {5, 6, 2, 2.7},
+        {5, 16, 3, 2.8},
+        {4, 45, 4, 2.8},
+        {2, 1, 0, 2.48},
+        {3, 1, 1, 2.34},
+    }
+
+    var state models.ReviewState
+    for i, step := range steps {
+        scheduleReview(&state, step.quality, reviewedAt, time.UTC)
+        if state.IntervalDays != step.wantInterval || state.Repetitions != step.wantReps || !floatEqual(round2(state.EaseFactor), step.wantEase) {
+            t.Fatalf("review %d: interval %d, repetitions %d, ease %.2f, want %d, %d, %.2f", i+1, state.IntervalDays, state.Repetitions, state.EaseFactor, step.wantInterval, step.wantReps, step.wantEase)
+        }
+        if state.LastQuality != step.quality || !state.LastReviewedAt.Equal(reviewedAt) {
+            t.Fatalf("review %d: last quality %d at %v, want %d at %v", i+1, state.LastQuality, state.LastReviewedAt, step.quality, reviewedAt)
+        }
+    }
+}
+
+func TestScheduleReviewEaseFloor(t *testing.T) {
+    var state models.ReviewState
+    for i := 0; i < 10; i++ {
+        scheduleReview(&state, 0, time.Now(), time.UTC)
+    }
+    if state.EaseFactor != minEaseFactor {
+        t.Errorf("ease factor = %v, want %v", state.EaseFactor, minEaseFactor)
+    }
+}
+
+func TestScheduleReviewDueDateInLocation(t *testing.T) {
+    // 20:00 UTC on April 10 is already April 11 in UTC+7.
+    reviewedAt := time.Date(2025, 4, 10, 20, 0, 0, 0, time.UTC)
+    tests := []struct {
+        name string
+        loc  *time.Location
+        want time.Time
+    }{
+        {"utc", time.UTC, time.Date(2025, 4, 11, 0, 0, 0, 0, time.UTC)},
+        {"ahead of utc", time.FixedZone("UTC+7", 7*60*60), time.Date(2025, 4, 12, 0, 0, 0, 0, time.UTC)},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var state models.ReviewState
+            scheduleReview(&state, 5, reviewedAt, tt.loc)
+            if !state.DueAt.Equal(tt.want) {
+                t.Errorf("DueAt = %v, want %v", state.DueAt, tt.want)
+            }
+        })
+    }
+}
+
+func TestGetDueReviews(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    east := time.FixedZone("UTC+14", 14*60*60)
+    west := time.FixedZone("UTC-12", -12*60*60)
+    today := localDate(time.Now(), east)
+
+    overdue := saveTherapyQuestion(t, "visual", "letter_recognition", "b")
+    dueToday := saveTherapyQuestion(t, "visual", "letter_recognition", "d")
+    dueTomorrow := saveTherapyQuestion(t, "visual", "letter_recognition", "p")
+    deleted := saveTherapyQuestion(t, "visual", "letter_recognition", "q")
+    if err := DeleteTherapyQuestion(ctx, deleted.ID, deleted.Type, deleted.Category, "admin"); err != nil {
+        t.Fatalf("DeleteTherapyQuestion() error = %v", err)
+    }
+
+    schedule := map[string]time.Time{
+        overdue.ID:     today.AddDate(0, 0, -3),
+        dueToday.ID:    today,
+        dueTomorrow.ID: today.AddDate(0, 0, 1),
+        deleted.ID:     today.AddDate(0, 0, -5),
+    }
+    for questionID, dueAt := range schedule {
+        _, err := r.UpdateReviewState(ctx, "user", questionID, func(state *models.ReviewState) error {
+            state.DueAt = dueAt
+            return nil
+        })
+        if err != nil {
+            t.Fatalf("UpdateReviewState() error = %v", err)
+        }
+    }
+
+    reviews, err := GetDueReviews(ctx, "user", 0, east)
+    if err != nil {
+        t.Fatalf("GetDueReviews() error = %v", err)
+    }
+    if got := reviewQuestionIDs(reviews); !slices.Equal(got, []string{overdue.ID, dueToday.ID}) {
+        t.Errorf("due reviews = %v, want %v", got, []string{overdue.ID, dueToday.ID})
+    }
+
+    reviews, err = GetDueReviews(ctx, "user", 1, east)
+    if err != nil {
+        t.Fatalf("GetDueReviews() error = %v", err)
+    }
+    if got := reviewQuestionIDs(reviews); !slices.Equal(got, []string{overdue.ID}) {
+        t.Errorf("limited due reviews = %v, want %v", got, []string{overdue.ID})
+    }
+
+    // Today in UTC+14 is still yesterday, or earlier, in UTC-12.
+    reviews, err = GetDueReviews(ctx, "user", 0, west)
+    if err != nil {
+        t.Fatalf("GetDueReviews() error = %v", err)
+    }
+    if got := reviewQuestionIDs(reviews); !slices.Equal(got, []string{overdue.ID}) {
+        t.Errorf("due reviews behind utc = %v, want %v", got, []string{overdue.ID})
+    }
+}
+
+// reviewQuestionIDs returns the question IDs of due reviews in order.
+func reviewQuestionIDs(reviews []models.DueReview) []string {
+    ids := make([]string, len(reviews))
+    for i, review := range reviews {
+        ids[i] = review.Question.ID
+    }
+    return ids
+}
+
+// round2 rounds to two decimals, matching how ease factors are written in the tests.
+func round2(v float64) float64 {
+    return float64(int(v*100+0.5)) / 100
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "slices"
+    "sort"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+var (
+    // ErrInvalidRole is returned for a role other than those listed in rolePermissions.
+    ErrInvalidRole = errors.New("invalid role")
+    // ErrNotTherapist is returned when assigning a learner to a user without the therapist role.
+    ErrNotTherapist = errors.New("user is not a therapist")
+)
+
+// rolePermissions lists the permissions each role grants. Every signed-in user may use the routes
+// that need no permission, so learners grant none. Parents grant none either: the children they
+// manage are their own child profiles, selected with X-Profile-ID. Therapists manage the goals of
+// the learners an admin assigned to them; screening rules and any user's goals stay with admins.
+var rolePermissions = map[string][]string{
+    models.RoleLearner: {},
+    models.RoleParent:  {},
+    models.RoleTherapist: {
+        models.PermissionManageAssignedGoals,
+    },
+    models.RoleContentEditor: {
+        models.PermissionManageQuestions,
+    },
+    models.RoleAdmin: {
+        models.PermissionManageQuestions,
+        models.PermissionManageScreeningRules,
+        models.PermissionManageUserGoals,
+        models.PermissionManageRoles,
+        models.PermissionMaintenance,
+    },
+}
+
+// HasPermission reports whether any of roles grants permission. Unknown roles grant nothing.
+func HasPermission(roles []string, permission string) bool {
+    for _, role := range roles {
+        if slices.Contains(rolePermissions[role], permission) {
+            return true
+        }
+    }
+    return false
+}
+
+// UserRoles returns the roles of a user. Users without stored roles are admins when they have the
+// legacy isAdmin flag and learners otherwise.
+func UserRoles(user models.User) []string {
+    switch {
+    case len(user.Roles) > 0:
+        roles := append([]string(nil), user.Roles...)
+        sort.Strings(roles)
+        return roles
+    case user.IsAdmin:
+        return []string{models.RoleAdmin}
+    default:
+        return []string{models.RoleLearner}
+    }
+}
+
+// GetUserRoles retrieves the roles of a user, treating unknown users as learners.
+func GetUserRoles(ctx context.Context, userID string) ([]string, error) {
+    user, err := repo.GetUser(ctx, userID)
+    if errors.Is(err, repository.ErrNotFound) {
+        return UserRoles(models.User{}), nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve user data: %w", err)
+    }
+    return UserRoles(user), nil
+}
+
+// SetUserRoles validates and replaces the roles of a user. The change reaches the user's tokens
+// when they are next refreshed.
+func SetUserRoles(ctx context.Context, userID string, roles []string, setBy string) ([]string, error) {
+    if len(roles) == 0 {
+        return nil, fmt.Errorf("%w: at least one role is required", ErrInvalidRole)
+    }
+    unique := make([]string, 0, len(roles))
+    for _, role := range roles {
+        if _, ok := rolePermissions[role]; !ok {
+            return nil, fmt.Errorf("%w: %q", ErrInvalidRole, role)
+        }
+        if !slices.Contains(unique, role) {
+            unique = append(unique, role)
+        }
+    }
+    sort.Strings(unique)
+
+    if err := repo.SaveUserRoles(ctx, userID, unique); err != nil {
+        return nil, fmt.Errorf("failed to save user roles: %w", err)
+    }
+
+    log.Printf("Set roles for userID: %s to %v by userID: %s", userID, unique, setBy)
+    return unique, nil
+}
+
+// GetUserTherapists retrieves the therapists assigned to a learner.
+func GetUserTherapists(ctx context.Context, userID string) ([]string, error) {
+    user, err := repo.GetUser(ctx, userID)
+    if errors.Is(err, repository.ErrNotFound) {
+        return []string{}, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve user data: %w", err)
+    }
+    if user.TherapistIDs == nil {
+        return []string{}, nil
+    }
+    return user.TherapistIDs, nil
+}
+
+// SetUserTherapists validates and replaces the therapists assigned to a learner. Every therapist
+// must currently hold the therapist role; an empty list removes every assignment.
+func SetUserTherapists(ctx context.Context, userID string, therapistIDs []string, setBy string) ([]string, error) {
+    unique := make([]string, 0, len(therapistIDs))
+    for _, therapistID := range therapistIDs {
+        if slices.Contains(unique, therapistID) {
+            continue
+        }
+        roles, err := GetUserRoles(ctx, therapistID)
+        if err != nil {
+            return nil, err
+        }
+        if !slices.Contains(roles, models.RoleTherapist) {
+            return nil, fmt.Errorf("%w: %q", ErrNotTherapist, therapistID)
+        }
+        unique = append(unique, therapistID)
+    }
+    sort.Strings(unique)
+
+    if err := repo.SaveUserTherapists(ctx, userID, unique); err != nil {
+        return nil, fmt.Errorf("failed to save user therapists: %w", err)
+    }
+
+    log.Printf("Set therapists for userID: %s to %v by userID: %s", userID, unique, setBy)
+    return unique, nil
+}
+
+// IsAssignedTherapist reports whether therapistID is one of the therapists assigned to a learner.
+func IsAssignedTherapist(ctx context.Context, therapistID, userID string) (bool, error) {
+    therapists, err := GetUserTherapists(ctx, userID)
+    if err != nil {
+        return false, err
+    }
+    return slices.Contains(therapists, therapistID), nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "slices"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestHasPermission(t *testing.T) {
+    tests := []struct {
+        name       string
+        roles      []string
+        permission string
+        want       bool
+    }{
+        {"no roles", nil, models.PermissionManageQuestions, false},
+        {"learner", []string{models.RoleLearner}, models.PermissionManageQuestions, false},
+        {"parent", []string{models.RoleParent}, models.PermissionManageUserGoals, false},
+        {"therapist assigned goals", []string{models.RoleTherapist}, models.PermissionManageAssignedGoals, true},
+        {"therapist any user's goal", []string{models.RoleTherapist}, models.PermissionManageUserGoals, false},
+        {"content editor", []string{models.RoleContentEditor}, models.PermissionManageQuestions, true},
+        {"content editor roles", []string{models.RoleContentEditor}, models.PermissionManageRoles, false},
+        {"admin", []string{models.RoleAdmin}, models.PermissionMaintenance, true},
+        {"any role grants", []string{models.RoleLearner, models.RoleContentEditor}, models.PermissionManageQuestions, true},
+        {"unknown role", []string{"superuser"}, models.PermissionManageQuestions, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := HasPermission(tt.roles, tt.permission); got != tt.want {
+                t.Errorf("HasPermission(%v, %q) = %v, want %v", tt.roles, tt.permission, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestSetUserTherapists(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    for _, therapistID := range []string{"therapist-b", "therapist-a"} {
+        if _, err := SetUserRoles(ctx, therapistID, []string{models.RoleTherapist}, "admin"); err != nil {
+            t.Fatalf("SetUserRoles() error = %v", err)
+        }
+    }
+
+    if _, err := SetUserTherapists(ctx, "learner", []string{"therapist-a", "learner-2"}, "admin"); !errors.Is(err, ErrNotTherapist) {
+        t.Errorf("SetUserTherapists() with a learner error = %v, want ErrNotTherapist", err)
+    }
+
+    got, err := SetUserTherapists(ctx, "learner", []string{"therapist-b", "therapist-a", "therapist-b"}, "admin")
+    if err != nil {
+        t.Fatalf("SetUserTherapists() error = %v", err)
+    }
+    if want := []string{"therapist-a", "therapist-b"}; !slices.Equal(got, want) {
+        t.Errorf("SetUserTherapists() = %v, want %v", got, want)
+    }
+
+    if assigned, err := IsAssignedTherapist(ctx, "therapist-a", "learner"); err != nil || !assigned {
+        t.Errorf("IsAssignedTherapist(therapist-a) = %v, %v, want true", assigned, err)
+    }
+    if assigned, err := IsAssignedTherapist(ctx, "therapist-a", "other-learner"); err != nil || assigned {
+        t.Errorf("IsAssignedTherapist() for an unassigned learner = %v, %v, want false", assigned, err)
+    }
+
+    if _, err := SetUserTherapists(ctx, "learner", nil, "admin"); err != nil {
+        t.Fatalf("SetUserTherapists() clearing error = %v", err)
+    }
+    if assigned, _ := IsAssignedTherapist(ctx, "therapist-a", "learner"); assigned {
+        t.Error("therapist-a is still assigned after the assignments were cleared")
+    }
+}
@@ -2,52 +2,40 @@ package services
 
 import (
     "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
     "fmt"
     "log"
+    "slices"
+    "sort"
+    "strings"
+    "time"
 
-    "cloud.google.com/go/firestore"
     "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
 )
 
-// GetScreeningQuestions retrieves screening questions, optionally filtered by ageGroup.
+// Errors returned when binding screening answers to questions.
+var (
+    ErrInvalidScreeningAnswers = errors.New("invalid screening answers")
+    ErrScreeningSetChanged     = errors.New("screening question set changed")
+)
+
+// GetScreeningQuestions retrieves screening questions, optionally filtered by ageGroup, ordered by
+// age group and ID. Positional answers follow this order.
 func GetScreeningQuestions(ctx context.Context, ageGroup string, userID string) ([]models.ScreeningQuestion, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
+    questions, skipped, err := repo.ListScreeningQuestions(ctx, ageGroup)
     if err != nil {
-        return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
+        return nil, fmt.Errorf("failed to retrieve screening questions for ageGroup %s: %w", ageGroup, err)
     }
-    defer firestoreClient.Close()
-
-    var docs []*firestore.DocumentSnapshot
-    if ageGroup == "" {
-        ageGroupsIter := firestoreClient.Collection("screeningQuestions").DocumentRefs(ctx)
-        ageGroups, err := ageGroupsIter.GetAll()
-        if err != nil {
-            return nil, fmt.Errorf("failed to retrieve age groups: %w", err)
-        }
-        for _, ageGroupDoc := range ageGroups {
-            ageGroupName := ageGroupDoc.ID
-            groupDocs, err := firestoreClient.Collection("screeningQuestions").Doc(ageGroupName).Collection("questions").Documents(ctx).GetAll()
-            if err != nil {
-                log.Printf("Failed to retrieve questions for ageGroup %s: %v", ageGroupName, err)
-                continue
-            }
-            docs = append(docs, groupDocs...)
-        }
-    } else {
-        docs, err = firestoreClient.Collection("screeningQuestions").Doc(ageGroup).Collection("questions").Documents(ctx).GetAll()
-        if err != nil {
-            return nil, fmt.Errorf("failed to retrieve screening questions for ageGroup %s: %w", ageGroup, err)
+    logSkippedQuestions(skipped, "screening", "ageGroup "+ageGroup)
+    sort.Slice(questions, func(i, j int) bool {
+        if questions[i].AgeGroup != questions[j].AgeGroup {
+            return questions[i].AgeGroup < questions[j].AgeGroup
         }
-    }
-
-    questions := make([]models.ScreeningQuestion, 0, len(docs))
-    for _, doc := range docs {
-        var q models.ScreeningQuestion
-        data := doc.Data()
-        q.AgeGroup = data["ageGroup"].(string)
-        q.Question = data["question"].(string)
-        questions = append(questions, q)
-    }
+        return questions[i].ID < questions[j].ID
+    })
 
     log.Printf("Retrieved %d screening questions for ageGroup: %s", len(questions), ageGroup)
     return questions, nil
@@ -55,64 +43,55 @@ func GetScreeningQuestions(ctx context.Context, ageGroup string, userID string)
 
 // GetScreeningQuestionByID retrieves a screening question by ID.
 func GetScreeningQuestionByID(ctx context.Context, questionID, ageGroup string) (models.ScreeningQuestion, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return models.ScreeningQuestion{}, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    doc, err := firestoreClient.Collection("screeningQuestions").Doc(ageGroup).Collection("questions").Doc(questionID).Get(ctx)
+    q, err := repo.GetScreeningQuestion(ctx, ageGroup, questionID)
     if err != nil {
         return models.ScreeningQuestion{}, fmt.Errorf("failed to retrieve screening question: %w", err)
     }
 
-    var q models.ScreeningQuestion
-    data := doc.Data()
-    q.ID = doc.Ref.ID
-    q.AgeGroup = data["ageGroup"].(string)
-    if question, ok := data["question"].(string); ok {
-        q.Question = question
-    }
-
     log.Printf("Retrieved screening question with ID: %s, ageGroup: %s", questionID, ageGroup)
     return q, nil
 }
 
-// SaveScreeningQuestion saves a new screening question to Firestore.
+// FindScreeningQuestion locates a screening question by ID across all age groups.
+func FindScreeningQuestion(ctx context.Context, questionID string) (models.ScreeningQuestion, error) {
+    for _, ag := range screeningAgeGroups {
+        q, err := repo.GetScreeningQuestion(ctx, ag, questionID)
+        if err == nil {
+            return q, nil
+        }
+        if !errors.Is(err, repository.ErrNotFound) {
+            log.Printf("Failed to look up screening question %s in ageGroup %s: %v", questionID, ag, err)
+        }
+    }
+    return models.ScreeningQuestion{}, repository.ErrNotFound
+}
+
+// SaveScreeningQuestion saves a new screening question.
 func SaveScreeningQuestion(ctx context.Context, question models.ScreeningQuestion, userID string) (string, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
+    weight, err := checkScreeningWeight(question.Weight)
     if err != nil {
-        return "", fmt.Errorf("failed to connect to Firestore: %w", err)
+        return "", err
     }
-    defer firestoreClient.Close()
+    question.Weight = weight
 
-    docRef, _, err := firestoreClient.Collection("screeningQuestions").Doc(question.AgeGroup).Collection("questions").Add(ctx, map[string]interface{}{
-        "ageGroup":  question.AgeGroup,
-        "question":  question.Question,
-        "timestamp": firestore.ServerTimestamp,
-    })
+    questionID, err := repo.CreateScreeningQuestion(ctx, question)
     if err != nil {
         return "", fmt.Errorf("failed to save screening question: %w", err)
     }
 
-    log.Printf("Saved screening question with ID: %s, ageGroup: %s", docRef.ID, question.AgeGroup)
-    return docRef.ID, nil
+    log.Printf("Saved screening question with ID: %s, ageGroup: %s", questionID, question.AgeGroup)
+    return questionID, nil
 }
 
-// UpdateScreeningQuestion updates an existing screening question in Firestore.
+// UpdateScreeningQuestion updates an existing screening question.
 func UpdateScreeningQuestion(ctx context.Context, questionID string, question models.ScreeningQuestion, userID string) error {
-    firestoreClient, err := GetFirestoreClient(ctx)
+    weight, err := checkScreeningWeight(question.Weight)
     if err != nil {
-        return fmt.Errorf("failed to connect to Firestore: %w", err)
+        return err
     }
-    defer firestoreClient.Close()
+    question.Weight = weight
 
-    _, err = firestoreClient.Collection("screeningQuestions").Doc(question.AgeGroup).Collection("questions").Doc(questionID).Set(ctx, map[string]interface{}{
-        "ageGroup":  question.AgeGroup,
-        "question":  question.Question,
-        "timestamp": firestore.ServerTimestamp,
-    }, firestore.MergeAll)
-    if err != nil {
+    if err := repo.UpdateScreeningQuestion(ctx, questionID, question); err != nil {
         return fmt.Errorf("failed to update screening question: %w", err)
     }
 
@@ -120,16 +99,9 @@ func UpdateScreeningQuestion(ctx context.Context, questionID string, question mo
     return nil
 }
 
-// DeleteScreeningQuestion deletes a screening question from Firestore.
+// DeleteScreeningQuestion deletes a screening question.
 func DeleteScreeningQuestion(ctx context.Context, questionID, ageGroup, userID string) error {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    _, err = firestoreClient.Collection("screeningQuestions").Doc(ageGroup).Collection("questions").Doc(questionID).Delete(ctx)
-    if err != nil {
+    if err := repo.DeleteScreeningQuestion(ctx, ageGroup, questionID); err != nil {
         return fmt.Errorf("failed to delete screening question: %w", err)
     }
 
@@ -137,26 +109,166 @@ func DeleteScreeningQuestion(ctx context.Context, questionID, ageGroup, userID s
     return nil
 }
 
-// SaveScreeningResult saves the user's screening submission and risk level.
-func SaveScreeningResult(ctx context.Context, userID, ageGroup string, answers []bool, riskLevel string, firebaseToken string) error {
-    firestoreClient, err := GetFirestoreClient(ctx)
+// ScreeningQuestionSetVersion identifies the questions of an age group as a user sees them. It
+// changes whenever a question is added, deleted or reworded, but not when only its weight or
+// domain changes, since those do not alter what the user answered.
+func ScreeningQuestionSetVersion(questions []models.ScreeningQuestion) string {
+    hash := sha256.New()
+    for _, q := range questions {
+        fmt.Fprintf(hash, "%s\x00%s\x00", q.ID, q.Question)
+    }
+    return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// BindScreeningAnswers matches a submission's answers to the current questions of its age group.
+// Responses keyed by question ID are reconciled when the submission's version is stale or missing:
+// answers to deleted questions are ignored, while unanswered new questions fail with
+// ErrScreeningSetChanged. Positional answers cannot be reconciled, so they fail with
+// ErrScreeningSetChanged unless they carry the current version.
+func BindScreeningAnswers(ctx context.Context, submission models.ScreeningSubmission) (models.ScreeningAnswerSet, error) {
+    if !slices.Contains(screeningAgeGroups, submission.AgeGroup) {
+        return models.ScreeningAnswerSet{}, fmt.Errorf("%w: %s", ErrInvalidAgeGroup, submission.AgeGroup)
+    }
+    questions, err := GetScreeningQuestions(ctx, submission.AgeGroup, "")
+    if err != nil {
+        return models.ScreeningAnswerSet{}, err
+    }
+    if len(questions) == 0 {
+        return models.ScreeningAnswerSet{}, fmt.Errorf("%w for ageGroup %s", ErrNoQuestions, submission.AgeGroup)
+    }
+
+    set := models.ScreeningAnswerSet{
+        Version:   ScreeningQuestionSetVersion(questions),
+        Questions: questions,
+        Answers:   make([]bool, len(questions)),
+        Ignored:   []string{},
+    }
+    stale := submission.Version != set.Version
+
+    if len(submission.Responses) == 0 {
+        if submission.Version == "" {
+            return set, fmt.Errorf("%w: positional answers need the question set version, current version is %s", ErrScreeningSetChanged, set.Version)
+        }
+        if stale {
+            return set, fmt.Errorf("%w: answers were given for version %s, current version is %s", ErrScreeningSetChanged, submission.Version, set.Version)
+        }
+        if len(submission.Answers) != len(questions) {
+            return set, fmt.Errorf("%w: expected %d answers, got %d", ErrInvalidScreeningAnswers, len(questions), len(submission.Answers))
+        }
+        copy(set.Answers, submission.Answers)
+        return set, nil
+    }
+    if len(submission.Answers) > 0 {
+        return set, fmt.Errorf("%w: send either responses or answers, not both", ErrInvalidScreeningAnswers)
+    }
+
+    answers := make(map[string]bool, len(submission.Responses))
+    for _, response := range submission.Responses {
+        if _, ok := answers[response.QuestionID]; ok || response.QuestionID == "" {
+            return set, fmt.Errorf("%w: question IDs must be non-empty and unique", ErrInvalidScreeningAnswers)
+        }
+        answers[response.QuestionID] = response.Answer
+    }
+
+    var missing []string
+    for i, q := range questions {
+        answer, ok := answers[q.ID]
+        if !ok {
+            missing = append(missing, q.ID)
+            continue
+        }
+        set.Answers[i] = answer
+        delete(answers, q.ID)
+    }
+    for questionID := range answers {
+        set.Ignored = append(set.Ignored, questionID)
+    }
+    sort.Strings(set.Ignored)
+
+    if !stale && (len(missing) > 0 || len(set.Ignored) > 0) {
+        return set, fmt.Errorf("%w: responses do not match version %s", ErrInvalidScreeningAnswers, set.Version)
+    }
+    if len(missing) > 0 {
+        return set, fmt.Errorf("%w: questions %s are unanswered, current version is %s", ErrScreeningSetChanged, strings.Join(missing, ", "), set.Version)
+    }
+    if len(set.Ignored) > 0 {
+        log.Printf("Ignored answers to deleted screening questions %v for ageGroup: %s", set.Ignored, submission.AgeGroup)
+    }
+    return set, nil
+}
+
+// riskRanks orders screening risk levels from lowest to highest.
+var riskRanks = map[string]int{
+    "low":      0,
+    "moderate": 1,
+    "high":     2,
+}
+
+// screeningTrend compares a screening's risk level with the user's previous one.
+func screeningTrend(previous, current string) string {
+    switch {
+    case previous == "":
+        return models.ScreeningTrendFirst
+    case riskRanks[current] < riskRanks[previous]:
+        return models.ScreeningTrendImproved
+    case riskRanks[current] > riskRanks[previous]:
+        return models.ScreeningTrendWorsened
+    default:
+        return models.ScreeningTrendUnchanged
+    }
+}
+
+// SaveScreeningResult saves the user's bound screening answers and their score as a new dated
+// record, noting how the risk level changed since the user's previous screening.
+func SaveScreeningResult(ctx context.Context, userID, ageGroup string, set models.ScreeningAnswerSet, score models.ScreeningScore, firebaseToken string) (models.ScreeningResult, error) {
+    riskLevel := score.RiskLevel
+    questionIDs := make([]string, len(set.Questions))
+    for i, q := range set.Questions {
+        questionIDs[i] = q.ID
+    }
+    result := models.ScreeningResult{
+        AgeGroup:           ageGroup,
+        QuestionSetVersion: set.Version,
+        QuestionIDs:        questionIDs,
+        Answers:            set.Answers,
+        Score:              score.Score,
+        RiskLevel:          riskLevel,
+        Domains:            score.Domains,
+        CriticalItems:      score.CriticalItems,
+        Timestamp:          time.Now().UTC(),
+    }
+
+    previous, err := repo.GetScreeningResult(ctx, userID)
+    if err == nil {
+        result.PreviousRiskLevel = previous.RiskLevel
+    } else if !errors.Is(err, repository.ErrNotFound) {
+        return models.ScreeningResult{}, fmt.Errorf("failed to retrieve previous screening result: %w", err)
+    }
+    result.Trend = screeningTrend(result.PreviousRiskLevel, riskLevel)
+
+    result.ID, err = repo.SaveScreeningResult(ctx, userID, result)
     if err != nil {
-        return fmt.Errorf("failed to connect to Firestore: %w", err)
+        return models.ScreeningResult{}, fmt.Errorf("failed to save screening result: %w", err)
     }
-    defer firestoreClient.Close()
 
-    docRef := firestoreClient.Collection("users").Doc(userID).Collection("screenings").Doc("current")
+    log.Printf("Saved screening result %s for userID: %s, ageGroup: %s, riskLevel: %s, trend: %s", result.ID, userID, ageGroup, riskLevel, result.Trend)
+    return result, nil
+}
 
-    _, err = docRef.Set(ctx, map[string]interface{}{
-        "ageGroup":  ageGroup,
-        "answers":   answers,
-        "riskLevel": riskLevel,
-        "timestamp": firestore.ServerTimestamp,
-    }, firestore.MergeAll)
+// GetScreeningHistory retrieves the user's latest screening result together with every result,
+// newest first. Latest is nil when the user has not completed a screening.
+func GetScreeningHistory(ctx context.Context, userID string) (models.ScreeningHistory, error) {
+    results, err := repo.ListScreeningResults(ctx, userID)
     if err != nil {
-        return fmt.Errorf("failed to save screening result: %w", err)
+        return models.ScreeningHistory{}, fmt.Errorf("failed to retrieve screening results: %w", err)
     }
+    slices.Reverse(results)
 
-    log.Printf("Updated screening result for userID: %s, ageGroup: %s", userID, ageGroup)
-    return nil
-}
\ No newline at end of file
+    history := models.ScreeningHistory{History: results}
+    if len(results) > 0 {
+        history.Latest = &results[0]
+    }
+
+    log.Printf("Retrieved %d screening results for userID: %s", len(results), userID)
+    return history, nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "math"
+    "slices"
+    "sort"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// ErrInvalidScreeningRule is returned for a scoring rule with inconsistent thresholds, an unknown
+// risk level or critical items that are not questions of its age group.
+var ErrInvalidScreeningRule = errors.New("invalid screening rule")
+
+// ErrInvalidAgeGroup is returned for an age group other than those listed in screeningAgeGroups.
+var ErrInvalidAgeGroup = errors.New("invalid age group")
+
+// screeningAgeGroups lists the age groups screening questions are grouped by.
+var screeningAgeGroups = []string{"adult", "kid"}
+
+// Default thresholds applied to age groups without a stored scoring rule.
+const (
+    defaultLowRiskMax      = 40.0
+    defaultModerateRiskMax = 70.0
+)
+
+// defaultScreeningRule returns the rule used for an age group until an admin stores one.
+func defaultScreeningRule(ageGroup string) models.ScreeningRule {
+    return models.ScreeningRule{
+        AgeGroup:          ageGroup,
+        LowMax:            defaultLowRiskMax,
+        ModerateMax:       defaultModerateRiskMax,
+        CriticalRiskLevel: "high",
+    }
+}
+
+// checkScreeningWeight defaults an unset weight and rejects one outside the allowed range.
+func checkScreeningWeight(weight float64) (float64, error) {
+    if weight == 0 {
+        return models.DefaultScreeningWeight, nil
+    }
+    if weight < 0 || weight > models.MaxScreeningWeight {
+        return 0, fmt.Errorf("%w: weight must be greater than 0 and at most %g", ErrInvalidQuestion, models.MaxScreeningWeight)
+    }
+    return weight, nil
+}
+
+// screeningWeight returns a question's weight, treating questions stored without one as the default.
+func screeningWeight(q models.ScreeningQuestion) float64 {
+    if q.Weight <= 0 {
+        return models.DefaultScreeningWeight
+    }
+    return q.Weight
+}
+
+// checkThresholds reports an error unless 0 <= lowMax < moderateMax <= 100.
+func checkThresholds(name string, lowMax, moderateMax float64) error {
+    if lowMax < 0 || moderateMax > 100 || lowMax >= moderateMax {
+        return fmt.Errorf("%w: %s thresholds must satisfy 0 <= lowMax < moderateMax <= 100", ErrInvalidScreeningRule, name)
+    }
+    return nil
+}
+
+// GetScreeningRule retrieves the scoring rule of an age group, returning the default rule when
+// none has been stored.
+func GetScreeningRule(ctx context.Context, ageGroup string) (models.ScreeningRule, error) {
+    if !slices.Contains(screeningAgeGroups, ageGroup) {
+        return models.ScreeningRule{}, fmt.Errorf("%w: %s", ErrInvalidAgeGroup, ageGroup)
+    }
+    rule, err := repo.GetScreeningRule(ctx, ageGroup)
+    if errors.Is(err, repository.ErrNotFound) {
+        return defaultScreeningRule(ageGroup), nil
+    }
+    if err != nil {
+        return models.ScreeningRule{}, fmt.Errorf("failed to retrieve screening rule: %w", err)
+    }
+    if rule.CriticalRiskLevel == "" {
+        rule.CriticalRiskLevel = "high"
+    }
+    return rule, nil
+}
+
+// UpdateScreeningRule validates and stores the scoring rule of an age group.
+func UpdateScreeningRule(ctx context.Context, rule models.ScreeningRule, userID string) (models.ScreeningRule, error) {
+    if !slices.Contains(screeningAgeGroups, rule.AgeGroup) {
+        return models.ScreeningRule{}, fmt.Errorf("%w: %s", ErrInvalidAgeGroup, rule.AgeGroup)
+    }
+    if err := checkThresholds("overall", rule.LowMax, rule.ModerateMax); err != nil {
+        return models.ScreeningRule{}, err
+    }
+
+    seen := make(map[string]bool, len(rule.Domains))
+    for _, domain := range rule.Domains {
+        if domain.Domain == "" || seen[domain.Domain] {
+            return models.ScreeningRule{}, fmt.Errorf("%w: domain names must be non-empty and unique", ErrInvalidScreeningRule)
+        }
+        seen[domain.Domain] = true
+        if err := checkThresholds("domain "+domain.Domain, domain.LowMax, domain.ModerateMax); err != nil {
+            return models.ScreeningRule{}, err
+        }
+    }
+
+    if rule.CriticalRiskLevel == "" {
+        rule.CriticalRiskLevel = "high"
+    }
+    if _, ok := riskRanks[rule.CriticalRiskLevel]; !ok {
+        return models.ScreeningRule{}, fmt.Errorf("%w: unknown criticalRiskLevel %q", ErrInvalidScreeningRule, rule.CriticalRiskLevel)
+    }
+    for _, questionID := range rule.CriticalItems {
+        _, err := repo.GetScreeningQuestion(ctx, rule.AgeGroup, questionID)
+        if errors.Is(err, repository.ErrNotFound) {
+            return models.ScreeningRule{}, fmt.Errorf("%w: critical item %s is not a %s screening question", ErrInvalidScreeningRule, questionID, rule.AgeGroup)
+        }
+        if err != nil {
+            return models.ScreeningRule{}, fmt.Errorf("failed to retrieve critical item %s: %w", questionID, err)
+        }
+    }
+
+    rule.UpdatedBy = userID
+    rule.UpdatedAt = time.Now().UTC()
+    if err := repo.SaveScreeningRule(ctx, rule); err != nil {
+        return models.ScreeningRule{}, fmt.Errorf("failed to save screening rule: %w", err)
+    }
+
+    log.Printf("Updated screening rule for ageGroup: %s, lowMax: %.1f, moderateMax: %.1f, domains: %d, criticalItems: %d", rule.AgeGroup, rule.LowMax, rule.ModerateMax, len(rule.Domains), len(rule.CriticalItems))
+    return rule, nil
+}
+
+// riskLevelFor classifies a weighted percentage against a pair of thresholds.
+func riskLevelFor(score, lowMax, moderateMax float64) string {
+    switch {
+    case score <= lowMax:
+        return "low"
+    case score <= moderateMax:
+        return "moderate"
+    default:
+        return "high"
+    }
+}
+
+// roundPercent rounds a percentage to two decimal places.
+func roundPercent(percent float64) float64 {
+    return math.Round(percent*100) / 100
+}
+
+// ScoreScreening scores answers given to questions, in the same order, with the age group's
+// scoring rule. The overall risk level is the highest of the weighted score's level, the levels of
+// domains the rule sets thresholds for, and CriticalRiskLevel when a critical item was answered yes.
+func ScoreScreening(ctx context.Context, ageGroup string, questions []models.ScreeningQuestion, answers []bool) (models.ScreeningScore, error) {
+    if len(questions) != len(answers) {
+        return models.ScreeningScore{}, fmt.Errorf("expected %d answers, got %d", len(questions), len(answers))
+    }
+    rule, err := GetScreeningRule(ctx, ageGroup)
+    if err != nil {
+        return models.ScreeningScore{}, err
+    }
+
+    type tally struct{ positive, total float64 }
+    overall := tally{}
+    domains := make(map[string]*tally)
+    critical := make(map[string]bool, len(rule.CriticalItems))
+    for _, id := range rule.CriticalItems {
+        critical[id] = true
+    }
+
+    result := models.ScreeningScore{CriticalItems: []string{}, Reasons: []string{}}
+    for i, q := range questions {
+        weight := screeningWeight(q)
+        overall.total += weight
+        if answers[i] {
+            overall.positive += weight
+        }
+        if q.Domain != "" {
+            if domains[q.Domain] == nil {
+                domains[q.Domain] = &tally{}
+            }
+            domains[q.Domain].total += weight
+            if answers[i] {
+                domains[q.Domain].positive += weight
+            }
+        }
+        if answers[i] && critical[q.ID] {
+            result.CriticalItems = append(result.CriticalItems, q.ID)
+        }
+    }
+    if overall.total > 0 {
+        result.Score = roundPercent(overall.positive / overall.total * 100)
+    }
+    result.RiskLevel = riskLevelFor(result.Score, rule.LowMax, rule.ModerateMax)
+    result.Reasons = append(result.Reasons, fmt.Sprintf("Weighted score is %.2f%% (%s risk)", result.Score, result.RiskLevel))
+
+    domainRules := make(map[string]models.ScreeningDomainRule, len(rule.Domains))
+    for _, d := range rule.Domains {
+        domainRules[d.Domain] = d
+    }
+    names := make([]string, 0, len(domains))
+    for name := range domains {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        t := domains[name]
+        domain := models.ScreeningDomainScore{Domain: name, Score: roundPercent(t.positive / t.total * 100)}
+        if d, ok := domainRules[name]; ok {
+            domain.RiskLevel = riskLevelFor(domain.Score, d.LowMax, d.ModerateMax)
+            if riskRanks[domain.RiskLevel] > riskRanks[result.RiskLevel] {
+                result.Reasons = append(result.Reasons, fmt.Sprintf("Domain %s scored %.2f%% (%s risk)", name, domain.Score, domain.RiskLevel))
+                result.RiskLevel = domain.RiskLevel
+            }
+        } else {
+            domain.RiskLevel = riskLevelFor(domain.Score, rule.LowMax, rule.ModerateMax)
+        }
+        result.Domains = append(result.Domains, domain)
+    }
+
+    if len(result.CriticalItems) > 0 && riskRanks[rule.CriticalRiskLevel] > riskRanks[result.RiskLevel] {
+        result.Reasons = append(result.Reasons, fmt.Sprintf("%d critical item(s) answered yes (at least %s risk)", len(result.CriticalItems), rule.CriticalRiskLevel))
+        result.RiskLevel = rule.CriticalRiskLevel
+    }
+    for i := range result.Domains {
+        result.Domains[i].Driver = result.Domains[i].RiskLevel == result.RiskLevel
+    }
+    return result, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "slices"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestScoreScreening(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    questions := saveScreeningQuestions(t,
+        models.ScreeningQuestion{Question: "Reverses letters", Weight: 3, Domain: "reading"},
+        models.ScreeningQuestion{Question: "Confuses sounds", Domain: "phonology"},
+        models.ScreeningQuestion{Question: "Loses place while reading", Domain: "reading"},
+        models.ScreeningQuestion{Question: "Struggles to rhyme", Domain: "phonology"},
+    )
+    weights := make(map[string]float64, len(questions))
+    for _, q := range questions {
+        weights[q.Question] = q.Weight
+    }
+    if weights["Reverses letters"] != 3 || weights["Confuses sounds"] != models.DefaultScreeningWeight {
+        t.Fatalf("stored weights = %v, want 3 and the default weight", weights)
+    }
+
+    answersTo := func(yes ...string) []bool {
+        answers := make([]bool, len(questions))
+        for i, q := range questions {
+            answers[i] = slices.Contains(yes, q.Question)
+        }
+        return answers
+    }
+
+    score, err := ScoreScreening(ctx, "kid", questions, answersTo("Reverses letters"))
+    if err != nil {
+        t.Fatalf("ScoreScreening() error = %v", err)
+    }
+    if score.Score != 50 || score.RiskLevel != "moderate" {
+        t.Errorf("default rule: score %.2f, %s risk, want 50, moderate", score.Score, score.RiskLevel)
+    }
+
+    critical := questions[slices.IndexFunc(questions, func(q models.ScreeningQuestion) bool { return q.Question == "Loses place while reading" })]
+    _, err = UpdateScreeningRule(ctx, models.ScreeningRule{
+        AgeGroup:      "kid",
+        LowMax:        30,
+        ModerateMax:   60,
+        Domains:       []models.ScreeningDomainRule{{Domain: "phonology", LowMax: 20, ModerateMax: 40}},
+        CriticalItems: []string{critical.ID},
+    }, "admin")
+    if err != nil {
+        t.Fatalf("UpdateScreeningRule() error = %v", err)
+    }
+
+    tests := []struct {
+        name         string
+        yes          []string
+        wantScore    float64
+        wantRisk     string
+        wantCritical []string
+        wantDrivers  []string
+    }{
+        {"nothing", nil, 0, "low", []string{}, []string{"phonology", "reading"}},
+        {"weighted overall", []string{"Reverses letters", "Struggles to rhyme"}, 66.67, "high", []string{}, []string{"phonology", "reading"}},
+        {"domain threshold", []string{"Confuses sounds"}, 16.67, "high", []string{}, []string{"phonology"}},
+        {"critical item", []string{"Loses place while reading"}, 16.67, "high", []string{critical.ID}, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            score, err := ScoreScreening(ctx, "kid", questions, answersTo(tt.yes...))
+            if err != nil {
+                t.Fatalf("ScoreScreening() error = %v", err)
+            }
+            if score.Score != tt.wantScore || score.RiskLevel != tt.wantRisk {
+                t.Errorf("score %.2f, %s risk, want %.2f, %s", score.Score, score.RiskLevel, tt.wantScore, tt.wantRisk)
+            }
+            if !slices.Equal(score.CriticalItems, tt.wantCritical) {
+                t.Errorf("critical items = %v, want %v", score.CriticalItems, tt.wantCritical)
+            }
+            var drivers []string
+            for _, domain := range score.Domains {
+                if domain.Driver {
+                    drivers = append(drivers, domain.Domain)
+                }
+            }
+            if !slices.Equal(drivers, tt.wantDrivers) {
+                t.Errorf("driving domains = %v, want %v", drivers, tt.wantDrivers)
+            }
+            if len(score.Reasons) == 0 {
+                t.Error("score has no reasons")
+            }
+        })
+    }
+
+    if _, err := ScoreScreening(ctx, "kid", questions, []bool{true}); err == nil {
+        t.Error("ScoreScreening() with too few answers succeeded, want an error")
+    }
+}
+
+func TestUpdateScreeningRuleValidation(t *testing.T) {
+    useMemoryRepository(t)
+    questions := saveScreeningQuestions(t, models.ScreeningQuestion{Question: "Reverses letters"})
+    tests := []struct {
+        name    string
+        rule    models.ScreeningRule
+        wantErr error
+    }{
+        {"unknown age group", models.ScreeningRule{AgeGroup: "teen", LowMax: 40, ModerateMax: 70}, ErrInvalidAgeGroup},
+        {"thresholds reversed", models.ScreeningRule{AgeGroup: "kid", LowMax: 70, ModerateMax: 40}, ErrInvalidScreeningRule},
+        {"threshold above 100", models.ScreeningRule{AgeGroup: "kid", LowMax: 40, ModerateMax: 120}, ErrInvalidScreeningRule},
+        {"duplicate domain", models.ScreeningRule{AgeGroup: "kid", LowMax: 40, ModerateMax: 70, Domains: []models.ScreeningDomainRule{{Domain: "reading", LowMax: 1, ModerateMax: 2}, {Domain: "reading", LowMax: 1, ModerateMax: 2}}}, ErrInvalidScreeningRule},
+        {"unknown risk level", models.ScreeningRule{AgeGroup: "kid", LowMax: 40, ModerateMax: 70, CriticalRiskLevel: "severe"}, ErrInvalidScreeningRule},
+        {"critical item of another age group", models.ScreeningRule{AgeGroup: "adult", LowMax: 40, ModerateMax: 70, CriticalItems: []string{questions[0].ID}}, ErrInvalidScreeningRule},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := UpdateScreeningRule(context.Background(), tt.rule, "admin"); !errors.Is(err, tt.wantErr) {
+                t.Errorf("UpdateScreeningRule() error = %v, want %v", err, tt.wantErr)
+            }
+        })
+    }
+
+    rule, err := UpdateScreeningRule(context.Background(), models.ScreeningRule{AgeGroup: "kid", LowMax: 40, ModerateMax: 70}, "admin")
+    if err != nil {
+        t.Fatalf("UpdateScreeningRule() error = %v", err)
+    }
+    if rule.CriticalRiskLevel != "high" || rule.UpdatedBy != "admin" {
+        t.Errorf("stored rule = %+v, want criticalRiskLevel high updated by admin", rule)
+    }
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "slices"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// saveScreeningQuestions stores kid screening questions and returns them in question order.
+func saveScreeningQuestions(t *testing.T, questions ...models.ScreeningQuestion) []models.ScreeningQuestion {
+    t.Helper()
+    ctx := context.Background()
+    for _, question := range questions {
+        question.AgeGroup = "kid"
+        if _, err := SaveScreeningQuestion(ctx, question, "admin"); err != nil {
+            t.Fatalf("SaveScreeningQuestion() error = %v", err)
+        }
+    }
+    saved, err := GetScreeningQuestions(ctx, "kid", "")
+    if err != nil {
+        t.Fatalf("GetScreeningQuestions() error = %v", err)
+    }
+    return saved
+}
+
+func TestScreeningQuestionSetVersion(t *testing.T) {
+    questions := []models.ScreeningQuestion{{ID: "a", Question: "Reverses letters", Weight: 1}}
+    version := ScreeningQuestionSetVersion(questions)
+
+    reweighted := []models.ScreeningQuestion{{ID: "a", Question: "Reverses letters", Weight: 5, Domain: "reading"}}
+    if got := ScreeningQuestionSetVersion(reweighted); got != version {
+        t.Errorf("version changed from %s to %s when only the weight and domain changed", version, got)
+    }
+    reworded := []models.ScreeningQuestion{{ID: "a", Question: "Often reverses letters"}}
+    if got := ScreeningQuestionSetVersion(reworded); got == version {
+        t.Error("version did not change when a question was reworded")
+    }
+    added := append(questions, models.ScreeningQuestion{ID: "b", Question: "Confuses sounds"})
+    if got := ScreeningQuestionSetVersion(added); got == version {
+        t.Error("version did not change when a question was added")
+    }
+}
+
+func TestBindScreeningAnswers(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    questions := saveScreeningQuestions(t,
+        models.ScreeningQuestion{Question: "Reverses letters"},
+        models.ScreeningQuestion{Question: "Confuses sounds"},
+    )
+    version := ScreeningQuestionSetVersion(questions)
+    first, second := questions[0], questions[1]
+
+    set, err := BindScreeningAnswers(ctx, models.ScreeningSubmission{AgeGroup: "kid", Version: version, Answers: []bool{true, false}})
+    if err != nil {
+        t.Fatalf("positional answers: error = %v", err)
+    }
+    if !slices.Equal(set.Answers, []bool{true, false}) || set.Version != version {
+        t.Errorf("positional answers bound to %v at %s, want [true false] at %s", set.Answers, set.Version, version)
+    }
+
+    set, err = BindScreeningAnswers(ctx, models.ScreeningSubmission{AgeGroup: "kid", Responses: []models.ScreeningAnswer{
+        {QuestionID: second.ID, Answer: true},
+        {QuestionID: first.ID, Answer: false},
+    }})
+    if err != nil {
+        t.Fatalf("responses without version: error = %v", err)
+    }
+    if !slices.Equal(set.Answers, []bool{false, true}) {
+        t.Errorf("responses bound to %v, want [false true]", set.Answers)
+    }
+
+    if err := DeleteScreeningQuestion(ctx, first.ID, "kid", "admin"); err != nil {
+        t.Fatalf("DeleteScreeningQuestion() error = %v", err)
+    }
+
+    set, err = BindScreeningAnswers(ctx, models.ScreeningSubmission{AgeGroup: "kid", Version: version, Responses: []models.ScreeningAnswer{
+        {QuestionID: first.ID, Answer: true},
+        {QuestionID: second.ID, Answer: true},
+    }})
+    if err != nil {
+        t.Fatalf("stale responses to a deleted question: error = %v", err)
+    }
+    if !slices.Equal(set.Answers, []bool{true}) || !slices.Equal(set.Ignored, []string{first.ID}) {
+        t.Errorf("stale responses bound to %v ignoring %v, want [true] ignoring [%s]", set.Answers, set.Ignored, first.ID)
+    }
+
+    tests := []struct {
+        name       string
+        submission models.ScreeningSubmission
+        wantErr    error
+    }{
+        {"stale positional answers", models.ScreeningSubmission{AgeGroup: "kid", Version: version, Answers: []bool{true}}, ErrScreeningSetChanged},
+        {"unversioned positional answers", models.ScreeningSubmission{AgeGroup: "kid", Answers: []bool{true}}, ErrScreeningSetChanged},
+        {"wrong answer count", models.ScreeningSubmission{AgeGroup: "kid", Version: set.Version, Answers: []bool{true, false}}, ErrInvalidScreeningAnswers},
+        {"answers and responses", models.ScreeningSubmission{AgeGroup: "kid", Answers: []bool{true}, Responses: []models.ScreeningAnswer{{QuestionID: second.ID}}}, ErrInvalidScreeningAnswers},
+        {"duplicate responses", models.ScreeningSubmission{AgeGroup: "kid", Responses: []models.ScreeningAnswer{{QuestionID: second.ID}, {QuestionID: second.ID}}}, ErrInvalidScreeningAnswers},
+        {"current version with unknown question", models.ScreeningSubmission{AgeGroup: "kid", Version: set.Version, Responses: []models.ScreeningAnswer{{QuestionID: second.ID}, {QuestionID: first.ID}}}, ErrInvalidScreeningAnswers},
+        {"unknown age group", models.ScreeningSubmission{AgeGroup: "teen", Answers: []bool{true}}, ErrInvalidAgeGroup},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := BindScreeningAnswers(ctx, tt.submission); !errors.Is(err, tt.wantErr) {
+                t.Errorf("BindScreeningAnswers() error = %v, want %v", err, tt.wantErr)
+            }
+        })
+    }
+
+    saveScreeningQuestions(t, models.ScreeningQuestion{Question: "Struggles to rhyme"})
+    _, err = BindScreeningAnswers(ctx, models.ScreeningSubmission{AgeGroup: "kid", Version: set.Version, Responses: []models.ScreeningAnswer{{QuestionID: second.ID}}})
+    if !errors.Is(err, ErrScreeningSetChanged) {
+        t.Errorf("responses missing a new question: error = %v, want ErrScreeningSetChanged", err)
+    }
+}
+
+func TestSaveScreeningResultTrend(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    set := models.ScreeningAnswerSet{Questions: []models.ScreeningQuestion{{ID: "a"}}, Answers: []bool{true}}
+
+    wantTrends := []struct {
+        risk  string
+        trend string
+    }{
+        {"moderate", models.ScreeningTrendFirst},
+        {"high", models.ScreeningTrendWorsened},
+        {"high", models.ScreeningTrendUnchanged},
+        {"low", models.ScreeningTrendImproved},
+    }
+    for i, want := range wantTrends {
+        result, err := SaveScreeningResult(ctx, "user", "kid", set, models.ScreeningScore{RiskLevel: want.risk}, "")
+        if err != nil {
+            t.Fatalf("SaveScreeningResult() error = %v", err)
+        }
+        if result.Trend != want.trend {
+            t.Errorf("screening %d trend = %s, want %s", i+1, result.Trend, want.trend)
+        }
+    }
+
+    history, err := GetScreeningHistory(ctx, "user")
+    if err != nil {
+        t.Fatalf("GetScreeningHistory() error = %v", err)
+    }
+    if len(history.History) != len(wantTrends) || history.Latest == nil || history.Latest.RiskLevel != "low" {
+        t.Fatalf("history = %d results, latest %+v, want %d results with the low risk result latest", len(history.History), history.Latest, len(wantTrends))
+    }
+    if history.History[len(history.History)-1].Trend != models.ScreeningTrendFirst {
+        t.Errorf("oldest result trend = %s, want %s", history.History[len(history.History)-1].Trend, models.ScreeningTrendFirst)
+    }
+}
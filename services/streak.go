@@ -0,0 +1,95 @@
+package services
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// Streak freeze tokens are earned every freezeEarnInterval streak days, up to maxFreezeTokens.
+const (
+    freezeEarnInterval = 7
+    maxFreezeTokens    = 2
+)
+
+// daysBetween returns the number of calendar days from one midnight to another.
+func daysBetween(from, to time.Time) int {
+    return int(to.Sub(from).Hours() / 24)
+}
+
+// extendStreak counts date toward the user's streak. Missed days since the last streak day are
+// covered by freeze tokens when enough are available; otherwise the streak restarts.
+func extendStreak(streak *models.StreakSummary, date time.Time) {
+    if !streak.LastStreakDate.IsZero() && !date.After(streak.LastStreakDate) {
+        return
+    }
+
+    switch {
+    case streak.LastStreakDate.IsZero():
+        streak.CurrentStreak = 1
+    default:
+        missed := daysBetween(streak.LastStreakDate, date) - 1
+        if missed <= streak.FreezeTokens {
+            streak.FreezeTokens -= missed
+            streak.FreezesUsed += missed
+            streak.CurrentStreak++
+        } else {
+            streak.CurrentStreak = 1
+        }
+    }
+
+    streak.LastStreakDate = date
+    streak.LongestStreak = max(streak.LongestStreak, streak.CurrentStreak)
+    if streak.CurrentStreak%freezeEarnInterval == 0 && streak.FreezeTokens < maxFreezeTokens {
+        streak.FreezeTokens++
+    }
+}
+
+// currentStreak reports the streak as of today. A streak whose missed days exceed the available
+// freeze tokens is reported as broken, although it is only reset when the next streak day is counted.
+func currentStreak(streak models.StreakSummary, today time.Time) models.StreakSummary {
+    if streak.LastStreakDate.IsZero() {
+        streak.CurrentStreak = 0
+        return streak
+    }
+    gap := daysBetween(streak.LastStreakDate, today)
+    streak.AchievedToday = gap == 0
+    if gap-1 > streak.FreezeTokens {
+        streak.CurrentStreak = 0
+    }
+    return streak
+}
+
+// GetProgressSummary retrieves the user's streak and progress toward the daily goal for today in loc.
+func GetProgressSummary(ctx context.Context, userID string, loc *time.Location) (models.ProgressSummary, error) {
+    today := localDate(time.Now(), loc)
+
+    streak, err := repo.GetStreakSummary(ctx, userID)
+    if err != nil {
+        return models.ProgressSummary{}, fmt.Errorf("failed to retrieve streak summary: %w", err)
+    }
+    entries, err := repo.ListDailyProgress(ctx, userID, today, today)
+    if err != nil {
+        return models.ProgressSummary{}, fmt.Errorf("failed to retrieve today's progress: %w", err)
+    }
+    goal, err := GetDailyGoal(ctx, userID)
+    if err != nil {
+        return models.ProgressSummary{}, fmt.Errorf("failed to retrieve daily goal: %w", err)
+    }
+
+    summary := models.ProgressSummary{
+        Streak:    currentStreak(streak, today),
+        Today:     models.DailyProgress{UserID: userID, Date: today},
+        DailyGoal: goal,
+    }
+    if len(entries) > 0 {
+        summary.Today = entries[0]
+    }
+    measureProgress(&summary.Today, goal)
+
+    log.Printf("Retrieved progress summary for userID: %s, current streak: %d, longest: %d", userID, summary.Streak.CurrentStreak, summary.Streak.LongestStreak)
+    return summary, nil
+}
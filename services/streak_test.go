@@ -0,0 +1,87 @@
+package services
+
+import (
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// day returns midnight UTC of the given day of April 2025.
+func day(d int) time.Time {
+    return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestExtendStreak(t *testing.T) {
+    var streak models.StreakSummary
+    for d := 1; d <= 7; d++ {
+        extendStreak(&streak, day(d))
+    }
+    if streak.CurrentStreak != 7 || streak.LongestStreak != 7 || streak.FreezeTokens != 1 {
+        t.Fatalf("after 7 days: streak %+v, want current 7, longest 7 and 1 freeze token", streak)
+    }
+
+    extendStreak(&streak, day(7))
+    if streak.CurrentStreak != 7 {
+        t.Fatalf("counting the same day twice: current streak %d, want 7", streak.CurrentStreak)
+    }
+
+    // Missing April 8 uses the freeze token.
+    extendStreak(&streak, day(9))
+    if streak.CurrentStreak != 8 || streak.FreezeTokens != 0 || streak.FreezesUsed != 1 {
+        t.Fatalf("after a covered gap: streak %+v, want current 8, no tokens and 1 freeze used", streak)
+    }
+
+    // Missing April 10 without a token restarts the streak.
+    extendStreak(&streak, day(11))
+    if streak.CurrentStreak != 1 || streak.LongestStreak != 8 || streak.FreezesUsed != 1 {
+        t.Fatalf("after an uncovered gap: streak %+v, want current 1, longest 8 and 1 freeze used", streak)
+    }
+}
+
+func TestExtendStreakFreezeTokenCap(t *testing.T) {
+    var streak models.StreakSummary
+    start := day(1)
+    for d := 0; d < 4*freezeEarnInterval; d++ {
+        extendStreak(&streak, start.AddDate(0, 0, d))
+    }
+    if streak.FreezeTokens != maxFreezeTokens {
+        t.Errorf("freeze tokens = %d, want %d", streak.FreezeTokens, maxFreezeTokens)
+    }
+
+    // Missing more days than there are tokens keeps the tokens and restarts the streak.
+    extendStreak(&streak, start.AddDate(0, 0, 4*freezeEarnInterval+maxFreezeTokens+1))
+    if streak.CurrentStreak != 1 || streak.FreezeTokens != maxFreezeTokens {
+        t.Errorf("after a long gap: streak %+v, want current 1 and %d tokens", streak, maxFreezeTokens)
+    }
+}
+
+func TestCurrentStreak(t *testing.T) {
+    streak := models.StreakSummary{CurrentStreak: 5, LongestStreak: 5, LastStreakDate: day(10), FreezeTokens: 1}
+    tests := []struct {
+        name              string
+        today             time.Time
+        wantCurrent       int
+        wantAchievedToday bool
+    }{
+        {"achieved today", day(10), 5, true},
+        {"not yet today", day(11), 5, false},
+        {"gap covered by freeze", day(12), 5, false},
+        {"gap too long", day(13), 0, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := currentStreak(streak, tt.today)
+            if got.CurrentStreak != tt.wantCurrent || got.AchievedToday != tt.wantAchievedToday {
+                t.Errorf("currentStreak() = current %d, achieved today %v, want %d, %v", got.CurrentStreak, got.AchievedToday, tt.wantCurrent, tt.wantAchievedToday)
+            }
+            if got.LongestStreak != 5 {
+                t.Errorf("longest streak = %d, want 5", got.LongestStreak)
+            }
+        })
+    }
+
+    if got := currentStreak(models.StreakSummary{}, day(10)); got.CurrentStreak != 0 {
+        t.Errorf("currentStreak() without streak days = %d, want 0", got.CurrentStreak)
+    }
+}
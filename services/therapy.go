@@ -2,83 +2,23 @@ package services
 
 import (
     "context"
+    "errors"
     "fmt"
     "log"
+    "slices"
     "time"
 
-    "cloud.google.com/go/firestore"
     "github.com/dzuura/neurodyx-be/models"
-    "google.golang.org/grpc/codes"
-    "google.golang.org/grpc/status"
+    "github.com/dzuura/neurodyx-be/repository"
 )
 
 // GetTherapyQuestions retrieves therapy questions by type and category.
 func GetTherapyQuestions(ctx context.Context, questionType, category string, userID string) ([]models.TherapyQuestion, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    docs, err := firestoreClient.Collection("therapyQuestions").Doc(questionType).Collection(category).Documents(ctx).GetAll()
+    questions, skipped, err := repo.ListTherapyQuestions(ctx, questionType, category)
     if err != nil {
         return nil, fmt.Errorf("failed to retrieve therapy questions: %w", err)
     }
-
-    questions := make([]models.TherapyQuestion, 0, len(docs))
-    for _, doc := range docs {
-        var q models.TherapyQuestion
-        data := doc.Data()
-        q.ID = doc.Ref.ID
-        q.Type = data["type"].(string)
-        q.Category = data["category"].(string)
-        if content, ok := data["content"].(string); ok {
-            q.Content = content
-        }
-        if description, ok := data["description"].(string); ok {
-            q.Description = description
-        }
-        if imageURL, ok := data["imageURL"].(string); ok {
-            q.ImageURL = imageURL
-        }
-        if soundURL, ok := data["soundURL"].(string); ok {
-            q.SoundURL = soundURL
-        }
-        if options, ok := data["options"].([]interface{}); ok {
-            q.Options = make([]string, len(options))
-            for i, opt := range options {
-                q.Options[i] = opt.(string)
-            }
-        }
-        if leftItems, ok := data["leftItems"].([]interface{}); ok {
-            q.LeftItems = make([]string, len(leftItems))
-            for i, item := range leftItems {
-                q.LeftItems[i] = item.(string)
-            }
-        }
-        if rightItems, ok := data["rightItems"].([]interface{}); ok {
-            q.RightItems = make([]string, len(rightItems))
-            for i, item := range rightItems {
-                q.RightItems[i] = item.(string)
-            }
-        }
-        if correctAnswer, ok := data["correctAnswer"].(string); ok {
-            q.CorrectAnswer = correctAnswer
-        }
-        if correctSeq, ok := data["correctSequence"].([]interface{}); ok {
-            q.CorrectSequence = make([]string, len(correctSeq))
-            for i, seq := range correctSeq {
-                q.CorrectSequence[i] = seq.(string)
-            }
-        }
-        if correctPairs, ok := data["correctPairs"].(map[string]interface{}); ok {
-            q.CorrectPairs = make(map[string]string)
-            for k, v := range correctPairs {
-                q.CorrectPairs[k] = v.(string)
-            }
-        }
-        questions = append(questions, q)
-    }
+    logSkippedQuestions(skipped, "therapy", "type "+questionType+", category "+category)
 
     log.Printf("Retrieved %d therapy questions for type: %s, category: %s", len(questions), questionType, category)
     return questions, nil
@@ -86,331 +26,132 @@ func GetTherapyQuestions(ctx context.Context, questionType, category string, use
 
 // GetTherapyQuestionByID retrieves a therapy question by its ID.
 func GetTherapyQuestionByID(ctx context.Context, questionType, category, questionID string) (models.TherapyQuestion, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return models.TherapyQuestion{}, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    doc, err := firestoreClient.Collection("therapyQuestions").Doc(questionType).Collection(category).Doc(questionID).Get(ctx)
+    q, err := repo.GetTherapyQuestion(ctx, questionType, category, questionID)
     if err != nil {
         return models.TherapyQuestion{}, fmt.Errorf("failed to retrieve therapy question: %w", err)
     }
 
-    var q models.TherapyQuestion
-    data := doc.Data()
-    q.ID = doc.Ref.ID
-    q.Type = data["type"].(string)
-    q.Category = data["category"].(string)
-    if content, ok := data["content"].(string); ok {
-        q.Content = content
-    }
-    if description, ok := data["description"].(string); ok {
-        q.Description = description
-    }
-    if imageURL, ok := data["imageURL"].(string); ok {
-        q.ImageURL = imageURL
-    }
-    if soundURL, ok := data["soundURL"].(string); ok {
-        q.SoundURL = soundURL
-    }
-    if options, ok := data["options"].([]interface{}); ok {
-        q.Options = make([]string, len(options))
-        for i, opt := range options {
-            q.Options[i] = opt.(string)
-        }
-    }
-    if leftItems, ok := data["leftItems"].([]interface{}); ok {
-        q.LeftItems = make([]string, len(leftItems))
-        for i, item := range leftItems {
-            q.LeftItems[i] = item.(string)
-        }
-    }
-    if rightItems, ok := data["rightItems"].([]interface{}); ok {
-        q.RightItems = make([]string, len(rightItems))
-        for i, item := range rightItems {
-            q.RightItems[i] = item.(string)
-        }
-    }
-    if correctAnswer, ok := data["correctAnswer"].(string); ok {
-        q.CorrectAnswer = correctAnswer
-    }
-    if correctSeq, ok := data["correctSequence"].([]interface{}); ok {
-        q.CorrectSequence = make([]string, len(correctSeq))
-        for i, seq := range correctSeq {
-            q.CorrectSequence[i] = seq.(string)
-        }
-    }
-    if correctPairs, ok := data["correctPairs"].(map[string]interface{}); ok {
-        q.CorrectPairs = make(map[string]string)
-        for k, v := range correctPairs {
-            q.CorrectPairs[k] = v.(string)
-        }
-    }
-
     log.Printf("Retrieved therapy question with ID: %s, type: %s, category: %s", questionID, questionType, category)
     return q, nil
 }
 
 // GetTherapyCategories retrieves all available categories for a given type with descriptions.
 func GetTherapyCategories(ctx context.Context, questionType string) ([]models.TherapyCategory, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
+    categoryList, err := repo.ListTherapyCategories(ctx, questionType)
     if err != nil {
-        return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    categoriesIter := firestoreClient.Collection("therapyQuestions").Doc(questionType).Collections(ctx)
-    categories, err := categoriesIter.GetAll()
-    if err != nil {
-        return nil, fmt.Errorf("failed to retrieve categories for type %s: %w", questionType, err)
-    }
-
-    categoryList := make([]models.TherapyCategory, 0)
-    for _, category := range categories {
-        categoryName := category.ID
-        docs, err := category.Limit(1).Documents(ctx).GetAll()
-        if err != nil || len(docs) == 0 {
-            continue
-        }
-        data := docs[0].Data()
-        if description, ok := data["description"].(string); ok {
-            categoryList = append(categoryList, models.TherapyCategory{
-                Category:    categoryName,
-                Description: description,
-            })
-        }
+        return nil, err
     }
 
     log.Printf("Retrieved %d categories for type: %s", len(categoryList), questionType)
     return categoryList, nil
 }
 
-// SaveTherapyQuestion saves a new therapy question to Firestore.
+// SaveTherapyQuestion saves a new therapy question.
 func SaveTherapyQuestion(ctx context.Context, question models.TherapyQuestion, userID string) (string, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
+    format, err := checkAnswerKey(question.AnswerFormat, therapyAnswerKey(question))
     if err != nil {
-        return "", fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    docRef, _, err := firestoreClient.Collection("therapyQuestions").Doc(question.Type).Collection(question.Category).Add(ctx, map[string]interface{}{
-        "type":            question.Type,
-        "category":        question.Category,
-        "content":         question.Content,
-        "description":     question.Description,
-        "imageURL":        question.ImageURL,
-        "soundURL":        question.SoundURL,
-        "options":         question.Options,
-        "leftItems":       question.LeftItems,
-        "rightItems":      question.RightItems,
-        "correctAnswer":   question.CorrectAnswer,
-        "correctSequence": question.CorrectSequence,
-        "correctPairs":    question.CorrectPairs,
-        "timestamp":       firestore.ServerTimestamp,
-    })
+        return "", err
+    }
+    question.AnswerFormat = format
+    if question.Difficulty, err = checkDifficulty(question.Difficulty); err != nil {
+        return "", err
+    }
+
+    questionID, err := repo.CreateTherapyQuestion(ctx, question)
     if err != nil {
         return "", fmt.Errorf("failed to save therapy question: %w", err)
     }
+    indexQuestion(ctx, models.QuestionKindTherapy, questionID, question.Type, question.Category)
 
-    log.Printf("Saved therapy question with ID: %s, type: %s, category: %s", docRef.ID, question.Type, question.Category)
-    return docRef.ID, nil
+    log.Printf("Saved therapy question with ID: %s, type: %s, category: %s", questionID, question.Type, question.Category)
+    return questionID, nil
 }
 
-// UpdateTherapyQuestion updates an existing therapy question in Firestore.
+// UpdateTherapyQuestion updates an existing therapy question.
 func UpdateTherapyQuestion(ctx context.Context, questionID string, question models.TherapyQuestion, userID string) error {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    _, err = firestoreClient.Collection("therapyQuestions").Doc(question.Type).Collection(question.Category).Doc(questionID).Set(ctx, map[string]interface{}{
-        "type":            question.Type,
-        "category":        question.Category,
-        "content":         question.Content,
-        "description":     question.Description,
-        "imageURL":        question.ImageURL,
-        "soundURL":        question.SoundURL,
-        "options":         question.Options,
-        "correctAnswer":   question.CorrectAnswer,
-        "correctSequence": question.CorrectSequence,
-        "correctPairs":    question.CorrectPairs,
-        "timestamp":       firestore.ServerTimestamp,
-    }, firestore.MergeAll)
+    format, err := checkAnswerKey(question.AnswerFormat, therapyAnswerKey(question))
     if err != nil {
+        return err
+    }
+    question.AnswerFormat = format
+    if question.Difficulty, err = checkDifficulty(question.Difficulty); err != nil {
+        return err
+    }
+
+    if err := repo.UpdateTherapyQuestion(ctx, questionID, question); err != nil {
         return fmt.Errorf("failed to update therapy question: %w", err)
     }
+    indexQuestion(ctx, models.QuestionKindTherapy, questionID, question.Type, question.Category)
 
     log.Printf("Updated therapy question with ID: %s, type: %s, category: %s", questionID, question.Type, question.Category)
     return nil
 }
 
-// DeleteTherapyQuestion deletes a therapy question from Firestore.
+// DeleteTherapyQuestion deletes a therapy question.
 func DeleteTherapyQuestion(ctx context.Context, questionID, questionType, category, userID string) error {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    _, err = firestoreClient.Collection("therapyQuestions").Doc(questionType).Collection(category).Doc(questionID).Delete(ctx)
-    if err != nil {
+    if err := repo.DeleteTherapyQuestion(ctx, questionType, category, questionID); err != nil {
         return fmt.Errorf("failed to delete therapy question: %w", err)
     }
+    unindexQuestion(ctx, questionID)
 
     log.Printf("Deleted therapy question with ID: %s, type: %s, category: %s", questionID, questionType, category)
     return nil
 }
 
-// SaveTherapyResult saves the user's therapy result with flexible answer validation.
-func SaveTherapyResult(ctx context.Context, userID string, submission models.TherapySubmission, firebaseToken string) (models.TherapyResult, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return models.TherapyResult{}, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    var question models.TherapyQuestion
-    types := []string{"visual", "auditory", "kinesthetic", "tactile"}
-    for _, t := range types {
-        categoriesIter := firestoreClient.Collection("therapyQuestions").Doc(t).Collections(ctx)
-        categories, err := categoriesIter.GetAll()
-        if err != nil {
-            continue
-        }
-        for _, category := range categories {
-            doc, err := category.Doc(submission.QuestionID).Get(ctx)
-            if err == nil && doc.Exists() {
-                data := doc.Data()
-                question.ID = doc.Ref.ID
-                question.Type = data["type"].(string)
-                question.Category = data["category"].(string)
-                if options, ok := data["options"].([]interface{}); ok {
-                    question.Options = make([]string, len(options))
-                    for i, opt := range options {
-                        question.Options[i] = opt.(string)
-                    }
-                }
-                if correctAnswer, ok := data["correctAnswer"].(string); ok {
-                    question.CorrectAnswer = correctAnswer
-                }
-                if correctSeq, ok := data["correctSequence"].([]interface{}); ok {
-                    question.CorrectSequence = make([]string, len(correctSeq))
-                    for i, seq := range correctSeq {
-                        question.CorrectSequence[i] = seq.(string)
-                    }
-                }
-                if correctPairs, ok := data["correctPairs"].(map[string]interface{}); ok {
-                    question.CorrectPairs = make(map[string]string)
-                    for k, v := range correctPairs {
-                        question.CorrectPairs[k] = v.(string)
-                    }
-                }
-                break
-            }
-        }
-        if question.ID != "" {
-            break
-        }
-    }
-
-    if question.ID == "" {
+// SaveTherapyResult saves the user's therapy result with flexible answer validation and schedules
+// the question's next review in loc.
+func SaveTherapyResult(ctx context.Context, userID string, submission models.TherapySubmission, firebaseToken string, loc *time.Location) (models.TherapyResult, error) {
+    question, err := FindTherapyQuestion(ctx, submission.QuestionID)
+    if errors.Is(err, repository.ErrNotFound) {
         return models.TherapyResult{}, fmt.Errorf("therapy question with ID %s not found", submission.QuestionID)
     }
+    if err != nil {
+        return models.TherapyResult{}, fmt.Errorf("failed to retrieve therapy question: %w", err)
+    }
 
-    isCorrect := false
-    switch question.Category {
-    case "word_repetition", "word_recognition_by_touch", "complete_the_word_by_touch":
-        answerStr, ok := submission.Answer.(string)
-        if ok {
-            isCorrect = ValidateStringMatch(answerStr, question.CorrectAnswer)
-        }
-    case "letter_matching":
-        answerSeq, ok := submission.Answer.([]interface{})
-        if ok {
-            seq := make([]string, len(answerSeq))
-            for i, v := range answerSeq {
-                seq[i], _ = v.(string)
-            }
-            isCorrect = ValidateSequence(seq, question.CorrectSequence)
-        }
-    case "number_letter_similarity":
-        answerPairsRaw, ok := submission.Answer.([]interface{})
-        if ok {
-            answerPairs := make([]map[string]string, len(answerPairsRaw))
-            for i, pairRaw := range answerPairsRaw {
-                pairMap, pairOk := pairRaw.(map[string]interface{})
-                if !pairOk {
-                    log.Printf("Invalid pair format for questionID: %s", submission.QuestionID)
-                    break
-                }
-                left, leftOk := pairMap["left"].(string)
-                right, rightOk := pairMap["right"].(string)
-                if !leftOk || !rightOk {
-                    log.Printf("Invalid left or right value for questionID: %s", submission.QuestionID)
-                    break
-                }
-                answerPairs[i] = map[string]string{
-                    "left":  left,
-                    "right": right,
-                }
-            }
-            isCorrect = ValidatePairs(answerPairs, question.CorrectPairs)
-        } else {
-            log.Printf("Expected []interface{} for number_letter_similarity, got %T for questionID: %s", submission.Answer, submission.QuestionID)
-        }
-    default:
-        answerStr, ok := submission.Answer.(string)
-        if ok && len(question.Options) > 0 {
-            for _, opt := range question.Options {
-                if opt == answerStr {
-                    isCorrect = true
-                    break
-                }
-            }
-        }
+    score, err := validateAnswer(question.AnswerFormat, therapyAnswerKey(question), submission.Answer)
+    if err != nil {
+        return models.TherapyResult{}, fmt.Errorf("failed to validate answer for question %s: %w", submission.QuestionID, err)
     }
 
     result := models.TherapyResult{
         Type:           question.Type,
         Category:       question.Category,
-        CorrectAnswers: 1,
+        Score:          score.Score,
         TotalQuestions: 1,
+        Answers:        []models.AnswerFeedback{{QuestionID: submission.QuestionID, AnswerScore: score}},
     }
-    if !isCorrect {
-        result.CorrectAnswers = 0
+    if score.Correct {
+        result.CorrectAnswers = 1
     }
 
-    _, err = firestoreClient.Collection("users").Doc(userID).Collection("therapy").Doc(question.Type).Collection(question.Category).Doc(submission.QuestionID).Set(ctx, map[string]interface{}{
-        "type":           question.Type,
-        "category":       question.Category,
-        "questionID":     submission.QuestionID,
-        "correctAnswers": result.CorrectAnswers,
-        "answer":         submission.Answer,
-        "status":         "completed",
-        "timestamp":      firestore.ServerTimestamp,
-    }, firestore.MergeAll)
+    err = repo.SaveTherapySubmission(ctx, userID, models.TherapySubmissionRecord{
+        QuestionID:     submission.QuestionID,
+        Type:           question.Type,
+        Category:       question.Category,
+        CorrectAnswers: result.CorrectAnswers,
+        Score:          score.Score,
+        Items:          score.Items,
+        Match:          score.Match,
+        Answer:         submission.Answer,
+        Status:         "completed",
+    })
     if err != nil {
         return models.TherapyResult{}, fmt.Errorf("failed to save therapy result: %w", err)
     }
+    recordReview(ctx, userID, question, score.Score, loc)
 
-    log.Printf("Saved therapy result for userID: %s, questionID: %s, type: %s, category: %s, isCorrect: %v", userID, submission.QuestionID, question.Type, question.Category, isCorrect)
+    log.Printf("Saved therapy result for userID: %s, questionID: %s, type: %s, category: %s, isCorrect: %v, score: %.2f", userID, submission.QuestionID, question.Type, question.Category, score.Correct, score.Score)
     return result, nil
 }
 
 // GetTherapyResults retrieves therapy results for a user by type and category.
 func GetTherapyResults(ctx context.Context, userID, questionType, category string) (models.TherapyResult, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return models.TherapyResult{}, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
     totalQuestions := 0
-    docs, err := firestoreClient.Collection("therapyQuestions").Doc(questionType).Collection(category).Documents(ctx).GetAll()
+    questions, skipped, err := repo.ListTherapyQuestions(ctx, questionType, category)
     if err == nil {
-        totalQuestions = len(docs)
+        totalQuestions = len(questions)
+        logSkippedQuestions(skipped, "therapy", "type "+questionType+", category "+category)
     }
 
     result := models.TherapyResult{
@@ -421,9 +162,9 @@ func GetTherapyResults(ctx context.Context, userID, questionType, category strin
         Status:         "not started",
     }
 
-    submissionDocs, err := firestoreClient.Collection("users").Doc(userID).Collection("therapy").Doc(questionType).Collection(category).Documents(ctx).GetAll()
+    submissions, err := repo.ListTherapySubmissions(ctx, userID, questionType, category)
     if err != nil {
-        if status.Code(err) == codes.NotFound {
+        if errors.Is(err, repository.ErrNotFound) {
             log.Printf("No submissions found for type %s, category %s for userID: %s", questionType, category, userID)
             return result, nil
         }
@@ -431,17 +172,15 @@ func GetTherapyResults(ctx context.Context, userID, questionType, category strin
     }
 
     correctAnswers := 0
-    for _, subDoc := range submissionDocs {
-        data := subDoc.Data()
-        if correct, ok := data["correctAnswers"].(int64); ok {
-            correctAnswers += int(correct)
-        } else if correct, ok := data["correctAnswers"].(int); ok {
-            correctAnswers += correct
-        }
+    score := 0.0
+    for _, sub := range submissions {
+        correctAnswers += sub.CorrectAnswers
+        score += sub.Score
     }
 
     result.CorrectAnswers = correctAnswers
-    if len(submissionDocs) > 0 {
+    result.Score = score
+    if len(submissions) > 0 {
         result.Status = "completed"
     }
 
@@ -449,154 +188,184 @@ func GetTherapyResults(ctx context.Context, userID, questionType, category strin
     return result, nil
 }
 
-// UpdateDailyProgress updates the user's daily progress for therapy activities.
-func UpdateDailyProgress(ctx context.Context, userID, questionType, category string) (*models.DailyProgress, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
+// UpdateDailyProgress credits one therapy session made of the scored results of a submission
+// batch, and the seconds spent on it, to the user's progress for the current date in loc. Each
+// result is counted under its own question's type and category, and its correct answers are added
+// to the running totals in the streak summary. The day is measured against the user's current
+// daily goal and counts toward the streak once it is met.
+func UpdateDailyProgress(ctx context.Context, userID string, results []models.TherapyResult, secondsSpent int, loc *time.Location) (*models.DailyProgress, error) {
+    if len(results) == 0 {
+        return nil, errors.New("no scored therapy results to credit")
+    }
+    date := localDate(time.Now(), loc)
+
+    goal, err := GetDailyGoal(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve daily goal: %w", err)
+    }
+    summary, err := repo.GetStreakSummary(ctx, userID)
     if err != nil {
-        return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
-    now := time.Now().UTC()
-    date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-    docID := date.Format("20060102")
-    docRef := firestoreClient.Collection("users").Doc(userID).Collection("progress").Doc(docID)
-
-    var progress models.DailyProgress
-    err = firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
-        doc, err := tx.Get(docRef)
-        if err != nil && !doc.Exists() {
-            progress = models.DailyProgress{
-                UserID:         userID,
-                Date:           date,
-                TherapyCount:   1,
-                StreakAchieved: false,
-            }
-        } else if err != nil {
-            return fmt.Errorf("failed to fetch progress: %w", err)
-        } else {
-            if err := doc.DataTo(&progress); err != nil {
-                return fmt.Errorf("failed to parse progress data: %w", err)
-            }
-            progress.TherapyCount++
+        return nil, fmt.Errorf("failed to retrieve streak summary: %w", err)
+    }
+    var backfill map[string]int
+    if summary.CorrectAnswers == nil {
+        if backfill, err = therapyCorrectTotals(ctx, userID); err != nil {
+            return nil, err
         }
+    }
 
-        progress.StreakAchieved = progress.TherapyCount >= 5
+    progress, streak, err := repo.UpdateDailyProgress(ctx, userID, date, func(progress *models.DailyProgress, streak *models.StreakSummary) error {
+        if streak.CorrectAnswers == nil {
+            streak.CorrectAnswers = backfill
+        }
+        progress.TherapyCount++
+        progress.SecondsSpent += secondsSpent
+        creditedTypes := make(map[string]bool)
+        creditedCategories := make(map[string]bool)
+        for _, result := range results {
+            progress.CorrectAnswers += result.CorrectAnswers
+            progress.TotalQuestions += result.TotalQuestions
+            progress.Types = creditActivity(progress.Types, result.Type, "", result, creditedTypes)
+            progress.Categories = creditActivity(progress.Categories, result.Type, result.Category, result, creditedCategories)
+            streak.CorrectAnswers[result.Type] += result.CorrectAnswers
+        }
 
-        progressData := map[string]interface{}{
-            "userID":         progress.UserID,
-            "date":           progress.Date,
-            "therapyCount":   progress.TherapyCount,
-            "streakAchieved": progress.StreakAchieved,
+        progress.Goal = goal
+        measureProgress(progress, goal)
+        progress.StreakAchieved = goalMet(*progress)
+        if progress.StreakAchieved {
+            extendStreak(streak, date)
         }
-        return tx.Set(docRef, progressData)
+        return nil
     })
     if err != nil {
         return nil, fmt.Errorf("failed to update daily progress: %w", err)
     }
 
-    log.Printf("Updated daily progress for userID: %s on %s, therapyCount: %d, streak: %v", userID, date.Format("2006-01-02"), progress.TherapyCount, progress.StreakAchieved)
+    log.Printf("Updated daily progress for userID: %s on %s, therapyCount: %d, correct: %d/%d, goal: %d %s, completion: %.2f%%, streak: %v, current streak: %d", userID, date.Format("2006-01-02"), progress.TherapyCount, progress.CorrectAnswers, progress.TotalQuestions, goal.Target, goal.Metric, progress.CompletionPercent, progress.StreakAchieved, streak.CurrentStreak)
     return &progress, nil
 }
 
-// GetWeeklyProgress retrieves the user's progress for the last 7 days.
-func GetWeeklyProgress(ctx context.Context, userID string) ([]models.DailyProgress, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
+// therapyCorrectTotals sums the user's correct therapy answers per type over every day of progress.
+// It backfills streak summaries stored before the running totals were kept.
+func therapyCorrectTotals(ctx context.Context, userID string) (map[string]int, error) {
+    progress, err := repo.ListDailyProgress(ctx, userID, time.Time{}, time.Now().UTC().AddDate(0, 0, 1))
     if err != nil {
-        return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
+        return nil, fmt.Errorf("failed to retrieve daily progress: %w", err)
+    }
+    totals := make(map[string]int)
+    for _, day := range progress {
+        for _, t := range day.Types {
+            totals[t.Type] += t.CorrectAnswers
+        }
+    }
+    return totals, nil
+}
+
+// creditActivity adds result to the breakdown entry for questionType and category, appending the
+// entry if needed. The entry's session count grows once per batch, tracked through credited.
+func creditActivity(breakdown []models.ActivityBreakdown, questionType, category string, result models.TherapyResult, credited map[string]bool) []models.ActivityBreakdown {
+    i := slices.IndexFunc(breakdown, func(b models.ActivityBreakdown) bool {
+        return b.Type == questionType && b.Category == category
+    })
+    if i < 0 {
+        breakdown = append(breakdown, models.ActivityBreakdown{Type: questionType, Category: category})
+        i = len(breakdown) - 1
+    }
+
+    key := questionType + "/" + category
+    if !credited[key] {
+        breakdown[i].Sessions++
+        credited[key] = true
     }
-    defer firestoreClient.Close()
+    breakdown[i].CorrectAnswers += result.CorrectAnswers
+    breakdown[i].TotalQuestions += result.TotalQuestions
+    return breakdown
+}
 
-    now := time.Now().UTC()
-    endDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+// GetWeeklyProgress retrieves the user's progress for the 7 days ending today in loc together
+// with their streak.
+func GetWeeklyProgress(ctx context.Context, userID string, loc *time.Location) (models.WeeklyProgress, error) {
+    endDate := localDate(time.Now(), loc)
     startDate := endDate.AddDate(0, 0, -6)
 
-    docs, err := firestoreClient.Collection("users").Doc(userID).Collection("progress").
-        Where("date", ">=", startDate).
-        Where("date", "<=", endDate).
-        Documents(ctx).GetAll()
+    entries, err := repo.ListDailyProgress(ctx, userID, startDate, endDate)
     if err != nil {
-        return nil, fmt.Errorf("failed to retrieve weekly progress: %w", err)
+        return models.WeeklyProgress{}, fmt.Errorf("failed to retrieve weekly progress: %w", err)
+    }
+    streak, err := repo.GetStreakSummary(ctx, userID)
+    if err != nil {
+        return models.WeeklyProgress{}, fmt.Errorf("failed to retrieve streak summary: %w", err)
+    }
+    goal, err := GetDailyGoal(ctx, userID)
+    if err != nil {
+        return models.WeeklyProgress{}, fmt.Errorf("failed to retrieve daily goal: %w", err)
     }
 
     progressMap := make(map[string]models.DailyProgress)
-    for _, doc := range docs {
-        var p models.DailyProgress
-        if err := doc.DataTo(&p); err != nil {
-            log.Printf("Failed to parse progress data for doc %s: %v", doc.Ref.ID, err)
-            continue
-        }
+    for _, p := range entries {
         progressMap[p.Date.Format("20060102")] = p
     }
 
     result := make([]models.DailyProgress, 0, 7)
     for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
-        docID := d.Format("20060102")
-        if p, exists := progressMap[docID]; exists {
-            result = append(result, p)
-        } else {
-            result = append(result, models.DailyProgress{
-                UserID:         userID,
-                Date:           d,
-                TherapyCount:   0,
-                StreakAchieved: false,
-            })
+        p, exists := progressMap[d.Format("20060102")]
+        if !exists {
+            p = models.DailyProgress{UserID: userID, Date: d}
         }
+        measureProgress(&p, goal)
+        result = append(result, p)
     }
 
     log.Printf("Retrieved weekly progress for userID: %s, entries: %d", userID, len(result))
-    return result, nil
+    return models.WeeklyProgress{Days: result, Streak: currentStreak(streak, endDate)}, nil
 }
 
-// GetMonthlyProgress retrieves the user's progress for a specific month and year.
+// GetMonthlyProgress retrieves the user's progress for a specific month and year. A day's status
+// is "streak" when it met its daily goal, "active" when some therapy was done and "inactive" otherwise.
 func GetMonthlyProgress(ctx context.Context, userID string, year, month int) ([]models.ProgressDetail, error) {
-    firestoreClient, err := GetFirestoreClient(ctx)
-    if err != nil {
-        return nil, fmt.Errorf("failed to connect to Firestore: %w", err)
-    }
-    defer firestoreClient.Close()
-
     startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
     endDate := startDate.AddDate(0, 1, -1)
 
-    docs, err := firestoreClient.Collection("users").Doc(userID).Collection("progress").
-        Where("date", ">=", startDate).
-        Where("date", "<=", endDate).
-        Documents(ctx).GetAll()
+    entries, err := repo.ListDailyProgress(ctx, userID, startDate, endDate)
     if err != nil {
         return nil, fmt.Errorf("failed to retrieve monthly progress: %w", err)
     }
+    goal, err := GetDailyGoal(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve daily goal: %w", err)
+    }
 
     progressMap := make(map[string]models.DailyProgress)
-    for _, doc := range docs {
-        var p models.DailyProgress
-        if err := doc.DataTo(&p); err != nil {
-            log.Printf("Failed to parse progress data for doc %s: %v", doc.Ref.ID, err)
-            continue
-        }
+    for _, p := range entries {
         progressMap[p.Date.Format("20060102")] = p
     }
 
     result := make([]models.ProgressDetail, 0, endDate.Day())
     for day := 1; day <= endDate.Day(); day++ {
         date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
-        docID := date.Format("20060102")
-        status := "inactive"
+        p, exists := progressMap[date.Format("20060102")]
+        if !exists {
+            p = models.DailyProgress{UserID: userID, Date: date}
+        }
+        measureProgress(&p, goal)
 
-        if p, exists := progressMap[docID]; exists {
-            if p.StreakAchieved {
-                status = "streak"
-            } else if p.TherapyCount > 0 {
-                status = "active"
-            }
+        status := "inactive"
+        if goalMet(p) {
+            status = "streak"
+        } else if p.TherapyCount > 0 {
+            status = "active"
         }
 
         result = append(result, models.ProgressDetail{
-            Date:   date,
-            Status: status,
+            Date:              date,
+            Status:            status,
+            Goal:              p.Goal,
+            Achieved:          p.Achieved,
+            CompletionPercent: p.CompletionPercent,
         })
     }
 
     log.Printf("Retrieved monthly progress for userID: %s, year: %d, month: %d, entries: %d", userID, year, month, len(result))
     return result, nil
-}
\ No newline at end of file
+}
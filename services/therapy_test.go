@@ -0,0 +1,194 @@
+package services
+
+import (
+    "context"
+    "maps"
+    "slices"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+func TestUpdateDailyProgress(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+    if _, err := UpdateDailyGoal(ctx, "user", "user", models.DailyGoal{Metric: models.GoalMetricCorrectAnswers, Target: 3}); err != nil {
+        t.Fatalf("UpdateDailyGoal() error = %v", err)
+    }
+
+    batch := []models.TherapyResult{
+        {Type: "visual", Category: "letter_recognition", CorrectAnswers: 1, TotalQuestions: 1},
+        {Type: "visual", Category: "letter_recognition", CorrectAnswers: 0, TotalQuestions: 1},
+        {Type: "visual", Category: "word_recognition", CorrectAnswers: 1, TotalQuestions: 1},
+        {Type: "auditory", Category: "phoneme", CorrectAnswers: 0, TotalQuestions: 1},
+    }
+    progress, err := UpdateDailyProgress(ctx, "user", batch, 90, time.UTC)
+    if err != nil {
+        t.Fatalf("UpdateDailyProgress() error = %v", err)
+    }
+    if progress.TherapyCount != 1 || progress.CorrectAnswers != 2 || progress.TotalQuestions != 4 || progress.SecondsSpent != 90 {
+        t.Fatalf("progress = %+v, want 1 session, 2 of 4 correct and 90 seconds", progress)
+    }
+    wantTypes := []models.ActivityBreakdown{
+        {Type: "visual", Sessions: 1, CorrectAnswers: 2, TotalQuestions: 3},
+        {Type: "auditory", Sessions: 1, CorrectAnswers: 0, TotalQuestions: 1},
+    }
+    if !slices.Equal(progress.Types, wantTypes) {
+        t.Errorf("types = %+v, want %+v", progress.Types, wantTypes)
+    }
+    wantCategories := []models.ActivityBreakdown{
+        {Type: "visual", Category: "letter_recognition", Sessions: 1, CorrectAnswers: 1, TotalQuestions: 2},
+        {Type: "visual", Category: "word_recognition", Sessions: 1, CorrectAnswers: 1, TotalQuestions: 1},
+        {Type: "auditory", Category: "phoneme", Sessions: 1, CorrectAnswers: 0, TotalQuestions: 1},
+    }
+    if !slices.Equal(progress.Categories, wantCategories) {
+        t.Errorf("categories = %+v, want %+v", progress.Categories, wantCategories)
+    }
+    if progress.StreakAchieved || progress.Achieved != 2 || progress.CompletionPercent != 66.67 {
+        t.Errorf("goal progress = achieved %d, %.2f%%, streak %v, want 2, 66.67%%, false", progress.Achieved, progress.CompletionPercent, progress.StreakAchieved)
+    }
+
+    progress, err = UpdateDailyProgress(ctx, "user", batch[:1], 30, time.UTC)
+    if err != nil {
+        t.Fatalf("UpdateDailyProgress() error = %v", err)
+    }
+    if progress.TherapyCount != 2 || !progress.StreakAchieved || progress.CompletionPercent != 100 {
+        t.Errorf("second batch: %d sessions, %.2f%%, streak %v, want 2, 100%%, true", progress.TherapyCount, progress.CompletionPercent, progress.StreakAchieved)
+    }
+
+    summary, err := GetProgressSummary(ctx, "user", time.UTC)
+    if err != nil {
+        t.Fatalf("GetProgressSummary() error = %v", err)
+    }
+    if summary.Streak.CurrentStreak != 1 || !summary.Streak.AchievedToday {
+        t.Errorf("streak = %+v, want current 1 achieved today", summary.Streak)
+    }
+    if want := map[string]int{"visual": 3, "auditory": 0}; !maps.Equal(summary.Streak.CorrectAnswers, want) {
+        t.Errorf("streak correct answers = %v, want %v", summary.Streak.CorrectAnswers, want)
+    }
+
+    if _, err := UpdateDailyProgress(ctx, "user", nil, 0, time.UTC); err == nil {
+        t.Error("UpdateDailyProgress() without results succeeded, want an error")
+    }
+}
+
+// seedDailyProgress stores progress for a date with the given correct answers and goal.
+func seedDailyProgress(t *testing.T, r *repository.MemoryRepository, date time.Time, correct int, goal models.DailyGoal) {
+    t.Helper()
+    _, _, err := r.UpdateDailyProgress(context.Background(), "user", date, func(progress *models.DailyProgress, streak *models.StreakSummary) error {
+        progress.TherapyCount = 1
+        progress.CorrectAnswers = correct
+        progress.TotalQuestions = correct + 1
+        progress.Goal = goal
+        measureProgress(progress, goal)
+        progress.StreakAchieved = goalMet(*progress)
+        return nil
+    })
+    if err != nil {
+        t.Fatalf("UpdateDailyProgress() error = %v", err)
+    }
+}
+
+func TestGetWeeklyProgress(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    goal := models.DailyGoal{Metric: models.GoalMetricCorrectAnswers, Target: 4}
+    if _, err := UpdateDailyGoal(ctx, "user", "user", goal); err != nil {
+        t.Fatalf("UpdateDailyGoal() error = %v", err)
+    }
+    today := localDate(time.Now(), time.UTC)
+    seedDailyProgress(t, r, today, 2, goal)
+    seedDailyProgress(t, r, today.AddDate(0, 0, -6), 5, goal)
+    seedDailyProgress(t, r, today.AddDate(0, 0, -7), 9, goal)
+
+    weekly, err := GetWeeklyProgress(ctx, "user", time.UTC)
+    if err != nil {
+        t.Fatalf("GetWeeklyProgress() error = %v", err)
+    }
+    if len(weekly.Days) != 7 {
+        t.Fatalf("weekly progress has %d days, want 7", len(weekly.Days))
+    }
+    if !weekly.Days[0].Date.Equal(today.AddDate(0, 0, -6)) || !weekly.Days[6].Date.Equal(today) {
+        t.Errorf("days run from %v to %v, want the 7 days ending %v", weekly.Days[0].Date, weekly.Days[6].Date, today)
+    }
+
+    var achieved []int
+    for _, day := range weekly.Days {
+        achieved = append(achieved, day.Achieved)
+        if day.Goal.Target != goal.Target {
+            t.Errorf("day %v goal target = %d, want %d", day.Date, day.Goal.Target, goal.Target)
+        }
+    }
+    if want := []int{5, 0, 0, 0, 0, 0, 2}; !slices.Equal(achieved, want) {
+        t.Errorf("achieved = %v, want %v", achieved, want)
+    }
+    if weekly.Days[0].CompletionPercent != 100 || weekly.Days[6].CompletionPercent != 50 {
+        t.Errorf("completion = %.2f%% and %.2f%%, want 100%% and 50%%", weekly.Days[0].CompletionPercent, weekly.Days[6].CompletionPercent)
+    }
+}
+
+func TestGetMonthlyProgress(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    goal := models.DailyGoal{Metric: models.GoalMetricCorrectAnswers, Target: 4}
+    if _, err := UpdateDailyGoal(ctx, "user", "user", goal); err != nil {
+        t.Fatalf("UpdateDailyGoal() error = %v", err)
+    }
+    seedDailyProgress(t, r, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 4, goal)
+    seedDailyProgress(t, r, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), 1, goal)
+    seedDailyProgress(t, r, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 8, goal)
+
+    // A day keeps the goal in effect when it was recorded.
+    easier := models.DailyGoal{Metric: models.GoalMetricCorrectAnswers, Target: 2}
+    seedDailyProgress(t, r, time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), 2, easier)
+
+    days, err := GetMonthlyProgress(ctx, "user", 2024, 2)
+    if err != nil {
+        t.Fatalf("GetMonthlyProgress() error = %v", err)
+    }
+    if len(days) != 29 {
+        t.Fatalf("February 2024 has %d days, want 29", len(days))
+    }
+    statuses := map[int]string{1: "streak", 2: "inactive", 10: "streak", 29: "active"}
+    for day, want := range statuses {
+        if got := days[day-1].Status; got != want {
+            t.Errorf("day %d status = %q, want %q", day, got, want)
+        }
+    }
+    if days[9].Goal.Target != easier.Target || days[1].Goal.Target != goal.Target {
+        t.Errorf("goal targets = %d and %d, want the recorded %d and the current %d", days[9].Goal.Target, days[1].Goal.Target, easier.Target, goal.Target)
+    }
+    if days[28].Achieved != 1 || days[28].CompletionPercent != 25 {
+        t.Errorf("last day = achieved %d, %.2f%%, want 1 and 25%%", days[28].Achieved, days[28].CompletionPercent)
+    }
+}
+
+func TestUpdateDailyProgressBackfillsCorrectTotals(t *testing.T) {
+    r := useMemoryRepository(t)
+    ctx := context.Background()
+    // Progress recorded before the streak summary kept running totals.
+    _, _, err := r.UpdateDailyProgress(ctx, "user", localDate(time.Now(), time.UTC).AddDate(0, 0, -3), func(progress *models.DailyProgress, streak *models.StreakSummary) error {
+        progress.Types = []models.ActivityBreakdown{{Type: "auditory", CorrectAnswers: 7}}
+        return nil
+    })
+    if err != nil {
+        t.Fatalf("UpdateDailyProgress() error = %v", err)
+    }
+
+    batch := []models.TherapyResult{{Type: "auditory", Category: "phoneme", CorrectAnswers: 1, TotalQuestions: 1}}
+    for i := 0; i < 2; i++ {
+        if _, err := UpdateDailyProgress(ctx, "user", batch, 0, time.UTC); err != nil {
+            t.Fatalf("UpdateDailyProgress() error = %v", err)
+        }
+    }
+
+    streak, err := r.GetStreakSummary(ctx, "user")
+    if err != nil {
+        t.Fatalf("GetStreakSummary() error = %v", err)
+    }
+    if got := streak.CorrectAnswers["auditory"]; got != 9 {
+        t.Errorf("auditory correct answers = %d, want 7 backfilled plus 2 new", got)
+    }
+}
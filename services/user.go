@@ -0,0 +1,88 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+    _ "time/tzdata"
+
+    "github.com/dzuura/neurodyx-be/models"
+    "github.com/dzuura/neurodyx-be/repository"
+)
+
+// ErrInvalidTimezone is returned for a timezone that is not a known IANA zone name.
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// GetUser retrieves a user account by ID.
+func GetUser(ctx context.Context, userID string) (models.User, error) {
+    return repo.GetUser(ctx, userID)
+}
+
+// SaveUser stores a user's account fields.
+func SaveUser(ctx context.Context, user models.User) error {
+    if err := repo.SaveUser(ctx, user); err != nil {
+        return fmt.Errorf("failed to save user: %w", err)
+    }
+    return nil
+}
+
+// GetUserSettings retrieves the user's settings, returning defaults for users without any.
+func GetUserSettings(ctx context.Context, userID string) (models.UserSettings, error) {
+    user, err := repo.GetUser(ctx, userID)
+    if errors.Is(err, repository.ErrNotFound) {
+        return models.UserSettings{}, nil
+    }
+    if err != nil {
+        return models.UserSettings{}, fmt.Errorf("failed to retrieve user data: %w", err)
+    }
+    return user.Settings, nil
+}
+
+// UpdateUserSettings validates and stores the user's settings.
+func UpdateUserSettings(ctx context.Context, userID string, settings models.UserSettings) (models.UserSettings, error) {
+    if settings.Timezone != "" {
+        if _, err := loadLocation(settings.Timezone); err != nil {
+            return models.UserSettings{}, err
+        }
+    }
+    if err := repo.SaveUserSettings(ctx, userID, settings); err != nil {
+        return models.UserSettings{}, fmt.Errorf("failed to save user settings: %w", err)
+    }
+
+    log.Printf("Updated settings for userID: %s, timezone: %s", userID, settings.Timezone)
+    return settings, nil
+}
+
+// UserLocation resolves the timezone used for the user's daily progress. A non-empty override
+// takes precedence over the timezone stored in the user's settings; UTC is used when neither is set.
+func UserLocation(ctx context.Context, userID, override string) (*time.Location, error) {
+    if override != "" {
+        return loadLocation(override)
+    }
+    settings, err := GetUserSettings(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    if settings.Timezone == "" {
+        return time.UTC, nil
+    }
+    return loadLocation(settings.Timezone)
+}
+
+// loadLocation loads an IANA timezone by name.
+func loadLocation(name string) (*time.Location, error) {
+    loc, err := time.LoadLocation(name)
+    if err != nil || name == "Local" {
+        return nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, name)
+    }
+    return loc, nil
+}
+
+// localDate returns the calendar date of t in loc, as midnight UTC. Progress documents are keyed
+// by these date-only values.
+func localDate(t time.Time, loc *time.Location) time.Time {
+    t = t.In(loc)
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestLocalDate(t *testing.T) {
+    jakarta, err := time.LoadLocation("Asia/Jakarta")
+    if err != nil {
+        t.Skipf("timezone data unavailable: %v", err)
+    }
+    newYork, err := time.LoadLocation("America/New_York")
+    if err != nil {
+        t.Skipf("timezone data unavailable: %v", err)
+    }
+
+    tests := []struct {
+        name string
+        at   time.Time
+        loc  *time.Location
+        want string
+    }{
+        {"utc", time.Date(2025, 4, 10, 23, 30, 0, 0, time.UTC), time.UTC, "2025-04-10"},
+        {"ahead of utc crosses midnight", time.Date(2025, 4, 10, 18, 0, 0, 0, time.UTC), jakarta, "2025-04-11"},
+        {"ahead of utc before midnight", time.Date(2025, 4, 10, 16, 59, 0, 0, time.UTC), jakarta, "2025-04-10"},
+        {"behind utc", time.Date(2025, 4, 11, 2, 0, 0, 0, time.UTC), newYork, "2025-04-10"},
+        {"month boundary", time.Date(2025, 4, 30, 20, 0, 0, 0, time.UTC), jakarta, "2025-05-01"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := localDate(tt.at, tt.loc)
+            if got.Format("2006-01-02") != tt.want || got.Location() != time.UTC || got.Hour() != 0 {
+                t.Errorf("localDate() = %v, want midnight UTC on %s", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestUserLocation(t *testing.T) {
+    useMemoryRepository(t)
+    ctx := context.Background()
+
+    loc, err := UserLocation(ctx, "user", "")
+    if err != nil || loc != time.UTC {
+        t.Fatalf("UserLocation() without settings = %v, %v, want UTC", loc, err)
+    }
+
+    if _, err := UpdateUserSettings(ctx, "user", models.UserSettings{Timezone: "Asia/Jakarta"}); err != nil {
+        t.Fatalf("UpdateUserSettings() error = %v", err)
+    }
+    loc, err = UserLocation(ctx, "user", "")
+    if err != nil || loc.String() != "Asia/Jakarta" {
+        t.Errorf("UserLocation() = %v, %v, want the stored Asia/Jakarta", loc, err)
+    }
+    loc, err = UserLocation(ctx, "user", "Europe/London")
+    if err != nil || loc.String() != "Europe/London" {
+        t.Errorf("UserLocation() with override = %v, %v, want Europe/London", loc, err)
+    }
+
+    for _, name := range []string{"Mars/Olympus", "Local"} {
+        if _, err := UserLocation(ctx, "user", name); !errors.Is(err, ErrInvalidTimezone) {
+            t.Errorf("UserLocation(%q) error = %v, want ErrInvalidTimezone", name, err)
+        }
+    }
+}
@@ -1,45 +1,403 @@
 package services
 
-// ValidateStringMatch checks if the user's string answer matches the correct answer.
+import (
+    "errors"
+    "fmt"
+    "sort"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+// ErrInvalidQuestion is returned when a question does not provide the fields its answer format needs.
+var ErrInvalidQuestion = errors.New("invalid question")
+
+// AnswerValidator scores answers of a single answer format.
+type AnswerValidator interface {
+    // CheckAnswerKey reports an error if the key lacks the fields this validator needs.
+    CheckAnswerKey(key models.AnswerKey) error
+    // Validate scores the submitted answer against the key.
+    Validate(answer interface{}, key models.AnswerKey) models.AnswerScore
+}
+
+// answerValidators holds the registered validators keyed by answer format.
+var answerValidators = map[string]AnswerValidator{}
+
+// RegisterAnswerValidator makes a validator available under the given answer format.
+// Registering the same format twice replaces the earlier validator.
+func RegisterAnswerValidator(format string, v AnswerValidator) {
+    answerValidators[format] = v
+}
+
+func init() {
+    RegisterAnswerValidator(models.AnswerFormatExactString, exactStringValidator{})
+    RegisterAnswerValidator(models.AnswerFormatSequence, sequenceValidator{})
+    RegisterAnswerValidator(models.AnswerFormatPairs, pairsValidator{})
+    RegisterAnswerValidator(models.AnswerFormatSingleChoice, singleChoiceValidator{})
+    RegisterAnswerValidator(models.AnswerFormatMultiSelect, multiSelectValidator{})
+}
+
+// AnswerFormats returns the names of all registered answer formats.
+func AnswerFormats() []string {
+    formats := make([]string, 0, len(answerValidators))
+    for format := range answerValidators {
+        formats = append(formats, format)
+    }
+    sort.Strings(formats)
+    return formats
+}
+
+// inferAnswerFormat picks an answer format from the answer fields of questions
+// stored before formats were declared. Questions with options are only single choice when
+// correctAnswer is one of them; other questions with a correctAnswer, such as the legacy
+// *_by_touch categories, keep the string matching they were scored with before.
+func inferAnswerFormat(key models.AnswerKey) string {
+    switch {
+    case len(key.CorrectPairs) > 0:
+        return models.AnswerFormatPairs
+    case len(key.CorrectSequence) > 0:
+        return models.AnswerFormatSequence
+    case len(key.CorrectOptions) > 0:
+        return models.AnswerFormatMultiSelect
+    case len(key.Options) > 0 && containsString(key.Options, key.CorrectAnswer):
+        return models.AnswerFormatSingleChoice
+    case key.CorrectAnswer != "":
+        return models.AnswerFormatExactString
+    }
+    return ""
+}
+
+// resolveAnswerValidator returns the declared (or inferred) answer format and its validator.
+func resolveAnswerValidator(format string, key models.AnswerKey) (string, AnswerValidator, error) {
+    if format == "" {
+        format = inferAnswerFormat(key)
+        if format == "" {
+            return "", nil, fmt.Errorf("%w: answerFormat is required", ErrInvalidQuestion)
+        }
+    }
+    v, ok := answerValidators[format]
+    if !ok {
+        return "", nil, fmt.Errorf("%w: unknown answerFormat %q", ErrInvalidQuestion, format)
+    }
+    return format, v, nil
+}
+
+// checkAnswerKey resolves the question's answer format and verifies the question has the
+// fields its validator needs. It returns the resolved format.
+func checkAnswerKey(format string, key models.AnswerKey) (string, error) {
+    format, v, err := resolveAnswerValidator(format, key)
+    if err != nil {
+        return "", err
+    }
+    if err := v.CheckAnswerKey(key); err != nil {
+        return "", fmt.Errorf("%w: %s: %v", ErrInvalidQuestion, format, err)
+    }
+    return format, nil
+}
+
+// validateAnswer scores an answer with the validator of the question's answer format.
+func validateAnswer(format string, key models.AnswerKey, answer interface{}) (models.AnswerScore, error) {
+    _, v, err := resolveAnswerValidator(format, key)
+    if err != nil {
+        return models.AnswerScore{}, err
+    }
+    return v.Validate(answer, key), nil
+}
+
+// allOrNothing converts a pass/fail check into a full or zero score.
+func allOrNothing(correct bool) models.AnswerScore {
+    if correct {
+        return models.AnswerScore{Correct: true, Score: 1}
+    }
+    return models.AnswerScore{}
+}
+
+// assessmentAnswerKey extracts the answer fields of an assessment question.
+func assessmentAnswerKey(q models.AssessmentQuestion) models.AnswerKey {
+    return models.AnswerKey{
+        Options:         q.Options,
+        CorrectAnswer:   q.CorrectAnswer,
+        CorrectSequence: q.CorrectSequence,
+        CorrectPairs:    q.CorrectPairs,
+        CorrectOptions:  q.CorrectOptions,
+        Matching:        q.Matching,
+    }
+}
+
+// therapyAnswerKey extracts the answer fields of a therapy question.
+func therapyAnswerKey(q models.TherapyQuestion) models.AnswerKey {
+    return models.AnswerKey{
+        Options:         q.Options,
+        CorrectAnswer:   q.CorrectAnswer,
+        CorrectSequence: q.CorrectSequence,
+        CorrectPairs:    q.CorrectPairs,
+        CorrectOptions:  q.CorrectOptions,
+        Matching:        q.Matching,
+    }
+}
+
+// exactStringValidator accepts a string answer matching correctAnswer after normalization,
+// within the question's edit-distance or phonetic tolerance.
+type exactStringValidator struct{}
+
+func (exactStringValidator) CheckAnswerKey(key models.AnswerKey) error {
+    if key.CorrectAnswer == "" {
+        return errors.New("correctAnswer is required")
+    }
+    return checkMatchOptions(key.Matching)
+}
+
+func (exactStringValidator) Validate(answer interface{}, key models.AnswerKey) models.AnswerScore {
+    answerStr, ok := answer.(string)
+    if !ok {
+        return models.AnswerScore{}
+    }
+    match := MatchString(answerStr, key.CorrectAnswer, key.Matching)
+    score := allOrNothing(match.Quality != models.MatchNone)
+    score.Match = &match
+    return score
+}
+
+// sequenceValidator accepts a list of strings equal to correctSequence in order.
+type sequenceValidator struct{}
+
+func (sequenceValidator) CheckAnswerKey(key models.AnswerKey) error {
+    if len(key.CorrectSequence) == 0 {
+        return errors.New("correctSequence is required")
+    }
+    return nil
+}
+
+func (sequenceValidator) Validate(answer interface{}, key models.AnswerKey) models.AnswerScore {
+    seq, _ := stringList(answer)
+    return ScoreSequence(seq, key.CorrectSequence)
+}
+
+// pairsValidator accepts a list of {"left", "right"} objects matching correctPairs.
+type pairsValidator struct{}
+
+func (pairsValidator) CheckAnswerKey(key models.AnswerKey) error {
+    if len(key.CorrectPairs) == 0 {
+        return errors.New("correctPairs is required")
+    }
+    return nil
+}
+
+func (pairsValidator) Validate(answer interface{}, key models.AnswerKey) models.AnswerScore {
+    pairs, _ := answerPairs(answer)
+    return ScorePairs(pairs, key.CorrectPairs)
+}
+
+// singleChoiceValidator accepts the one option equal to correctAnswer.
+type singleChoiceValidator struct{}
+
+func (singleChoiceValidator) CheckAnswerKey(key models.AnswerKey) error {
+    if len(key.Options) == 0 {
+        return errors.New("options are required")
+    }
+    if !containsString(key.Options, key.CorrectAnswer) {
+        return errors.New("correctAnswer must be one of the options")
+    }
+    return nil
+}
+
+func (singleChoiceValidator) Validate(answer interface{}, key models.AnswerKey) models.AnswerScore {
+    answerStr, ok := answer.(string)
+    return allOrNothing(ok && answerStr == key.CorrectAnswer)
+}
+
+// multiSelectValidator accepts exactly the set of correctOptions, in any order.
+type multiSelectValidator struct{}
+
+func (multiSelectValidator) CheckAnswerKey(key models.AnswerKey) error {
+    if len(key.Options) == 0 {
+        return errors.New("options are required")
+    }
+    if len(key.CorrectOptions) == 0 {
+        return errors.New("correctOptions is required")
+    }
+    for _, opt := range key.CorrectOptions {
+        if !containsString(key.Options, opt) {
+            return fmt.Errorf("correctOptions value %q is not one of the options", opt)
+        }
+    }
+    return nil
+}
+
+func (multiSelectValidator) Validate(answer interface{}, key models.AnswerKey) models.AnswerScore {
+    selected, _ := stringList(answer)
+    return ScoreSelection(selected, key.CorrectOptions)
+}
+
+// stringList converts a decoded JSON array into a slice of strings.
+func stringList(answer interface{}) ([]string, bool) {
+    items, ok := answer.([]interface{})
+    if !ok {
+        return nil, false
+    }
+    values := make([]string, len(items))
+    for i, item := range items {
+        value, ok := item.(string)
+        if !ok {
+            return nil, false
+        }
+        values[i] = value
+    }
+    return values, true
+}
+
+// answerPairs converts a decoded JSON array of {"left", "right"} objects into pairs.
+func answerPairs(answer interface{}) ([]map[string]string, bool) {
+    items, ok := answer.([]interface{})
+    if !ok {
+        return nil, false
+    }
+    pairs := make([]map[string]string, len(items))
+    for i, item := range items {
+        pairMap, ok := item.(map[string]interface{})
+        if !ok {
+            return nil, false
+        }
+        left, leftOk := pairMap["left"].(string)
+        right, rightOk := pairMap["right"].(string)
+        if !leftOk || !rightOk {
+            return nil, false
+        }
+        pairs[i] = map[string]string{
+            "left":  left,
+            "right": right,
+        }
+    }
+    return pairs, true
+}
+
+// containsString reports whether values contains target.
+func containsString(values []string, target string) bool {
+    for _, value := range values {
+        if value == target {
+            return true
+        }
+    }
+    return false
+}
+
+// ValidateStringMatch checks if the user's string answer matches the correct answer
+// once both are normalized with the default normalizers.
 func ValidateStringMatch(userAnswer, correctAnswer string) bool {
-    return userAnswer == correctAnswer
+    return MatchString(userAnswer, correctAnswer, nil).Quality != models.MatchNone
 }
 
 // ValidateSequence checks if the user's sequence matches the correct sequence exactly.
 func ValidateSequence(userSequence, correctSequence []string) bool {
-    if len(userSequence) != len(correctSequence) {
-        return false
-    }
-    for i, char := range userSequence {
-        if char != correctSequence[i] {
-            return false
+    return ScoreSequence(userSequence, correctSequence).Correct
+}
+
+// ScoreSequence credits each position of the user's sequence that matches the correct sequence.
+// Extra items in the user's sequence count against the score.
+func ScoreSequence(userSequence, correctSequence []string) models.AnswerScore {
+    items := make([]models.ItemResult, len(correctSequence))
+    matched := 0
+    for i, expected := range correctSequence {
+        item := models.ItemResult{Index: i, Expected: expected}
+        if i < len(userSequence) {
+            item.Answer = userSequence[i]
+            item.Correct = item.Answer == expected
+        }
+        if item.Correct {
+            matched++
         }
+        items[i] = item
+    }
+
+    return models.AnswerScore{
+        Correct: matched == len(correctSequence) && len(userSequence) == len(correctSequence),
+        Score:   fraction(matched, max(len(correctSequence), len(userSequence))),
+        Items:   items,
     }
-    return true
 }
 
 // ValidatePairs checks if the user's pairs match the correct pairs.
 func ValidatePairs(userPairs []map[string]string, correctPairs map[string]string) bool {
+    return ScorePairs(userPairs, correctPairs).Correct
+}
+
+// ScorePairs credits each correct pair the user matched. Pairs for left items that are not
+// part of the correct pairs count against the score.
+func ScorePairs(userPairs []map[string]string, correctPairs map[string]string) models.AnswerScore {
     userPairMap := make(map[string]string)
     for _, pair := range userPairs {
         left, leftOk := pair["left"]
         right, rightOk := pair["right"]
         if !leftOk || !rightOk {
-            return false
+            continue
         }
         userPairMap[left] = right
     }
 
-    if len(userPairMap) != len(correctPairs) {
-        return false
+    lefts := make([]string, 0, len(correctPairs))
+    for left := range correctPairs {
+        lefts = append(lefts, left)
     }
+    sort.Strings(lefts)
 
-    for left, correctRight := range correctPairs {
+    items := make([]models.ItemResult, len(lefts))
+    matched := 0
+    for i, left := range lefts {
         userRight, exists := userPairMap[left]
-        if !exists || userRight != correctRight {
-            return false
+        items[i] = models.ItemResult{
+            Index:    i,
+            Left:     left,
+            Expected: correctPairs[left],
+            Answer:   userRight,
+            Correct:  exists && userRight == correctPairs[left],
+        }
+        if items[i].Correct {
+            matched++
+        }
+    }
+
+    extra := 0
+    for left := range userPairMap {
+        if _, ok := correctPairs[left]; !ok {
+            extra++
         }
     }
 
-    return true
-}
\ No newline at end of file
+    return models.AnswerScore{
+        Correct: matched == len(correctPairs) && extra == 0,
+        Score:   fraction(matched, len(correctPairs)+extra),
+        Items:   items,
+    }
+}
+
+// ScoreSelection credits each correct option the user selected. Incorrect selections count
+// against the score.
+func ScoreSelection(userSelection, correctOptions []string) models.AnswerScore {
+    selected := make(map[string]bool, len(userSelection))
+    for _, opt := range userSelection {
+        selected[opt] = true
+    }
+    correct := make(map[string]bool, len(correctOptions))
+    for _, opt := range correctOptions {
+        correct[opt] = true
+    }
+
+    matched := 0
+    for opt := range correct {
+        if selected[opt] {
+            matched++
+        }
+    }
+
+    return models.AnswerScore{
+        Correct: matched == len(correct) && len(selected) == len(correct),
+        Score:   fraction(matched, max(len(correct), len(selected))),
+    }
+}
+
+// fraction returns matched/total, or 0 when total is 0.
+func fraction(matched, total int) float64 {
+    if total == 0 {
+        return 0
+    }
+    return float64(matched) / float64(total)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+    "errors"
+    "testing"
+
+    "github.com/dzuura/neurodyx-be/models"
+)
+
+func TestInferAnswerFormat(t *testing.T) {
+    tests := []struct {
+        name string
+        key  models.AnswerKey
+        want string
+    }{
+        {"pairs", models.AnswerKey{CorrectPairs: map[string]string{"a": "1"}}, models.AnswerFormatPairs},
+        {"sequence", models.AnswerKey{CorrectSequence: []string{"a", "b"}}, models.AnswerFormatSequence},
+        {"multi select", models.AnswerKey{Options: []string{"a", "b"}, CorrectOptions: []string{"a"}}, models.AnswerFormatMultiSelect},
+        {"single choice", models.AnswerKey{Options: []string{"a", "b"}, CorrectAnswer: "b"}, models.AnswerFormatSingleChoice},
+        {"answer outside options", models.AnswerKey{Options: []string{"a", "b"}, CorrectAnswer: "ba"}, models.AnswerFormatExactString},
+        {"exact string", models.AnswerKey{CorrectAnswer: "m"}, models.AnswerFormatExactString},
+        {"no answer fields", models.AnswerKey{}, ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := inferAnswerFormat(tt.key); got != tt.want {
+                t.Errorf("inferAnswerFormat() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestCheckAnswerKey(t *testing.T) {
+    tests := []struct {
+        name    string
+        format  string
+        key     models.AnswerKey
+        want    string
+        wantErr bool
+    }{
+        {"inferred format", "", models.AnswerKey{CorrectAnswer: "m"}, models.AnswerFormatExactString, false},
+        {"missing fields", "", models.AnswerKey{}, "", true},
+        {"unknown format", "essay", models.AnswerKey{CorrectAnswer: "m"}, "", true},
+        {"single choice outside options", models.AnswerFormatSingleChoice, models.AnswerKey{Options: []string{"a"}, CorrectAnswer: "b"}, "", true},
+        {"multi select outside options", models.AnswerFormatMultiSelect, models.AnswerKey{Options: []string{"a"}, CorrectOptions: []string{"a", "c"}}, "", true},
+        {"sequence without items", models.AnswerFormatSequence, models.AnswerKey{}, "", true},
+        {"unknown normalizer", models.AnswerFormatExactString, models.AnswerKey{CorrectAnswer: "m", Matching: &models.MatchOptions{Normalizers: []string{"shout"}}}, "", true},
+        {"edit distance too large", models.AnswerFormatExactString, models.AnswerKey{CorrectAnswer: "m", Matching: &models.MatchOptions{MaxEditDistance: maxAllowedEditDistance + 1}}, "", true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := checkAnswerKey(tt.format, tt.key)
+            if tt.wantErr {
+                if !errors.Is(err, ErrInvalidQuestion) {
+                    t.Fatalf("checkAnswerKey() error = %v, want ErrInvalidQuestion", err)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatalf("checkAnswerKey() error = %v", err)
+            }
+            if got != tt.want {
+                t.Errorf("checkAnswerKey() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestValidateAnswer(t *testing.T) {
+    tests := []struct {
+        name        string
+        format      string
+        key         models.AnswerKey
+        answer      interface{}
+        wantCorrect bool
+        wantScore   float64
+    }{
+        {"single choice", models.AnswerFormatSingleChoice, models.AnswerKey{Options: []string{"a", "b"}, CorrectAnswer: "b"}, "b", true, 1},
+        {"single choice wrong", models.AnswerFormatSingleChoice, models.AnswerKey{Options: []string{"a", "b"}, CorrectAnswer: "b"}, "a", false, 0},
+        {"single choice wrong type", models.AnswerFormatSingleChoice, models.AnswerKey{Options: []string{"a", "b"}, CorrectAnswer: "b"}, 1.0, false, 0},
+        {"multi select any order", models.AnswerFormatMultiSelect, models.AnswerKey{Options: []string{"a", "b", "c"}, CorrectOptions: []string{"a", "c"}}, []interface{}{"c", "a"}, true, 1},
+        {"multi select extra option", models.AnswerFormatMultiSelect, models.AnswerKey{Options: []string{"a", "b", "c"}, CorrectOptions: []string{"a", "c"}}, []interface{}{"a", "b", "c"}, false, 2.0 / 3},
+        {"sequence half right", models.AnswerFormatSequence, models.AnswerKey{CorrectSequence: []string{"a", "b", "c", "d"}}, []interface{}{"a", "b", "d", "c"}, false, 0.5},
+        {"pairs", models.AnswerFormatPairs, models.AnswerKey{CorrectPairs: map[string]string{"a": "1"}}, []interface{}{map[string]interface{}{"left": "a", "right": "1"}}, true, 1},
+        {"malformed pairs", models.AnswerFormatPairs, models.AnswerKey{CorrectPairs: map[string]string{"a": "1"}}, "a=1", false, 0},
+        {"string normalized", models.AnswerFormatExactString, models.AnswerKey{CorrectAnswer: "Café"}, " cafe! ", true, 1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := validateAnswer(tt.format, tt.key, tt.answer)
+            if err != nil {
+                t.Fatalf("validateAnswer() error = %v", err)
+            }
+            if got.Correct != tt.wantCorrect || !floatEqual(got.Score, tt.wantScore) {
+                t.Errorf("validateAnswer() = correct %v, score %v, want correct %v, score %v", got.Correct, got.Score, tt.wantCorrect, tt.wantScore)
+            }
+        })
+    }
+    if _, err := validateAnswer("", models.AnswerKey{}, "m"); !errors.Is(err, ErrInvalidQuestion) {
+        t.Errorf("validateAnswer() without a format error = %v, want ErrInvalidQuestion", err)
+    }
+}
+
+func TestScoreSequence(t *testing.T) {
+    tests := []struct {
+        name        string
+        answer      []string
+        wantCorrect bool
+        wantScore   float64
+        wantItems   []bool
+    }{
+        {"all correct", []string{"a", "b", "c"}, true, 1, []bool{true, true, true}},
+        {"one swapped", []string{"a", "c", "b"}, false, 1.0 / 3, []bool{true, false, false}},
+        {"too short", []string{"a"}, false, 1.0 / 3, []bool{true, false, false}},
+        {"extra item", []string{"a", "b", "c", "d"}, false, 0.75, []bool{true, true, true}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := ScoreSequence(tt.answer, []string{"a", "b", "c"})
+            if got.Correct != tt.wantCorrect || !floatEqual(got.Score, tt.wantScore) {
+                t.Errorf("ScoreSequence() = correct %v, score %v, want correct %v, score %v", got.Correct, got.Score, tt.wantCorrect, tt.wantScore)
+            }
+            if len(got.Items) != len(tt.wantItems) {
+                t.Fatalf("ScoreSequence() returned %d items, want %d", len(got.Items), len(tt.wantItems))
+            }
+            for i, item := range got.Items {
+                if item.Correct != tt.wantItems[i] {
+                    t.Errorf("item %d correct = %v, want %v", i, item.Correct, tt.wantItems[i])
+                }
+            }
+        })
+    }
+}
+
+func TestScorePairs(t *testing.T) {
+    correct := map[string]string{"a": "1", "b": "2"}
+    tests := []struct {
+        name        string
+        answer      []map[string]string
+        wantCorrect bool
+        wantScore   float64
+    }{
+        {"all correct", []map[string]string{{"left": "a", "right": "1"}, {"left": "b", "right": "2"}}, true, 1},
+        {"one wrong", []map[string]string{{"left": "a", "right": "1"}, {"left": "b", "right": "1"}}, false, 0.5},
+        {"unknown left item", []map[string]string{{"left": "a", "right": "1"}, {"left": "b", "right": "2"}, {"left": "c", "right": "3"}}, false, 2.0 / 3},
+        {"empty", nil, false, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := ScorePairs(tt.answer, correct)
+            if got.Correct != tt.wantCorrect || !floatEqual(got.Score, tt.wantScore) {
+                t.Errorf("ScorePairs() = correct %v, score %v, want correct %v, score %v", got.Correct, got.Score, tt.wantCorrect, tt.wantScore)
+            }
+        })
+    }
+}
+
+func TestMatchString(t *testing.T) {
+    tests := []struct {
+        name         string
+        answer       string
+        correct      string
+        opts         *models.MatchOptions
+        wantQuality  string
+        wantDistance int
+    }{
+        {"exact", "kucing", "kucing", nil, models.MatchExact, 0},
+        {"case and punctuation", "Kucing!", "kucing", nil, models.MatchNormalized, 0},
+        {"diacritics", "café", "cafe", nil, models.MatchNormalized, 0},
+        {"typo without tolerance", "kuching", "kucing", nil, models.MatchNone, 1},
+        {"typo within tolerance", "kuching", "kucing", &models.MatchOptions{MaxEditDistance: 1}, models.MatchFuzzy, 1},
+        {"typo beyond tolerance", "kuchinq", "kucing", &models.MatchOptions{MaxEditDistance: 1}, models.MatchNone, 2},
+        {"sounds alike", "rupert", "robert", &models.MatchOptions{Phonetic: true}, models.MatchPhonetic, 2},
+        {"case kept", "Kucing", "kucing", &models.MatchOptions{Normalizers: []string{models.NormalizeNone}}, models.MatchNone, 1},
+        {"empty answer", "", "cat", &models.MatchOptions{MaxEditDistance: 3}, models.MatchNone, 3},
+        {"punctuation only", "!!!", "cat", &models.MatchOptions{MaxEditDistance: 3, Phonetic: true}, models.MatchNone, 3},
+        {"every letter replaced", "dog", "cat", &models.MatchOptions{MaxEditDistance: 3}, models.MatchNone, 3},
+        {"tolerance capped below length", "ox", "ab", &models.MatchOptions{MaxEditDistance: 2}, models.MatchNone, 2},
+        {"short answer within cap", "cot", "cat", &models.MatchOptions{MaxEditDistance: 3}, models.MatchFuzzy, 1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := MatchString(tt.answer, tt.correct, tt.opts)
+            if got.Quality != tt.wantQuality || got.Distance != tt.wantDistance {
+                t.Errorf("MatchString() = %s at distance %d, want %s at distance %d", got.Quality, got.Distance, tt.wantQuality, tt.wantDistance)
+            }
+        })
+    }
+}
+
+// floatEqual reports whether two scores are equal within rounding error.
+func floatEqual(a, b float64) bool {
+    const epsilon = 1e-9
+    return a-b < epsilon && b-a < epsilon
+}